@@ -0,0 +1,2219 @@
+// Code generated by cmd/openapi-gen from openapiv1.yaml. DO NOT EDIT.
+
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// Nullable поле JSON Merge Patch: различает «не передано» (Set == false),
+// «передан null» (Null == true) и переданное значение
+type Nullable[T any] struct {
+	Set   bool
+	Null  bool
+	Value T
+}
+
+// UnmarshalJSON вызывается только для присутствующих в JSON ключей
+func (n *Nullable[T]) UnmarshalJSON(data []byte) error {
+	n.Set = true
+	if string(data) == "null" {
+		n.Null = true
+		return nil
+	}
+	return json.Unmarshal(data, &n.Value)
+}
+
+// MarshalJSON кодирует непереданное поле и null как null
+func (n Nullable[T]) MarshalJSON() ([]byte, error) {
+	if !n.Set || n.Null {
+		return []byte("null"), nil
+	}
+	return json.Marshal(n.Value)
+}
+
+// AddAssigneeRequest схема из спецификации
+type AddAssigneeRequest struct {
+	// id пользователя, назначаемого исполнителем
+	UserID int `json:"user_id"`
+}
+
+// AddDependencyRequest схема из спецификации
+type AddDependencyRequest struct {
+	DependentTaskID uuid.UUID     `json:"dependent_task_id"`
+	Relation        *TaskRelation `json:"relation,omitempty"`
+}
+
+// AddWatcherRequest схема из спецификации
+type AddWatcherRequest struct {
+	// id пользователя-наблюдателя; без поля — текущий пользователь
+	UserID *int `json:"user_id,omitempty"`
+}
+
+// ArchiveRequest задачи для архивации или возврата из архива
+type ArchiveRequest struct {
+	Ids []uuid.UUID `json:"ids"`
+}
+
+// ArchiveResult задачи, состояние которых изменилось
+type ArchiveResult struct {
+	// Задачи, уже находившиеся в нужном состоянии, отсутствующие или в корзине, пропускаются
+	Ids []uuid.UUID `json:"ids"`
+}
+
+// AssigneeCount схема из спецификации
+type AssigneeCount struct {
+	Assignee *UserSummary `json:"assignee,omitempty"`
+	Count    int          `json:"count"`
+}
+
+// AuthTokens схема из спецификации
+type AuthTokens struct {
+	// Refresh токен (не возвращается при обновлении)
+	RefreshToken *string `json:"refresh_token,omitempty"`
+	// JWT access токен
+	Token string `json:"token"`
+}
+
+// Board доска (проект) со своим упорядоченным набором колонок
+type Board struct {
+	// Колонки в порядке position
+	Columns     []BoardColumn `json:"columns"`
+	CreatedAt   time.Time     `json:"created_at"`
+	Description string        `json:"description"`
+	ID          uuid.UUID     `json:"id"`
+	// Доска по умолчанию для задач без board_id; её нельзя удалить
+	IsDefault bool      `json:"is_default"`
+	Name      string    `json:"name"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// BoardColumn схема из спецификации
+type BoardColumn struct {
+	BoardID uuid.UUID `json:"board_id"`
+	ID      uuid.UUID `json:"id"`
+	// Неизменяемый ключ колонки, он же kanban_space задач в ней
+	Key  string `json:"key"`
+	Name string `json:"name"`
+	// Порядковый номер колонки на доске, с нуля
+	Position int `json:"position"`
+	// Статус, который обязаны иметь задачи в колонке; null — любой статус доски
+	Status *string `json:"status,omitempty"`
+	// Сколько задач может быть в колонке; null — без лимита
+	WipLimit *int `json:"wip_limit,omitempty"`
+	// Сколько задач колонки может быть у одного исполнителя; null — без лимита
+	WipLimitPerAssignee *int    `json:"wip_limit_per_assignee,omitempty"`
+	WipMode             WIPMode `json:"wip_mode"`
+}
+
+// BoardInput новая доска. Без columns создаются колонки backlog, todo, in_progress, review, done.
+type BoardInput struct {
+	// Колонки в нужном порядке
+	Columns     []ColumnInput `json:"columns,omitempty"`
+	Description *string       `json:"description,omitempty"`
+	Name        string        `json:"name"`
+}
+
+// BoardSnapshot доска со всеми колонками и их карточками; счётчики учитывают фильтры запроса
+type BoardSnapshot struct {
+	// Число задач по исполнителям, по убыванию; задачи без исполнителя — последними
+	Assignees []AssigneeCount `json:"assignees"`
+	// Колонки в порядке position
+	Columns     []ColumnSnapshot `json:"columns"`
+	Description string           `json:"description"`
+	ID          uuid.UUID        `json:"id"`
+	IsDefault   bool             `json:"is_default"`
+	Name        string           `json:"name"`
+	// Число задач по приоритетам, от urgent к low
+	Priorities []PriorityCount `json:"priorities"`
+	// Задач на доске, подходящих под фильтры
+	Total int `json:"total"`
+}
+
+// BoardStatus схема из спецификации
+type BoardStatus struct {
+	// Завершающий статус: задача в нём не блокирует другие задачи и не учитывается
+	Final *bool `json:"final,omitempty"`
+	// Ключ статуса (a-z, 0-9, _), уникальный в пределах доски
+	Key  string `json:"key"`
+	Name string `json:"name"`
+	// Порядковый номер статуса, с нуля (в запросе игнорируется)
+	Position *int `json:"position,omitempty"`
+}
+
+// BoardTransition схема из спецификации
+type BoardTransition struct {
+	From string `json:"from"`
+	// Для перехода у задачи должен быть исполнитель
+	RequireAssignee *bool `json:"require_assignee,omitempty"`
+	// Поля задачи, которые должны быть заполнены для перехода
+	RequiredFields []TransitionField `json:"required_fields,omitempty"`
+	// Роли пользователя относительно задачи, которым доступен переход; пусто — всем
+	Roles []TransitionRole `json:"roles,omitempty"`
+	To    string           `json:"to"`
+}
+
+// BoardUpdate название и описание доски; колонки меняются через /boards/{id}/columns
+type BoardUpdate struct {
+	Description *string `json:"description,omitempty"`
+	Name        string  `json:"name"`
+}
+
+// ColumnInput схема из спецификации
+type ColumnInput struct {
+	// Ключ колонки (a-z, 0-9, _), уникальный в пределах доски
+	Key  string `json:"key"`
+	Name string `json:"name"`
+	// Место колонки; без него колонка добавляется в конец (игнорируется при создании доски)
+	Position *int        `json:"position,omitempty"`
+	Status   *TaskStatus `json:"status,omitempty"`
+	// WIP-лимит колонки; без него лимита нет
+	WipLimit *int `json:"wip_limit,omitempty"`
+	// WIP-лимит на одного исполнителя; без него лимита нет
+	WipLimitPerAssignee *int     `json:"wip_limit_per_assignee,omitempty"`
+	WipMode             *WIPMode `json:"wip_mode,omitempty"`
+}
+
+// ColumnSnapshot колонка доски (поля BoardColumn) с карточками
+type ColumnSnapshot struct {
+	BoardID uuid.UUID `json:"board_id"`
+	// Задач в колонке, подходящих под фильтры; tasks может содержать меньше (limit)
+	Count    int       `json:"count"`
+	ID       uuid.UUID `json:"id"`
+	Key      string    `json:"key"`
+	Name     string    `json:"name"`
+	Position int       `json:"position"`
+	Status   *string   `json:"status,omitempty"`
+	// Карточки в порядке rank
+	Tasks               []Task  `json:"tasks"`
+	WipLimit            *int    `json:"wip_limit,omitempty"`
+	WipLimitPerAssignee *int    `json:"wip_limit_per_assignee,omitempty"`
+	WipMode             WIPMode `json:"wip_mode"`
+}
+
+// ColumnUpdate схема из спецификации
+type ColumnUpdate struct {
+	Name string `json:"name"`
+	// Новое место колонки; остальные колонки сдвигаются
+	Position *int        `json:"position,omitempty"`
+	Status   *TaskStatus `json:"status,omitempty"`
+	// WIP-лимит колонки; без него лимит снимается
+	WipLimit *int `json:"wip_limit,omitempty"`
+	// WIP-лимит на одного исполнителя; без него лимит снимается
+	WipLimitPerAssignee *int     `json:"wip_limit_per_assignee,omitempty"`
+	WipMode             *WIPMode `json:"wip_mode,omitempty"`
+}
+
+// FieldChange изменение одного поля задачи. Для связей field — поле связи в Task
+type FieldChange struct {
+	Field string `json:"field"`
+	// Значение после изменения; null — поле очищено, задача или связь удалены
+	New interface{} `json:"new,omitempty"`
+	// Значение до изменения; null — поля не было (создание, новая связь) или оно было пустым
+	Old interface{} `json:"old,omitempty"`
+}
+
+// GetBoardSnapshotParams query-параметры операции GetBoardSnapshot
+type GetBoardSnapshotParams struct {
+	ColumnID    *uuid.UUID    `form:"column_id"`
+	KanbanSpace *KanbanSpace  `form:"kanban_space"`
+	Status      *TaskStatus   `form:"status"`
+	Priority    *TaskPriority `form:"priority"`
+	OwnerID     *int          `form:"owner_id"`
+	AssigneeID  *int          `form:"assignee_id"`
+	Mine        bool          `form:"mine"`
+	DueFrom     *time.Time    `form:"due_from"`
+	DueTo       *time.Time    `form:"due_to"`
+	CreatedFrom *time.Time    `form:"created_from"`
+	CreatedTo   *time.Time    `form:"created_to"`
+	UpdatedFrom *time.Time    `form:"updated_from"`
+	UpdatedTo   *time.Time    `form:"updated_to"`
+	Limit       int           `form:"limit"`
+}
+
+// GetScheduleParams query-параметры операции GetSchedule
+type GetScheduleParams struct {
+	BoardID *uuid.UUID `form:"board_id"`
+}
+
+// GetTaskGraphParams query-параметры операции GetTaskGraph
+type GetTaskGraphParams struct {
+	Direction GetTaskGraphParamsDirection `form:"direction"`
+	Depth     int                         `form:"depth"`
+	Relation  *TaskRelation               `form:"relation"`
+	Format    GetTaskGraphParamsFormat    `form:"format"`
+}
+
+// GetTaskGraphParamsDirection схема из спецификации
+type GetTaskGraphParamsDirection string
+
+// Допустимые значения GetTaskGraphParamsDirection
+const (
+	GetTaskGraphParamsDirectionUp   GetTaskGraphParamsDirection = "up"
+	GetTaskGraphParamsDirectionDown GetTaskGraphParamsDirection = "down"
+	GetTaskGraphParamsDirectionBoth GetTaskGraphParamsDirection = "both"
+)
+
+// Valid сообщает, входит ли значение в перечисление
+func (v GetTaskGraphParamsDirection) Valid() bool {
+	switch v {
+	case GetTaskGraphParamsDirectionUp, GetTaskGraphParamsDirectionDown, GetTaskGraphParamsDirectionBoth:
+		return true
+	}
+	return false
+}
+
+// UnmarshalJSON отклоняет значения вне перечисления
+func (v *GetTaskGraphParamsDirection) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	if !GetTaskGraphParamsDirection(s).Valid() {
+		return fmt.Errorf("unexpected GetTaskGraphParamsDirection value %q", s)
+	}
+	*v = GetTaskGraphParamsDirection(s)
+	return nil
+}
+
+// GetTaskGraphParamsFormat схема из спецификации
+type GetTaskGraphParamsFormat string
+
+// Допустимые значения GetTaskGraphParamsFormat
+const (
+	GetTaskGraphParamsFormatJSON    GetTaskGraphParamsFormat = "json"
+	GetTaskGraphParamsFormatDot     GetTaskGraphParamsFormat = "dot"
+	GetTaskGraphParamsFormatMermaid GetTaskGraphParamsFormat = "mermaid"
+)
+
+// Valid сообщает, входит ли значение в перечисление
+func (v GetTaskGraphParamsFormat) Valid() bool {
+	switch v {
+	case GetTaskGraphParamsFormatJSON, GetTaskGraphParamsFormatDot, GetTaskGraphParamsFormatMermaid:
+		return true
+	}
+	return false
+}
+
+// UnmarshalJSON отклоняет значения вне перечисления
+func (v *GetTaskGraphParamsFormat) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	if !GetTaskGraphParamsFormat(s).Valid() {
+		return fmt.Errorf("unexpected GetTaskGraphParamsFormat value %q", s)
+	}
+	*v = GetTaskGraphParamsFormat(s)
+	return nil
+}
+
+// GetTaskHistoryParams query-параметры операции GetTaskHistory
+type GetTaskHistoryParams struct {
+	Limit  int `form:"limit"`
+	Offset int `form:"offset"`
+}
+
+// KanbanSpace ключ колонки доски. У доски по умолчанию это backlog, todo, in_progress, review и done;
+
+type KanbanSpace = string
+
+// ListTasksParams query-параметры операции ListTasks
+type ListTasksParams struct {
+	BoardID     *uuid.UUID              `form:"board_id"`
+	ColumnID    *uuid.UUID              `form:"column_id"`
+	KanbanSpace *KanbanSpace            `form:"kanban_space"`
+	Status      *TaskStatus             `form:"status"`
+	Priority    *TaskPriority           `form:"priority"`
+	OwnerID     *int                    `form:"owner_id"`
+	AssigneeID  *int                    `form:"assignee_id"`
+	Mine        bool                    `form:"mine"`
+	Archived    ListTasksParamsArchived `form:"archived"`
+	DueFrom     *time.Time              `form:"due_from"`
+	DueTo       *time.Time              `form:"due_to"`
+	CreatedFrom *time.Time              `form:"created_from"`
+	CreatedTo   *time.Time              `form:"created_to"`
+	UpdatedFrom *time.Time              `form:"updated_from"`
+	UpdatedTo   *time.Time              `form:"updated_to"`
+	SortBy      ListTasksParamsSortBy   `form:"sort_by"`
+	Order       ListTasksParamsOrder    `form:"order"`
+	Limit       int                     `form:"limit"`
+	Offset      int                     `form:"offset"`
+	Cursor      *string                 `form:"cursor"`
+}
+
+// ListTasksParamsArchived схема из спецификации
+type ListTasksParamsArchived string
+
+// Допустимые значения ListTasksParamsArchived
+const (
+	ListTasksParamsArchivedExclude ListTasksParamsArchived = "exclude"
+	ListTasksParamsArchivedInclude ListTasksParamsArchived = "include"
+	ListTasksParamsArchivedOnly    ListTasksParamsArchived = "only"
+)
+
+// Valid сообщает, входит ли значение в перечисление
+func (v ListTasksParamsArchived) Valid() bool {
+	switch v {
+	case ListTasksParamsArchivedExclude, ListTasksParamsArchivedInclude, ListTasksParamsArchivedOnly:
+		return true
+	}
+	return false
+}
+
+// UnmarshalJSON отклоняет значения вне перечисления
+func (v *ListTasksParamsArchived) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	if !ListTasksParamsArchived(s).Valid() {
+		return fmt.Errorf("unexpected ListTasksParamsArchived value %q", s)
+	}
+	*v = ListTasksParamsArchived(s)
+	return nil
+}
+
+// ListTasksParamsOrder схема из спецификации
+type ListTasksParamsOrder string
+
+// Допустимые значения ListTasksParamsOrder
+const (
+	ListTasksParamsOrderAsc  ListTasksParamsOrder = "asc"
+	ListTasksParamsOrderDesc ListTasksParamsOrder = "desc"
+)
+
+// Valid сообщает, входит ли значение в перечисление
+func (v ListTasksParamsOrder) Valid() bool {
+	switch v {
+	case ListTasksParamsOrderAsc, ListTasksParamsOrderDesc:
+		return true
+	}
+	return false
+}
+
+// UnmarshalJSON отклоняет значения вне перечисления
+func (v *ListTasksParamsOrder) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	if !ListTasksParamsOrder(s).Valid() {
+		return fmt.Errorf("unexpected ListTasksParamsOrder value %q", s)
+	}
+	*v = ListTasksParamsOrder(s)
+	return nil
+}
+
+// ListTasksParamsSortBy схема из спецификации
+type ListTasksParamsSortBy string
+
+// Допустимые значения ListTasksParamsSortBy
+const (
+	ListTasksParamsSortByCreatedAt ListTasksParamsSortBy = "created_at"
+	ListTasksParamsSortByUpdatedAt ListTasksParamsSortBy = "updated_at"
+	ListTasksParamsSortByDueDate   ListTasksParamsSortBy = "due_date"
+	ListTasksParamsSortByPriority  ListTasksParamsSortBy = "priority"
+	ListTasksParamsSortByTitle     ListTasksParamsSortBy = "title"
+	ListTasksParamsSortByStatus    ListTasksParamsSortBy = "status"
+	ListTasksParamsSortByRank      ListTasksParamsSortBy = "rank"
+)
+
+// Valid сообщает, входит ли значение в перечисление
+func (v ListTasksParamsSortBy) Valid() bool {
+	switch v {
+	case ListTasksParamsSortByCreatedAt, ListTasksParamsSortByUpdatedAt, ListTasksParamsSortByDueDate, ListTasksParamsSortByPriority, ListTasksParamsSortByTitle, ListTasksParamsSortByStatus, ListTasksParamsSortByRank:
+		return true
+	}
+	return false
+}
+
+// UnmarshalJSON отклоняет значения вне перечисления
+func (v *ListTasksParamsSortBy) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	if !ListTasksParamsSortBy(s).Valid() {
+		return fmt.Errorf("unexpected ListTasksParamsSortBy value %q", s)
+	}
+	*v = ListTasksParamsSortBy(s)
+	return nil
+}
+
+// ListTrashParams query-параметры операции ListTrash
+type ListTrashParams struct {
+	BoardID *uuid.UUID `form:"board_id"`
+	Limit   int        `form:"limit"`
+	Offset  int        `form:"offset"`
+}
+
+// LoginRequest схема из спецификации
+type LoginRequest struct {
+	Login    string `json:"login"`
+	Password string `json:"password"`
+}
+
+// Member пользователь с ролью на доске или в рабочем пространстве
+type Member struct {
+	Role MemberRole  `json:"role"`
+	User UserSummary `json:"user"`
+}
+
+// MemberInput схема из спецификации
+type MemberInput struct {
+	Role MemberRole `json:"role"`
+}
+
+// MemberRole роль пользователя (owner, admin, member, viewer) на доске или во всём рабочем пространстве
+type MemberRole string
+
+// Допустимые значения MemberRole
+const (
+	MemberRoleOwner  MemberRole = "owner"
+	MemberRoleAdmin  MemberRole = "admin"
+	MemberRoleMember MemberRole = "member"
+	MemberRoleViewer MemberRole = "viewer"
+)
+
+// Valid сообщает, входит ли значение в перечисление
+func (v MemberRole) Valid() bool {
+	switch v {
+	case MemberRoleOwner, MemberRoleAdmin, MemberRoleMember, MemberRoleViewer:
+		return true
+	}
+	return false
+}
+
+// UnmarshalJSON отклоняет значения вне перечисления
+func (v *MemberRole) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	if !MemberRole(s).Valid() {
+		return fmt.Errorf("unexpected MemberRole value %q", s)
+	}
+	*v = MemberRole(s)
+	return nil
+}
+
+// MoveTaskRequest колонка задаётся column_id или ключом space, а без них — колонкой карточки after_id
+type MoveTaskRequest struct {
+	// Карточка встаёт сразу после этой карточки целевой колонки
+	AfterID *uuid.UUID `json:"after_id,omitempty"`
+	// Карточка встаёт сразу перед этой карточкой целевой колонки
+	BeforeID *uuid.UUID   `json:"before_id,omitempty"`
+	ColumnID *uuid.UUID   `json:"column_id,omitempty"`
+	Space    *KanbanSpace `json:"space,omitempty"`
+	Status   *TaskStatus  `json:"status,omitempty"`
+}
+
+// PriorityCount схема из спецификации
+type PriorityCount struct {
+	Count    int          `json:"count"`
+	Priority TaskPriority `json:"priority"`
+}
+
+// Problem описание ошибки (RFC 7807, application/problem+json)
+type Problem struct {
+	// Статусы, в которые задача может перейти из текущего (code = transition_not_allowed)
+	AllowedStatuses []string `json:"allowed_statuses,omitempty"`
+	// id исполнителя, если превышен лимит на одного исполнителя (code = wip_limit_exceeded)
+	Assignee *int `json:"assignee,omitempty"`
+	// Незавершённые задачи, которые не дают выполнить переход (code = transition_blocked)
+	BlockingTasks []uuid.UUID `json:"blocking_tasks,omitempty"`
+	// Стабильный машиночитаемый код: internal_error, invalid_request, validation_failed,
+	Code string `json:"code"`
+	// Ключ колонки, WIP-лимит которой превышен (code = wip_limit_exceeded)
+	Column *string `json:"column,omitempty"`
+	// Описание конкретной ошибки
+	Detail *string `json:"detail,omitempty"`
+	// Путь запроса
+	Instance *string `json:"instance,omitempty"`
+	// Поля и параметры, не прошедшие проверку
+	InvalidParams []ProblemField `json:"invalid_params,omitempty"`
+	// Право, которого не хватает пользователю (code = forbidden)
+	Permission *string `json:"permission,omitempty"`
+	Status     int     `json:"status"`
+	// Краткое описание HTTP-статуса
+	Title string `json:"title"`
+	// URI типа ошибки (urn:truesmartcomm:problem:<code>)
+	Type string `json:"type"`
+	// Сколько задач было бы в колонке (у исполнителя) вместе с этой (code = wip_limit_exceeded)
+	WipCount *int `json:"wip_count,omitempty"`
+	// Превышенный лимит (code = wip_limit_exceeded)
+	WipLimit *int `json:"wip_limit,omitempty"`
+}
+
+// ProblemField схема из спецификации
+type ProblemField struct {
+	// Где находится значение (path, query, body); пусто для полей ресурса
+	In *string `json:"in,omitempty"`
+	// Имя поля или параметра (для вложенных полей — путь, например items[0].id)
+	Name   string `json:"name"`
+	Reason string `json:"reason"`
+}
+
+// RefreshRequest схема из спецификации
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// RegisterRequest схема из спецификации
+type RegisterRequest struct {
+	Email    string `json:"email"`
+	Login    string `json:"login"`
+	Password string `json:"password"`
+}
+
+// RemoveTaskDependencyParams query-параметры операции RemoveTaskDependency
+type RemoveTaskDependencyParams struct {
+	Relation *TaskRelation `form:"relation"`
+}
+
+// Schedule расписание по методу критического пути. Время отсчитывается от start непрерывно
+type Schedule struct {
+	// Задачи критического пути в порядке выполнения
+	CriticalPath []uuid.UUID `json:"critical_path"`
+	// Самое раннее завершение всех задач
+	Finish time.Time `json:"finish"`
+	// Задача, для которой построено расписание (нет для расписания всей доски)
+	Root *uuid.UUID `json:"root,omitempty"`
+	// Момент расчёта
+	Start time.Time `json:"start"`
+	// Задачи в топологическом порядке
+	Tasks []ScheduledTask `json:"tasks"`
+}
+
+// ScheduledTask схема из спецификации
+type ScheduledTask struct {
+	Critical bool       `json:"critical"`
+	DueDate  *time.Time `json:"due_date,omitempty"`
+	// Срок due_date не достижим даже при самом раннем завершении
+	DueDateAtRisk bool `json:"due_date_at_risk"`
+	// Длительность, использованная в расчёте
+	DurationHours  float64   `json:"duration_hours"`
+	EarliestFinish time.Time `json:"earliest_finish"`
+	EarliestStart  time.Time `json:"earliest_start"`
+	// Оценка задачи; отсутствует, если не задана (длительность считается нулевой)
+	EstimateHours *float64  `json:"estimate_hours,omitempty"`
+	ID            uuid.UUID `json:"id"`
+	LatestFinish  time.Time `json:"latest_finish"`
+	LatestStart   time.Time `json:"latest_start"`
+	// Задачи, которые должны завершиться раньше (блокирующие задачи и подзадачи)
+	Predecessors []uuid.UUID `json:"predecessors,omitempty"`
+	// Резерв времени; 0 у задач критического пути
+	SlackHours float64    `json:"slack_hours"`
+	Status     TaskStatus `json:"status"`
+	Title      string     `json:"title"`
+}
+
+// Task задача в Kanban-доске
+type Task struct {
+	// Время архивации; есть только у архивных задач
+	ArchivedAt *time.Time `json:"archived_at,omitempty"`
+	// Исполнители задачи в порядке id; пусто — исполнитель не назначен
+	Assignees []UserSummary `json:"assignees,omitempty"`
+	// Задачи, которые блокируют эту задачу (заполняется в GET /tasks/{id}/dependencies)
+	BlockedBy []uuid.UUID `json:"blocked_by,omitempty"`
+	// Задачи, которые блокирует эта задача (заполняется в GET /tasks/{id}/dependencies)
+	Blocks []uuid.UUID `json:"blocks,omitempty"`
+	// Доска, которой принадлежит задача
+	BoardID uuid.UUID `json:"board_id"`
+	// Колонка доски, в которой находится задача
+	ColumnID uuid.UUID `json:"column_id"`
+	// Время перехода в завершающий статус доски; нет у незавершённых задач
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	// Время создания задачи
+	CreatedAt time.Time `json:"created_at"`
+	// Время переноса в корзину; есть только у задач из GET /trash
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	// Детальное описание задачи (пустая строка, если не задано)
+	Description string `json:"description"`
+	// Срок выполнения задачи
+	DueDate *time.Time `json:"due_date,omitempty"`
+	// Задачи-дубликаты этой задачи (заполняется в GET /tasks/{id}/dependencies)
+	DuplicatedBy []uuid.UUID `json:"duplicated_by,omitempty"`
+	// Задачи, которые дублирует эта задача (заполняется в GET /tasks/{id}/dependencies)
+	Duplicates []uuid.UUID `json:"duplicates,omitempty"`
+	// Оценка трудоёмкости в часах
+	EstimateHours *float64 `json:"estimate_hours,omitempty"`
+	// Уникальный идентификатор задачи
+	ID          uuid.UUID    `json:"id"`
+	KanbanSpace KanbanSpace  `json:"kanban_space"`
+	Owner       *UserSummary `json:"owner,omitempty"`
+	// Родительские задачи, связь subtask_of (заполняется в GET /tasks/{id}/dependencies)
+	ParentTasks []uuid.UUID  `json:"parent_tasks,omitempty"`
+	Priority    TaskPriority `json:"priority"`
+	// Ключ ручного порядка карточки в колонке; карточки упорядочены по возрастанию rank
+	Rank string `json:"rank"`
+	// Связанные задачи, связь relates_to в любую сторону (заполняется в GET /tasks/{id}/dependencies)
+	RelatesTo []uuid.UUID `json:"relates_to,omitempty"`
+	Status    TaskStatus  `json:"status"`
+	// Подзадачи, связь subtask_of (заполняется в GET /tasks/{id}/dependencies)
+	SubTasks []uuid.UUID `json:"sub_tasks,omitempty"`
+	// Название задачи
+	Title string `json:"title"`
+	// Время последнего обновления задачи
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TaskGraph транзитивные связи задачи
+type TaskGraph struct {
+	Edges []TaskGraphEdge `json:"edges"`
+	// Задачи графа, включая корневую (depth = 0)
+	Nodes []TaskGraphNode `json:"nodes"`
+	Root  uuid.UUID       `json:"root"`
+	// Граф глубже, чем depth; показаны не все задачи
+	Truncated bool `json:"truncated"`
+}
+
+// TaskGraphEdge связь от задачи from к задаче to (см. TaskRelation)
+type TaskGraphEdge struct {
+	From     uuid.UUID    `json:"from"`
+	Relation TaskRelation `json:"relation"`
+	To       uuid.UUID    `json:"to"`
+}
+
+// TaskGraphNode краткие сведения о задаче в графе
+type TaskGraphNode struct {
+	// Расстояние от корневой задачи
+	Depth       int          `json:"depth"`
+	ID          uuid.UUID    `json:"id"`
+	KanbanSpace KanbanSpace  `json:"kanban_space"`
+	Priority    TaskPriority `json:"priority"`
+	Status      TaskStatus   `json:"status"`
+	Title       string       `json:"title"`
+}
+
+// TaskHistoryAction событие в истории задачи
+type TaskHistoryAction string
+
+// Допустимые значения TaskHistoryAction
+const (
+	TaskHistoryActionCreated           TaskHistoryAction = "created"
+	TaskHistoryActionUpdated           TaskHistoryAction = "updated"
+	TaskHistoryActionMoved             TaskHistoryAction = "moved"
+	TaskHistoryActionDeleted           TaskHistoryAction = "deleted"
+	TaskHistoryActionRestored          TaskHistoryAction = "restored"
+	TaskHistoryActionPurged            TaskHistoryAction = "purged"
+	TaskHistoryActionArchived          TaskHistoryAction = "archived"
+	TaskHistoryActionUnarchived        TaskHistoryAction = "unarchived"
+	TaskHistoryActionDependencyAdded   TaskHistoryAction = "dependency_added"
+	TaskHistoryActionDependencyRemoved TaskHistoryAction = "dependency_removed"
+)
+
+// Valid сообщает, входит ли значение в перечисление
+func (v TaskHistoryAction) Valid() bool {
+	switch v {
+	case TaskHistoryActionCreated, TaskHistoryActionUpdated, TaskHistoryActionMoved, TaskHistoryActionDeleted, TaskHistoryActionRestored, TaskHistoryActionPurged, TaskHistoryActionArchived, TaskHistoryActionUnarchived, TaskHistoryActionDependencyAdded, TaskHistoryActionDependencyRemoved:
+		return true
+	}
+	return false
+}
+
+// UnmarshalJSON отклоняет значения вне перечисления
+func (v *TaskHistoryAction) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	if !TaskHistoryAction(s).Valid() {
+		return fmt.Errorf("unexpected TaskHistoryAction value %q", s)
+	}
+	*v = TaskHistoryAction(s)
+	return nil
+}
+
+// TaskHistoryEntry схема из спецификации
+type TaskHistoryEntry struct {
+	Action TaskHistoryAction `json:"action"`
+	// Пользователь, выполнивший изменение; null — неизвестен или удалён
+	ActorID   *int          `json:"actor_id,omitempty"`
+	Changes   []FieldChange `json:"changes"`
+	CreatedAt time.Time     `json:"created_at"`
+	ID        int           `json:"id"`
+	TaskID    uuid.UUID     `json:"task_id"`
+}
+
+// TaskHistoryPage страница истории задачи, новые события первыми
+type TaskHistoryPage struct {
+	Items  []TaskHistoryEntry `json:"items"`
+	Limit  int                `json:"limit"`
+	Offset int                `json:"offset"`
+	Total  int                `json:"total"`
+}
+
+// TaskInput данные задачи для создания и полного обновления. Без board_id задача создаётся на доске
+type TaskInput struct {
+	// id пользователей-исполнителей; при обновлении заменяют текущих, без поля исполнители снимаются
+	AssigneeIds []int      `json:"assignee_ids,omitempty"`
+	BoardID     *uuid.UUID `json:"board_id,omitempty"`
+	ColumnID    *uuid.UUID `json:"column_id,omitempty"`
+	Description *string    `json:"description,omitempty"`
+	DueDate     *time.Time `json:"due_date,omitempty"`
+	// Оценка трудоёмкости в часах
+	EstimateHours *float64      `json:"estimate_hours,omitempty"`
+	KanbanSpace   *KanbanSpace  `json:"kanban_space,omitempty"`
+	Priority      *TaskPriority `json:"priority,omitempty"`
+	Status        *TaskStatus   `json:"status,omitempty"`
+	Title         string        `json:"title"`
+}
+
+// TaskList страница списка задач
+type TaskList struct {
+	Items []Task `json:"items"`
+	Limit int    `json:"limit"`
+	// Непрозрачный курсор следующей страницы (передаётся в параметре cursor)
+	NextCursor *string `json:"next_cursor,omitempty"`
+	Offset     int     `json:"offset"`
+	// Непрозрачный курсор предыдущей страницы
+	PrevCursor *string `json:"prev_cursor,omitempty"`
+	// Общее количество задач, подходящих под фильтр
+	Total int `json:"total"`
+}
+
+// TaskPatch JSON Merge Patch задачи: отсутствующее поле не меняется, null очищает его.
+type TaskPatch struct {
+	// id пользователей-исполнителей взамен текущих; null или пустой список снимает всех
+	AssigneeIds Nullable[[]int] `json:"assignee_ids"`
+	// Перенос на другую доску; без column_id и kanban_space — в её первую колонку
+	BoardID       *uuid.UUID             `json:"board_id,omitempty"`
+	ColumnID      *uuid.UUID             `json:"column_id,omitempty"`
+	Description   Nullable[string]       `json:"description"`
+	DueDate       Nullable[time.Time]    `json:"due_date"`
+	EstimateHours Nullable[float64]      `json:"estimate_hours"`
+	KanbanSpace   Nullable[KanbanSpace]  `json:"kanban_space"`
+	Priority      Nullable[TaskPriority] `json:"priority"`
+	Status        Nullable[TaskStatus]   `json:"status"`
+	Title         Nullable[string]       `json:"title"`
+}
+
+// TaskPriority приоритет задачи
+type TaskPriority string
+
+// Допустимые значения TaskPriority
+const (
+	TaskPriorityLow    TaskPriority = "low"
+	TaskPriorityMedium TaskPriority = "medium"
+	TaskPriorityHigh   TaskPriority = "high"
+	TaskPriorityUrgent TaskPriority = "urgent"
+)
+
+// Valid сообщает, входит ли значение в перечисление
+func (v TaskPriority) Valid() bool {
+	switch v {
+	case TaskPriorityLow, TaskPriorityMedium, TaskPriorityHigh, TaskPriorityUrgent:
+		return true
+	}
+	return false
+}
+
+// UnmarshalJSON отклоняет значения вне перечисления
+func (v *TaskPriority) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	if !TaskPriority(s).Valid() {
+		return fmt.Errorf("unexpected TaskPriority value %q", s)
+	}
+	*v = TaskPriority(s)
+	return nil
+}
+
+// TaskRelation тип связи между задачами, направленной от задачи {id} к связанной задаче.
+type TaskRelation string
+
+// Допустимые значения TaskRelation
+const (
+	TaskRelationBlocks     TaskRelation = "blocks"
+	TaskRelationSubtaskOf  TaskRelation = "subtask_of"
+	TaskRelationRelatesTo  TaskRelation = "relates_to"
+	TaskRelationDuplicates TaskRelation = "duplicates"
+)
+
+// Valid сообщает, входит ли значение в перечисление
+func (v TaskRelation) Valid() bool {
+	switch v {
+	case TaskRelationBlocks, TaskRelationSubtaskOf, TaskRelationRelatesTo, TaskRelationDuplicates:
+		return true
+	}
+	return false
+}
+
+// UnmarshalJSON отклоняет значения вне перечисления
+func (v *TaskRelation) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	if !TaskRelation(s).Valid() {
+		return fmt.Errorf("unexpected TaskRelation value %q", s)
+	}
+	*v = TaskRelation(s)
+	return nil
+}
+
+// TaskStatus ключ статуса из workflow доски задачи. У новых досок это todo, in_progress, review
+
+type TaskStatus = string
+
+// TaskTransition переход, доступный задаче из текущего статуса, и выполнение его условий
+type TaskTransition struct {
+	// Текущий пользователь может выполнить переход прямо сейчас
+	Available bool `json:"available"`
+	// Незавершённые блокирующие задачи и подзадачи (для завершающих статусов)
+	BlockingTasks []uuid.UUID `json:"blocking_tasks,omitempty"`
+	Final         bool        `json:"final"`
+	// У пользователя нет роли, которой разрешён переход
+	Forbidden *bool `json:"forbidden,omitempty"`
+	// Незаполненные обязательные поля
+	MissingFields   []string          `json:"missing_fields,omitempty"`
+	Name            string            `json:"name"`
+	RequireAssignee bool              `json:"require_assignee"`
+	RequiredFields  []TransitionField `json:"required_fields"`
+	Roles           []TransitionRole  `json:"roles"`
+	To              string            `json:"to"`
+}
+
+// TransitionField поле задачи, которое можно потребовать заполнить перед переходом
+type TransitionField string
+
+// Допустимые значения TransitionField
+const (
+	TransitionFieldDescription   TransitionField = "description"
+	TransitionFieldAssignees     TransitionField = "assignees"
+	TransitionFieldDueDate       TransitionField = "due_date"
+	TransitionFieldEstimateHours TransitionField = "estimate_hours"
+)
+
+// Valid сообщает, входит ли значение в перечисление
+func (v TransitionField) Valid() bool {
+	switch v {
+	case TransitionFieldDescription, TransitionFieldAssignees, TransitionFieldDueDate, TransitionFieldEstimateHours:
+		return true
+	}
+	return false
+}
+
+// UnmarshalJSON отклоняет значения вне перечисления
+func (v *TransitionField) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	if !TransitionField(s).Valid() {
+		return fmt.Errorf("unexpected TransitionField value %q", s)
+	}
+	*v = TransitionField(s)
+	return nil
+}
+
+// TransitionRole роль пользователя относительно задачи (task_owner — владелец задачи, assignee — исполнитель). Не связана с ролями рабочего пространства и доски (MemberRole).
+type TransitionRole string
+
+// Допустимые значения TransitionRole
+const (
+	TransitionRoleTaskOwner TransitionRole = "task_owner"
+	TransitionRoleAssignee  TransitionRole = "assignee"
+)
+
+// Valid сообщает, входит ли значение в перечисление
+func (v TransitionRole) Valid() bool {
+	switch v {
+	case TransitionRoleTaskOwner, TransitionRoleAssignee:
+		return true
+	}
+	return false
+}
+
+// UnmarshalJSON отклоняет значения вне перечисления
+func (v *TransitionRole) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	if !TransitionRole(s).Valid() {
+		return fmt.Errorf("unexpected TransitionRole value %q", s)
+	}
+	*v = TransitionRole(s)
+	return nil
+}
+
+// UserSummary пользователь в ответах с задачами
+type UserSummary struct {
+	Email string `json:"email"`
+	ID    int    `json:"id"`
+	Login string `json:"login"`
+}
+
+// WIPMode режим WIP-лимитов колонки.
+type WIPMode string
+
+// Допустимые значения WIPMode
+const (
+	WIPModeHard WIPMode = "hard"
+	WIPModeSoft WIPMode = "soft"
+)
+
+// Valid сообщает, входит ли значение в перечисление
+func (v WIPMode) Valid() bool {
+	switch v {
+	case WIPModeHard, WIPModeSoft:
+		return true
+	}
+	return false
+}
+
+// UnmarshalJSON отклоняет значения вне перечисления
+func (v *WIPMode) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	if !WIPMode(s).Valid() {
+		return fmt.Errorf("unexpected WIPMode value %q", s)
+	}
+	*v = WIPMode(s)
+	return nil
+}
+
+// Workflow статусы доски и граф переходов между ними. Пустой список transitions разрешает
+type Workflow struct {
+	// Статусы в порядке отображения; хотя бы один
+	Statuses    []BoardStatus     `json:"statuses"`
+	Transitions []BoardTransition `json:"transitions"`
+}
+
+// InvalidParamError параметр пути или query-строки не соответствует спецификации
+type InvalidParamError struct {
+	Name string
+	In   string
+	Err  error
+}
+
+func (e *InvalidParamError) Error() string {
+	return fmt.Sprintf("invalid %s parameter %s: %v", e.In, e.Name, e.Err)
+}
+
+func (e *InvalidParamError) Unwrap() error { return e.Err }
+
+// InvalidBodyError тело запроса не удалось разобрать
+type InvalidBodyError struct {
+	Err error
+}
+
+func (e *InvalidBodyError) Error() string {
+	return "invalid request body: " + e.Err.Error()
+}
+
+func (e *InvalidBodyError) Unwrap() error { return e.Err }
+
+// HandlerOptions настройки сгенерированных обёрток
+type HandlerOptions struct {
+	// ErrorHandler отвечает клиенту при ошибке разбора запроса.
+	// По умолчанию возвращает 400 с текстом ошибки.
+	ErrorHandler func(c *gin.Context, err error)
+}
+
+func (o HandlerOptions) errorHandler() func(*gin.Context, error) {
+	if o.ErrorHandler != nil {
+		return o.ErrorHandler
+	}
+	return func(c *gin.Context, err error) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	}
+}
+
+func decodeJSONBody(c *gin.Context, dst interface{}, strict bool) error {
+	if c.Request.Body == nil || c.Request.Body == http.NoBody {
+		return &InvalidBodyError{Err: errors.New("request body is required")}
+	}
+	dec := json.NewDecoder(c.Request.Body)
+	if strict {
+		dec.DisallowUnknownFields()
+	}
+	if err := dec.Decode(dst); err != nil {
+		return &InvalidBodyError{Err: err}
+	}
+	return nil
+}
+
+// AuthServer операции с тегом "auth"
+type AuthServer interface {
+	// Вход по логину и паролю
+	// POST /api/v1/auth/login
+	Login(c *gin.Context, body LoginRequest)
+	// Обновление access токена по refresh токену
+	// POST /api/v1/auth/refresh
+	RefreshToken(c *gin.Context, body RefreshRequest)
+	// Регистрация пользователя
+	// POST /api/v1/auth/register
+	Register(c *gin.Context, body RegisterRequest)
+}
+
+type authWrapper struct {
+	handler      AuthServer
+	errorHandler func(*gin.Context, error)
+}
+
+func (w *authWrapper) Login(c *gin.Context) {
+	var body LoginRequest
+	if err := decodeJSONBody(c, &body, false); err != nil {
+		w.errorHandler(c, err)
+		return
+	}
+	w.handler.Login(c, body)
+}
+
+func (w *authWrapper) RefreshToken(c *gin.Context) {
+	var body RefreshRequest
+	if err := decodeJSONBody(c, &body, false); err != nil {
+		w.errorHandler(c, err)
+		return
+	}
+	w.handler.RefreshToken(c, body)
+}
+
+func (w *authWrapper) Register(c *gin.Context) {
+	var body RegisterRequest
+	if err := decodeJSONBody(c, &body, false); err != nil {
+		w.errorHandler(c, err)
+		return
+	}
+	w.handler.Register(c, body)
+}
+
+// RegisterAuthHandlers регистрирует операции AuthServer по путям из спецификации
+func RegisterAuthHandlers(router gin.IRoutes, si AuthServer, opts HandlerOptions) {
+	w := &authWrapper{handler: si, errorHandler: opts.errorHandler()}
+	router.Handle(http.MethodPost, "/api/v1/auth/login", w.Login)
+	router.Handle(http.MethodPost, "/api/v1/auth/refresh", w.RefreshToken)
+	router.Handle(http.MethodPost, "/api/v1/auth/register", w.Register)
+}
+
+// BoardsServer операции с тегом "boards"
+type BoardsServer interface {
+	// Список досок с колонками
+	// GET /api/v1/boards
+	ListBoards(c *gin.Context)
+	// Создать доску
+	// POST /api/v1/boards
+	CreateBoard(c *gin.Context, body BoardInput)
+	// Получить доску с колонками
+	// GET /api/v1/boards/{id}
+	GetBoard(c *gin.Context, id uuid.UUID)
+	// Изменить название и описание доски
+	// PUT /api/v1/boards/{id}
+	UpdateBoard(c *gin.Context, id uuid.UUID, body BoardUpdate)
+	// Удалить пустую доску
+	// DELETE /api/v1/boards/{id}
+	DeleteBoard(c *gin.Context, id uuid.UUID)
+	// Колонки доски по порядку
+	// GET /api/v1/boards/{id}/columns
+	ListBoardColumns(c *gin.Context, id uuid.UUID)
+	// Добавить колонку
+	// POST /api/v1/boards/{id}/columns
+	CreateBoardColumn(c *gin.Context, id uuid.UUID, body ColumnInput)
+	// Переименовать или переставить колонку
+	// PUT /api/v1/boards/{id}/columns/{column_id}
+	UpdateBoardColumn(c *gin.Context, id uuid.UUID, columnID uuid.UUID, body ColumnUpdate)
+	// Удалить пустую колонку
+	// DELETE /api/v1/boards/{id}/columns/{column_id}
+	DeleteBoardColumn(c *gin.Context, id uuid.UUID, columnID uuid.UUID)
+	// Доска целиком — колонки с карточками и сводки
+	// GET /api/v1/boards/{id}/snapshot
+	GetBoardSnapshot(c *gin.Context, id uuid.UUID, params GetBoardSnapshotParams)
+	// Статусы и граф переходов доски
+	// GET /api/v1/boards/{id}/workflow
+	GetBoardWorkflow(c *gin.Context, id uuid.UUID)
+	// Заменить статусы и граф переходов доски
+	// PUT /api/v1/boards/{id}/workflow
+	UpdateBoardWorkflow(c *gin.Context, id uuid.UUID, body Workflow)
+}
+
+type boardsWrapper struct {
+	handler      BoardsServer
+	errorHandler func(*gin.Context, error)
+}
+
+func (w *boardsWrapper) ListBoards(c *gin.Context) {
+	w.handler.ListBoards(c)
+}
+
+func (w *boardsWrapper) CreateBoard(c *gin.Context) {
+	var body BoardInput
+	if err := decodeJSONBody(c, &body, false); err != nil {
+		w.errorHandler(c, err)
+		return
+	}
+	w.handler.CreateBoard(c, body)
+}
+
+func (w *boardsWrapper) GetBoard(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		w.errorHandler(c, &InvalidParamError{Name: "id", In: "path", Err: err})
+		return
+	}
+	w.handler.GetBoard(c, id)
+}
+
+func (w *boardsWrapper) UpdateBoard(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		w.errorHandler(c, &InvalidParamError{Name: "id", In: "path", Err: err})
+		return
+	}
+	var body BoardUpdate
+	if err := decodeJSONBody(c, &body, false); err != nil {
+		w.errorHandler(c, err)
+		return
+	}
+	w.handler.UpdateBoard(c, id, body)
+}
+
+func (w *boardsWrapper) DeleteBoard(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		w.errorHandler(c, &InvalidParamError{Name: "id", In: "path", Err: err})
+		return
+	}
+	w.handler.DeleteBoard(c, id)
+}
+
+func (w *boardsWrapper) ListBoardColumns(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		w.errorHandler(c, &InvalidParamError{Name: "id", In: "path", Err: err})
+		return
+	}
+	w.handler.ListBoardColumns(c, id)
+}
+
+func (w *boardsWrapper) CreateBoardColumn(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		w.errorHandler(c, &InvalidParamError{Name: "id", In: "path", Err: err})
+		return
+	}
+	var body ColumnInput
+	if err := decodeJSONBody(c, &body, false); err != nil {
+		w.errorHandler(c, err)
+		return
+	}
+	w.handler.CreateBoardColumn(c, id, body)
+}
+
+func (w *boardsWrapper) UpdateBoardColumn(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		w.errorHandler(c, &InvalidParamError{Name: "id", In: "path", Err: err})
+		return
+	}
+	columnID, err := uuid.Parse(c.Param("column_id"))
+	if err != nil {
+		w.errorHandler(c, &InvalidParamError{Name: "column_id", In: "path", Err: err})
+		return
+	}
+	var body ColumnUpdate
+	if err := decodeJSONBody(c, &body, false); err != nil {
+		w.errorHandler(c, err)
+		return
+	}
+	w.handler.UpdateBoardColumn(c, id, columnID, body)
+}
+
+func (w *boardsWrapper) DeleteBoardColumn(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		w.errorHandler(c, &InvalidParamError{Name: "id", In: "path", Err: err})
+		return
+	}
+	columnID, err := uuid.Parse(c.Param("column_id"))
+	if err != nil {
+		w.errorHandler(c, &InvalidParamError{Name: "column_id", In: "path", Err: err})
+		return
+	}
+	w.handler.DeleteBoardColumn(c, id, columnID)
+}
+
+func (w *boardsWrapper) GetBoardSnapshot(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		w.errorHandler(c, &InvalidParamError{Name: "id", In: "path", Err: err})
+		return
+	}
+	var params GetBoardSnapshotParams
+	if raw, ok := c.GetQuery("column_id"); ok {
+		v, err := uuid.Parse(raw)
+		if err != nil {
+			w.errorHandler(c, &InvalidParamError{Name: "column_id", In: "query", Err: err})
+			return
+		}
+		params.ColumnID = &v
+	}
+	if raw, ok := c.GetQuery("kanban_space"); ok {
+		v := raw
+		params.KanbanSpace = &v
+	}
+	if raw, ok := c.GetQuery("status"); ok {
+		v := raw
+		params.Status = &v
+	}
+	if raw, ok := c.GetQuery("priority"); ok {
+		v := TaskPriority(raw)
+		if !v.Valid() {
+			err := fmt.Errorf("unexpected value %q", raw)
+			w.errorHandler(c, &InvalidParamError{Name: "priority", In: "query", Err: err})
+			return
+		}
+		params.Priority = &v
+	}
+	if raw, ok := c.GetQuery("owner_id"); ok {
+		v, err := strconv.Atoi(raw)
+		if err != nil {
+			w.errorHandler(c, &InvalidParamError{Name: "owner_id", In: "query", Err: err})
+			return
+		}
+		params.OwnerID = &v
+	}
+	if raw, ok := c.GetQuery("assignee_id"); ok {
+		v, err := strconv.Atoi(raw)
+		if err != nil {
+			w.errorHandler(c, &InvalidParamError{Name: "assignee_id", In: "query", Err: err})
+			return
+		}
+		params.AssigneeID = &v
+	}
+	params.Mine = false
+	if raw, ok := c.GetQuery("mine"); ok {
+		v, err := strconv.ParseBool(raw)
+		if err != nil {
+			w.errorHandler(c, &InvalidParamError{Name: "mine", In: "query", Err: err})
+			return
+		}
+		params.Mine = v
+	}
+	if raw, ok := c.GetQuery("due_from"); ok {
+		v, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			w.errorHandler(c, &InvalidParamError{Name: "due_from", In: "query", Err: err})
+			return
+		}
+		params.DueFrom = &v
+	}
+	if raw, ok := c.GetQuery("due_to"); ok {
+		v, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			w.errorHandler(c, &InvalidParamError{Name: "due_to", In: "query", Err: err})
+			return
+		}
+		params.DueTo = &v
+	}
+	if raw, ok := c.GetQuery("created_from"); ok {
+		v, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			w.errorHandler(c, &InvalidParamError{Name: "created_from", In: "query", Err: err})
+			return
+		}
+		params.CreatedFrom = &v
+	}
+	if raw, ok := c.GetQuery("created_to"); ok {
+		v, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			w.errorHandler(c, &InvalidParamError{Name: "created_to", In: "query", Err: err})
+			return
+		}
+		params.CreatedTo = &v
+	}
+	if raw, ok := c.GetQuery("updated_from"); ok {
+		v, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			w.errorHandler(c, &InvalidParamError{Name: "updated_from", In: "query", Err: err})
+			return
+		}
+		params.UpdatedFrom = &v
+	}
+	if raw, ok := c.GetQuery("updated_to"); ok {
+		v, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			w.errorHandler(c, &InvalidParamError{Name: "updated_to", In: "query", Err: err})
+			return
+		}
+		params.UpdatedTo = &v
+	}
+	params.Limit = 100
+	if raw, ok := c.GetQuery("limit"); ok {
+		v, err := strconv.Atoi(raw)
+		if err != nil {
+			w.errorHandler(c, &InvalidParamError{Name: "limit", In: "query", Err: err})
+			return
+		}
+		if v < 1 {
+			err := errors.New("must be >= 1")
+			w.errorHandler(c, &InvalidParamError{Name: "limit", In: "query", Err: err})
+			return
+		}
+		if v > 500 {
+			err := errors.New("must be <= 500")
+			w.errorHandler(c, &InvalidParamError{Name: "limit", In: "query", Err: err})
+			return
+		}
+		params.Limit = v
+	}
+	w.handler.GetBoardSnapshot(c, id, params)
+}
+
+func (w *boardsWrapper) GetBoardWorkflow(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		w.errorHandler(c, &InvalidParamError{Name: "id", In: "path", Err: err})
+		return
+	}
+	w.handler.GetBoardWorkflow(c, id)
+}
+
+func (w *boardsWrapper) UpdateBoardWorkflow(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		w.errorHandler(c, &InvalidParamError{Name: "id", In: "path", Err: err})
+		return
+	}
+	var body Workflow
+	if err := decodeJSONBody(c, &body, false); err != nil {
+		w.errorHandler(c, err)
+		return
+	}
+	w.handler.UpdateBoardWorkflow(c, id, body)
+}
+
+// RegisterBoardsHandlers регистрирует операции BoardsServer по путям из спецификации
+func RegisterBoardsHandlers(router gin.IRoutes, si BoardsServer, opts HandlerOptions) {
+	w := &boardsWrapper{handler: si, errorHandler: opts.errorHandler()}
+	router.Handle(http.MethodGet, "/api/v1/boards", w.ListBoards)
+	router.Handle(http.MethodPost, "/api/v1/boards", w.CreateBoard)
+	router.Handle(http.MethodGet, "/api/v1/boards/:id", w.GetBoard)
+	router.Handle(http.MethodPut, "/api/v1/boards/:id", w.UpdateBoard)
+	router.Handle(http.MethodDelete, "/api/v1/boards/:id", w.DeleteBoard)
+	router.Handle(http.MethodGet, "/api/v1/boards/:id/columns", w.ListBoardColumns)
+	router.Handle(http.MethodPost, "/api/v1/boards/:id/columns", w.CreateBoardColumn)
+	router.Handle(http.MethodPut, "/api/v1/boards/:id/columns/:column_id", w.UpdateBoardColumn)
+	router.Handle(http.MethodDelete, "/api/v1/boards/:id/columns/:column_id", w.DeleteBoardColumn)
+	router.Handle(http.MethodGet, "/api/v1/boards/:id/snapshot", w.GetBoardSnapshot)
+	router.Handle(http.MethodGet, "/api/v1/boards/:id/workflow", w.GetBoardWorkflow)
+	router.Handle(http.MethodPut, "/api/v1/boards/:id/workflow", w.UpdateBoardWorkflow)
+}
+
+// MembersServer операции с тегом "members"
+type MembersServer interface {
+	// Участники доски
+	// GET /api/v1/boards/{id}/members
+	ListBoardMembers(c *gin.Context, id uuid.UUID)
+	// Назначить роль на доске
+	// PUT /api/v1/boards/{id}/members/{user_id}
+	SetBoardMember(c *gin.Context, id uuid.UUID, userID int, body MemberInput)
+	// Убрать участника доски
+	// DELETE /api/v1/boards/{id}/members/{user_id}
+	RemoveBoardMember(c *gin.Context, id uuid.UUID, userID int)
+	// Роли в рабочем пространстве
+	// GET /api/v1/members
+	ListWorkspaceMembers(c *gin.Context)
+	// Назначить роль в рабочем пространстве
+	// PUT /api/v1/members/{user_id}
+	SetWorkspaceMember(c *gin.Context, userID int, body MemberInput)
+	// Снять роль в рабочем пространстве
+	// DELETE /api/v1/members/{user_id}
+	RemoveWorkspaceMember(c *gin.Context, userID int)
+}
+
+type membersWrapper struct {
+	handler      MembersServer
+	errorHandler func(*gin.Context, error)
+}
+
+func (w *membersWrapper) ListBoardMembers(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		w.errorHandler(c, &InvalidParamError{Name: "id", In: "path", Err: err})
+		return
+	}
+	w.handler.ListBoardMembers(c, id)
+}
+
+func (w *membersWrapper) SetBoardMember(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		w.errorHandler(c, &InvalidParamError{Name: "id", In: "path", Err: err})
+		return
+	}
+	userID, err := strconv.Atoi(c.Param("user_id"))
+	if err != nil {
+		w.errorHandler(c, &InvalidParamError{Name: "user_id", In: "path", Err: err})
+		return
+	}
+	var body MemberInput
+	if err := decodeJSONBody(c, &body, false); err != nil {
+		w.errorHandler(c, err)
+		return
+	}
+	w.handler.SetBoardMember(c, id, userID, body)
+}
+
+func (w *membersWrapper) RemoveBoardMember(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		w.errorHandler(c, &InvalidParamError{Name: "id", In: "path", Err: err})
+		return
+	}
+	userID, err := strconv.Atoi(c.Param("user_id"))
+	if err != nil {
+		w.errorHandler(c, &InvalidParamError{Name: "user_id", In: "path", Err: err})
+		return
+	}
+	w.handler.RemoveBoardMember(c, id, userID)
+}
+
+func (w *membersWrapper) ListWorkspaceMembers(c *gin.Context) {
+	w.handler.ListWorkspaceMembers(c)
+}
+
+func (w *membersWrapper) SetWorkspaceMember(c *gin.Context) {
+	userID, err := strconv.Atoi(c.Param("user_id"))
+	if err != nil {
+		w.errorHandler(c, &InvalidParamError{Name: "user_id", In: "path", Err: err})
+		return
+	}
+	var body MemberInput
+	if err := decodeJSONBody(c, &body, false); err != nil {
+		w.errorHandler(c, err)
+		return
+	}
+	w.handler.SetWorkspaceMember(c, userID, body)
+}
+
+func (w *membersWrapper) RemoveWorkspaceMember(c *gin.Context) {
+	userID, err := strconv.Atoi(c.Param("user_id"))
+	if err != nil {
+		w.errorHandler(c, &InvalidParamError{Name: "user_id", In: "path", Err: err})
+		return
+	}
+	w.handler.RemoveWorkspaceMember(c, userID)
+}
+
+// RegisterMembersHandlers регистрирует операции MembersServer по путям из спецификации
+func RegisterMembersHandlers(router gin.IRoutes, si MembersServer, opts HandlerOptions) {
+	w := &membersWrapper{handler: si, errorHandler: opts.errorHandler()}
+	router.Handle(http.MethodGet, "/api/v1/boards/:id/members", w.ListBoardMembers)
+	router.Handle(http.MethodPut, "/api/v1/boards/:id/members/:user_id", w.SetBoardMember)
+	router.Handle(http.MethodDelete, "/api/v1/boards/:id/members/:user_id", w.RemoveBoardMember)
+	router.Handle(http.MethodGet, "/api/v1/members", w.ListWorkspaceMembers)
+	router.Handle(http.MethodPut, "/api/v1/members/:user_id", w.SetWorkspaceMember)
+	router.Handle(http.MethodDelete, "/api/v1/members/:user_id", w.RemoveWorkspaceMember)
+}
+
+// ProfileServer операции с тегом "profile"
+type ProfileServer interface {
+	// Профиль текущего пользователя
+	// GET /api/v1/profile
+	GetProfile(c *gin.Context)
+}
+
+type profileWrapper struct {
+	handler      ProfileServer
+	errorHandler func(*gin.Context, error)
+}
+
+func (w *profileWrapper) GetProfile(c *gin.Context) {
+	w.handler.GetProfile(c)
+}
+
+// RegisterProfileHandlers регистрирует операции ProfileServer по путям из спецификации
+func RegisterProfileHandlers(router gin.IRoutes, si ProfileServer, opts HandlerOptions) {
+	w := &profileWrapper{handler: si, errorHandler: opts.errorHandler()}
+	router.Handle(http.MethodGet, "/api/v1/profile", w.GetProfile)
+}
+
+// TasksServer операции с тегом "tasks"
+type TasksServer interface {
+	// Расписание и критический путь для всех задач
+	// GET /api/v1/schedule
+	GetSchedule(c *gin.Context, params GetScheduleParams)
+	// Получить список задач
+	// GET /api/v1/tasks
+	ListTasks(c *gin.Context, params ListTasksParams)
+	// Создать новую задачу
+	// POST /api/v1/tasks
+	CreateTask(c *gin.Context, body TaskInput)
+	// Перенести задачи в архив
+	// POST /api/v1/tasks/archive
+	ArchiveTasks(c *gin.Context, body ArchiveRequest)
+	// Вернуть задачи из архива
+	// POST /api/v1/tasks/unarchive
+	UnarchiveTasks(c *gin.Context, body ArchiveRequest)
+	// Получить задачу по ID
+	// GET /api/v1/tasks/{id}
+	GetTask(c *gin.Context, id uuid.UUID)
+	// Обновить задачу полностью
+	// PUT /api/v1/tasks/{id}
+	UpdateTask(c *gin.Context, id uuid.UUID, body TaskInput)
+	// Частичное обновление задачи
+	// PATCH /api/v1/tasks/{id}
+	PatchTask(c *gin.Context, id uuid.UUID, body TaskPatch)
+	// Перенести задачу в корзину
+	// DELETE /api/v1/tasks/{id}
+	DeleteTask(c *gin.Context, id uuid.UUID)
+	// Назначить исполнителя задачи
+	// POST /api/v1/tasks/{id}/assignees
+	AddTaskAssignee(c *gin.Context, id uuid.UUID, body AddAssigneeRequest)
+	// Снять исполнителя задачи
+	// DELETE /api/v1/tasks/{id}/assignees/{user_id}
+	RemoveTaskAssignee(c *gin.Context, id uuid.UUID, userID int)
+	// Получить задачу с подзадачами и родительскими задачами
+	// GET /api/v1/tasks/{id}/dependencies
+	GetTaskWithDependencies(c *gin.Context, id uuid.UUID)
+	// Добавить зависимую задачу
+	// POST /api/v1/tasks/{id}/dependencies
+	AddTaskDependency(c *gin.Context, id uuid.UUID, body AddDependencyRequest)
+	// Удалить зависимость между задачами
+	// DELETE /api/v1/tasks/{id}/dependencies/{dependent_id}
+	RemoveTaskDependency(c *gin.Context, id uuid.UUID, dependentID uuid.UUID, params RemoveTaskDependencyParams)
+	// Получить граф связей задачи
+	// GET /api/v1/tasks/{id}/graph
+	GetTaskGraph(c *gin.Context, id uuid.UUID, params GetTaskGraphParams)
+	// История изменений задачи
+	// GET /api/v1/tasks/{id}/history
+	GetTaskHistory(c *gin.Context, id uuid.UUID, params GetTaskHistoryParams)
+	// Переместить задачу в другое Kanban-пространство
+	// POST /api/v1/tasks/{id}/move
+	MoveTask(c *gin.Context, id uuid.UUID, body MoveTaskRequest)
+	// Восстановить задачу из корзины
+	// POST /api/v1/tasks/{id}/restore
+	RestoreTask(c *gin.Context, id uuid.UUID)
+	// Расписание и критический путь для задачи
+	// GET /api/v1/tasks/{id}/schedule
+	GetTaskSchedule(c *gin.Context, id uuid.UUID)
+	// Переходы, доступные задаче из текущего статуса
+	// GET /api/v1/tasks/{id}/transitions
+	GetTaskTransitions(c *gin.Context, id uuid.UUID)
+	// Наблюдатели задачи
+	// GET /api/v1/tasks/{id}/watchers
+	ListTaskWatchers(c *gin.Context, id uuid.UUID)
+	// Подписать пользователя на задачу
+	// POST /api/v1/tasks/{id}/watchers
+	AddTaskWatcher(c *gin.Context, id uuid.UUID, body AddWatcherRequest)
+	// Отписать пользователя от задачи
+	// DELETE /api/v1/tasks/{id}/watchers/{user_id}
+	RemoveTaskWatcher(c *gin.Context, id uuid.UUID, userID int)
+	// Задачи в корзине
+	// GET /api/v1/trash
+	ListTrash(c *gin.Context, params ListTrashParams)
+}
+
+type tasksWrapper struct {
+	handler      TasksServer
+	errorHandler func(*gin.Context, error)
+}
+
+func (w *tasksWrapper) GetSchedule(c *gin.Context) {
+	var params GetScheduleParams
+	if raw, ok := c.GetQuery("board_id"); ok {
+		v, err := uuid.Parse(raw)
+		if err != nil {
+			w.errorHandler(c, &InvalidParamError{Name: "board_id", In: "query", Err: err})
+			return
+		}
+		params.BoardID = &v
+	}
+	w.handler.GetSchedule(c, params)
+}
+
+func (w *tasksWrapper) ListTasks(c *gin.Context) {
+	var params ListTasksParams
+	if raw, ok := c.GetQuery("board_id"); ok {
+		v, err := uuid.Parse(raw)
+		if err != nil {
+			w.errorHandler(c, &InvalidParamError{Name: "board_id", In: "query", Err: err})
+			return
+		}
+		params.BoardID = &v
+	}
+	if raw, ok := c.GetQuery("column_id"); ok {
+		v, err := uuid.Parse(raw)
+		if err != nil {
+			w.errorHandler(c, &InvalidParamError{Name: "column_id", In: "query", Err: err})
+			return
+		}
+		params.ColumnID = &v
+	}
+	if raw, ok := c.GetQuery("kanban_space"); ok {
+		v := raw
+		params.KanbanSpace = &v
+	}
+	if raw, ok := c.GetQuery("status"); ok {
+		v := raw
+		params.Status = &v
+	}
+	if raw, ok := c.GetQuery("priority"); ok {
+		v := TaskPriority(raw)
+		if !v.Valid() {
+			err := fmt.Errorf("unexpected value %q", raw)
+			w.errorHandler(c, &InvalidParamError{Name: "priority", In: "query", Err: err})
+			return
+		}
+		params.Priority = &v
+	}
+	if raw, ok := c.GetQuery("owner_id"); ok {
+		v, err := strconv.Atoi(raw)
+		if err != nil {
+			w.errorHandler(c, &InvalidParamError{Name: "owner_id", In: "query", Err: err})
+			return
+		}
+		params.OwnerID = &v
+	}
+	if raw, ok := c.GetQuery("assignee_id"); ok {
+		v, err := strconv.Atoi(raw)
+		if err != nil {
+			w.errorHandler(c, &InvalidParamError{Name: "assignee_id", In: "query", Err: err})
+			return
+		}
+		params.AssigneeID = &v
+	}
+	params.Mine = false
+	if raw, ok := c.GetQuery("mine"); ok {
+		v, err := strconv.ParseBool(raw)
+		if err != nil {
+			w.errorHandler(c, &InvalidParamError{Name: "mine", In: "query", Err: err})
+			return
+		}
+		params.Mine = v
+	}
+	params.Archived = ListTasksParamsArchivedExclude
+	if raw, ok := c.GetQuery("archived"); ok {
+		v := ListTasksParamsArchived(raw)
+		if !v.Valid() {
+			err := fmt.Errorf("unexpected value %q", raw)
+			w.errorHandler(c, &InvalidParamError{Name: "archived", In: "query", Err: err})
+			return
+		}
+		params.Archived = v
+	}
+	if raw, ok := c.GetQuery("due_from"); ok {
+		v, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			w.errorHandler(c, &InvalidParamError{Name: "due_from", In: "query", Err: err})
+			return
+		}
+		params.DueFrom = &v
+	}
+	if raw, ok := c.GetQuery("due_to"); ok {
+		v, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			w.errorHandler(c, &InvalidParamError{Name: "due_to", In: "query", Err: err})
+			return
+		}
+		params.DueTo = &v
+	}
+	if raw, ok := c.GetQuery("created_from"); ok {
+		v, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			w.errorHandler(c, &InvalidParamError{Name: "created_from", In: "query", Err: err})
+			return
+		}
+		params.CreatedFrom = &v
+	}
+	if raw, ok := c.GetQuery("created_to"); ok {
+		v, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			w.errorHandler(c, &InvalidParamError{Name: "created_to", In: "query", Err: err})
+			return
+		}
+		params.CreatedTo = &v
+	}
+	if raw, ok := c.GetQuery("updated_from"); ok {
+		v, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			w.errorHandler(c, &InvalidParamError{Name: "updated_from", In: "query", Err: err})
+			return
+		}
+		params.UpdatedFrom = &v
+	}
+	if raw, ok := c.GetQuery("updated_to"); ok {
+		v, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			w.errorHandler(c, &InvalidParamError{Name: "updated_to", In: "query", Err: err})
+			return
+		}
+		params.UpdatedTo = &v
+	}
+	params.SortBy = ListTasksParamsSortByCreatedAt
+	if raw, ok := c.GetQuery("sort_by"); ok {
+		v := ListTasksParamsSortBy(raw)
+		if !v.Valid() {
+			err := fmt.Errorf("unexpected value %q", raw)
+			w.errorHandler(c, &InvalidParamError{Name: "sort_by", In: "query", Err: err})
+			return
+		}
+		params.SortBy = v
+	}
+	params.Order = ListTasksParamsOrderDesc
+	if raw, ok := c.GetQuery("order"); ok {
+		v := ListTasksParamsOrder(raw)
+		if !v.Valid() {
+			err := fmt.Errorf("unexpected value %q", raw)
+			w.errorHandler(c, &InvalidParamError{Name: "order", In: "query", Err: err})
+			return
+		}
+		params.Order = v
+	}
+	params.Limit = 50
+	if raw, ok := c.GetQuery("limit"); ok {
+		v, err := strconv.Atoi(raw)
+		if err != nil {
+			w.errorHandler(c, &InvalidParamError{Name: "limit", In: "query", Err: err})
+			return
+		}
+		if v < 1 {
+			err := errors.New("must be >= 1")
+			w.errorHandler(c, &InvalidParamError{Name: "limit", In: "query", Err: err})
+			return
+		}
+		if v > 200 {
+			err := errors.New("must be <= 200")
+			w.errorHandler(c, &InvalidParamError{Name: "limit", In: "query", Err: err})
+			return
+		}
+		params.Limit = v
+	}
+	params.Offset = 0
+	if raw, ok := c.GetQuery("offset"); ok {
+		v, err := strconv.Atoi(raw)
+		if err != nil {
+			w.errorHandler(c, &InvalidParamError{Name: "offset", In: "query", Err: err})
+			return
+		}
+		if v < 0 {
+			err := errors.New("must be >= 0")
+			w.errorHandler(c, &InvalidParamError{Name: "offset", In: "query", Err: err})
+			return
+		}
+		params.Offset = v
+	}
+	if raw, ok := c.GetQuery("cursor"); ok {
+		v := raw
+		params.Cursor = &v
+	}
+	w.handler.ListTasks(c, params)
+}
+
+func (w *tasksWrapper) CreateTask(c *gin.Context) {
+	var body TaskInput
+	if err := decodeJSONBody(c, &body, false); err != nil {
+		w.errorHandler(c, err)
+		return
+	}
+	w.handler.CreateTask(c, body)
+}
+
+func (w *tasksWrapper) ArchiveTasks(c *gin.Context) {
+	var body ArchiveRequest
+	if err := decodeJSONBody(c, &body, true); err != nil {
+		w.errorHandler(c, err)
+		return
+	}
+	w.handler.ArchiveTasks(c, body)
+}
+
+func (w *tasksWrapper) UnarchiveTasks(c *gin.Context) {
+	var body ArchiveRequest
+	if err := decodeJSONBody(c, &body, true); err != nil {
+		w.errorHandler(c, err)
+		return
+	}
+	w.handler.UnarchiveTasks(c, body)
+}
+
+func (w *tasksWrapper) GetTask(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		w.errorHandler(c, &InvalidParamError{Name: "id", In: "path", Err: err})
+		return
+	}
+	w.handler.GetTask(c, id)
+}
+
+func (w *tasksWrapper) UpdateTask(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		w.errorHandler(c, &InvalidParamError{Name: "id", In: "path", Err: err})
+		return
+	}
+	var body TaskInput
+	if err := decodeJSONBody(c, &body, false); err != nil {
+		w.errorHandler(c, err)
+		return
+	}
+	w.handler.UpdateTask(c, id, body)
+}
+
+func (w *tasksWrapper) PatchTask(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		w.errorHandler(c, &InvalidParamError{Name: "id", In: "path", Err: err})
+		return
+	}
+	var body TaskPatch
+	if err := decodeJSONBody(c, &body, true); err != nil {
+		w.errorHandler(c, err)
+		return
+	}
+	w.handler.PatchTask(c, id, body)
+}
+
+func (w *tasksWrapper) DeleteTask(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		w.errorHandler(c, &InvalidParamError{Name: "id", In: "path", Err: err})
+		return
+	}
+	w.handler.DeleteTask(c, id)
+}
+
+func (w *tasksWrapper) AddTaskAssignee(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		w.errorHandler(c, &InvalidParamError{Name: "id", In: "path", Err: err})
+		return
+	}
+	var body AddAssigneeRequest
+	if err := decodeJSONBody(c, &body, false); err != nil {
+		w.errorHandler(c, err)
+		return
+	}
+	w.handler.AddTaskAssignee(c, id, body)
+}
+
+func (w *tasksWrapper) RemoveTaskAssignee(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		w.errorHandler(c, &InvalidParamError{Name: "id", In: "path", Err: err})
+		return
+	}
+	userID, err := strconv.Atoi(c.Param("user_id"))
+	if err != nil {
+		w.errorHandler(c, &InvalidParamError{Name: "user_id", In: "path", Err: err})
+		return
+	}
+	w.handler.RemoveTaskAssignee(c, id, userID)
+}
+
+func (w *tasksWrapper) GetTaskWithDependencies(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		w.errorHandler(c, &InvalidParamError{Name: "id", In: "path", Err: err})
+		return
+	}
+	w.handler.GetTaskWithDependencies(c, id)
+}
+
+func (w *tasksWrapper) AddTaskDependency(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		w.errorHandler(c, &InvalidParamError{Name: "id", In: "path", Err: err})
+		return
+	}
+	var body AddDependencyRequest
+	if err := decodeJSONBody(c, &body, false); err != nil {
+		w.errorHandler(c, err)
+		return
+	}
+	w.handler.AddTaskDependency(c, id, body)
+}
+
+func (w *tasksWrapper) RemoveTaskDependency(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		w.errorHandler(c, &InvalidParamError{Name: "id", In: "path", Err: err})
+		return
+	}
+	dependentID, err := uuid.Parse(c.Param("dependent_id"))
+	if err != nil {
+		w.errorHandler(c, &InvalidParamError{Name: "dependent_id", In: "path", Err: err})
+		return
+	}
+	var params RemoveTaskDependencyParams
+	if raw, ok := c.GetQuery("relation"); ok {
+		v := TaskRelation(raw)
+		if !v.Valid() {
+			err := fmt.Errorf("unexpected value %q", raw)
+			w.errorHandler(c, &InvalidParamError{Name: "relation", In: "query", Err: err})
+			return
+		}
+		params.Relation = &v
+	}
+	w.handler.RemoveTaskDependency(c, id, dependentID, params)
+}
+
+func (w *tasksWrapper) GetTaskGraph(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		w.errorHandler(c, &InvalidParamError{Name: "id", In: "path", Err: err})
+		return
+	}
+	var params GetTaskGraphParams
+	params.Direction = GetTaskGraphParamsDirectionDown
+	if raw, ok := c.GetQuery("direction"); ok {
+		v := GetTaskGraphParamsDirection(raw)
+		if !v.Valid() {
+			err := fmt.Errorf("unexpected value %q", raw)
+			w.errorHandler(c, &InvalidParamError{Name: "direction", In: "query", Err: err})
+			return
+		}
+		params.Direction = v
+	}
+	params.Depth = 3
+	if raw, ok := c.GetQuery("depth"); ok {
+		v, err := strconv.Atoi(raw)
+		if err != nil {
+			w.errorHandler(c, &InvalidParamError{Name: "depth", In: "query", Err: err})
+			return
+		}
+		if v < 1 {
+			err := errors.New("must be >= 1")
+			w.errorHandler(c, &InvalidParamError{Name: "depth", In: "query", Err: err})
+			return
+		}
+		if v > 10 {
+			err := errors.New("must be <= 10")
+			w.errorHandler(c, &InvalidParamError{Name: "depth", In: "query", Err: err})
+			return
+		}
+		params.Depth = v
+	}
+	if raw, ok := c.GetQuery("relation"); ok {
+		v := TaskRelation(raw)
+		if !v.Valid() {
+			err := fmt.Errorf("unexpected value %q", raw)
+			w.errorHandler(c, &InvalidParamError{Name: "relation", In: "query", Err: err})
+			return
+		}
+		params.Relation = &v
+	}
+	params.Format = GetTaskGraphParamsFormatJSON
+	if raw, ok := c.GetQuery("format"); ok {
+		v := GetTaskGraphParamsFormat(raw)
+		if !v.Valid() {
+			err := fmt.Errorf("unexpected value %q", raw)
+			w.errorHandler(c, &InvalidParamError{Name: "format", In: "query", Err: err})
+			return
+		}
+		params.Format = v
+	}
+	w.handler.GetTaskGraph(c, id, params)
+}
+
+func (w *tasksWrapper) GetTaskHistory(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		w.errorHandler(c, &InvalidParamError{Name: "id", In: "path", Err: err})
+		return
+	}
+	var params GetTaskHistoryParams
+	params.Limit = 50
+	if raw, ok := c.GetQuery("limit"); ok {
+		v, err := strconv.Atoi(raw)
+		if err != nil {
+			w.errorHandler(c, &InvalidParamError{Name: "limit", In: "query", Err: err})
+			return
+		}
+		if v < 1 {
+			err := errors.New("must be >= 1")
+			w.errorHandler(c, &InvalidParamError{Name: "limit", In: "query", Err: err})
+			return
+		}
+		if v > 200 {
+			err := errors.New("must be <= 200")
+			w.errorHandler(c, &InvalidParamError{Name: "limit", In: "query", Err: err})
+			return
+		}
+		params.Limit = v
+	}
+	params.Offset = 0
+	if raw, ok := c.GetQuery("offset"); ok {
+		v, err := strconv.Atoi(raw)
+		if err != nil {
+			w.errorHandler(c, &InvalidParamError{Name: "offset", In: "query", Err: err})
+			return
+		}
+		if v < 0 {
+			err := errors.New("must be >= 0")
+			w.errorHandler(c, &InvalidParamError{Name: "offset", In: "query", Err: err})
+			return
+		}
+		params.Offset = v
+	}
+	w.handler.GetTaskHistory(c, id, params)
+}
+
+func (w *tasksWrapper) MoveTask(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		w.errorHandler(c, &InvalidParamError{Name: "id", In: "path", Err: err})
+		return
+	}
+	var body MoveTaskRequest
+	if err := decodeJSONBody(c, &body, false); err != nil {
+		w.errorHandler(c, err)
+		return
+	}
+	w.handler.MoveTask(c, id, body)
+}
+
+func (w *tasksWrapper) RestoreTask(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		w.errorHandler(c, &InvalidParamError{Name: "id", In: "path", Err: err})
+		return
+	}
+	w.handler.RestoreTask(c, id)
+}
+
+func (w *tasksWrapper) GetTaskSchedule(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		w.errorHandler(c, &InvalidParamError{Name: "id", In: "path", Err: err})
+		return
+	}
+	w.handler.GetTaskSchedule(c, id)
+}
+
+func (w *tasksWrapper) GetTaskTransitions(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		w.errorHandler(c, &InvalidParamError{Name: "id", In: "path", Err: err})
+		return
+	}
+	w.handler.GetTaskTransitions(c, id)
+}
+
+func (w *tasksWrapper) ListTaskWatchers(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		w.errorHandler(c, &InvalidParamError{Name: "id", In: "path", Err: err})
+		return
+	}
+	w.handler.ListTaskWatchers(c, id)
+}
+
+func (w *tasksWrapper) AddTaskWatcher(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		w.errorHandler(c, &InvalidParamError{Name: "id", In: "path", Err: err})
+		return
+	}
+	var body AddWatcherRequest
+	if err := decodeJSONBody(c, &body, false); err != nil {
+		w.errorHandler(c, err)
+		return
+	}
+	w.handler.AddTaskWatcher(c, id, body)
+}
+
+func (w *tasksWrapper) RemoveTaskWatcher(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		w.errorHandler(c, &InvalidParamError{Name: "id", In: "path", Err: err})
+		return
+	}
+	userID, err := strconv.Atoi(c.Param("user_id"))
+	if err != nil {
+		w.errorHandler(c, &InvalidParamError{Name: "user_id", In: "path", Err: err})
+		return
+	}
+	w.handler.RemoveTaskWatcher(c, id, userID)
+}
+
+func (w *tasksWrapper) ListTrash(c *gin.Context) {
+	var params ListTrashParams
+	if raw, ok := c.GetQuery("board_id"); ok {
+		v, err := uuid.Parse(raw)
+		if err != nil {
+			w.errorHandler(c, &InvalidParamError{Name: "board_id", In: "query", Err: err})
+			return
+		}
+		params.BoardID = &v
+	}
+	params.Limit = 50
+	if raw, ok := c.GetQuery("limit"); ok {
+		v, err := strconv.Atoi(raw)
+		if err != nil {
+			w.errorHandler(c, &InvalidParamError{Name: "limit", In: "query", Err: err})
+			return
+		}
+		if v < 1 {
+			err := errors.New("must be >= 1")
+			w.errorHandler(c, &InvalidParamError{Name: "limit", In: "query", Err: err})
+			return
+		}
+		if v > 200 {
+			err := errors.New("must be <= 200")
+			w.errorHandler(c, &InvalidParamError{Name: "limit", In: "query", Err: err})
+			return
+		}
+		params.Limit = v
+	}
+	params.Offset = 0
+	if raw, ok := c.GetQuery("offset"); ok {
+		v, err := strconv.Atoi(raw)
+		if err != nil {
+			w.errorHandler(c, &InvalidParamError{Name: "offset", In: "query", Err: err})
+			return
+		}
+		if v < 0 {
+			err := errors.New("must be >= 0")
+			w.errorHandler(c, &InvalidParamError{Name: "offset", In: "query", Err: err})
+			return
+		}
+		params.Offset = v
+	}
+	w.handler.ListTrash(c, params)
+}
+
+// RegisterTasksHandlers регистрирует операции TasksServer по путям из спецификации
+func RegisterTasksHandlers(router gin.IRoutes, si TasksServer, opts HandlerOptions) {
+	w := &tasksWrapper{handler: si, errorHandler: opts.errorHandler()}
+	router.Handle(http.MethodGet, "/api/v1/schedule", w.GetSchedule)
+	router.Handle(http.MethodGet, "/api/v1/tasks", w.ListTasks)
+	router.Handle(http.MethodPost, "/api/v1/tasks", w.CreateTask)
+	router.Handle(http.MethodPost, "/api/v1/tasks/archive", w.ArchiveTasks)
+	router.Handle(http.MethodPost, "/api/v1/tasks/unarchive", w.UnarchiveTasks)
+	router.Handle(http.MethodGet, "/api/v1/tasks/:id", w.GetTask)
+	router.Handle(http.MethodPut, "/api/v1/tasks/:id", w.UpdateTask)
+	router.Handle(http.MethodPatch, "/api/v1/tasks/:id", w.PatchTask)
+	router.Handle(http.MethodDelete, "/api/v1/tasks/:id", w.DeleteTask)
+	router.Handle(http.MethodPost, "/api/v1/tasks/:id/assignees", w.AddTaskAssignee)
+	router.Handle(http.MethodDelete, "/api/v1/tasks/:id/assignees/:user_id", w.RemoveTaskAssignee)
+	router.Handle(http.MethodGet, "/api/v1/tasks/:id/dependencies", w.GetTaskWithDependencies)
+	router.Handle(http.MethodPost, "/api/v1/tasks/:id/dependencies", w.AddTaskDependency)
+	router.Handle(http.MethodDelete, "/api/v1/tasks/:id/dependencies/:dependent_id", w.RemoveTaskDependency)
+	router.Handle(http.MethodGet, "/api/v1/tasks/:id/graph", w.GetTaskGraph)
+	router.Handle(http.MethodGet, "/api/v1/tasks/:id/history", w.GetTaskHistory)
+	router.Handle(http.MethodPost, "/api/v1/tasks/:id/move", w.MoveTask)
+	router.Handle(http.MethodPost, "/api/v1/tasks/:id/restore", w.RestoreTask)
+	router.Handle(http.MethodGet, "/api/v1/tasks/:id/schedule", w.GetTaskSchedule)
+	router.Handle(http.MethodGet, "/api/v1/tasks/:id/transitions", w.GetTaskTransitions)
+	router.Handle(http.MethodGet, "/api/v1/tasks/:id/watchers", w.ListTaskWatchers)
+	router.Handle(http.MethodPost, "/api/v1/tasks/:id/watchers", w.AddTaskWatcher)
+	router.Handle(http.MethodDelete, "/api/v1/tasks/:id/watchers/:user_id", w.RemoveTaskWatcher)
+	router.Handle(http.MethodGet, "/api/v1/trash", w.ListTrash)
+}
@@ -0,0 +1,11 @@
+// Package api содержит OpenAPI-описание HTTP API сервиса.
+package api
+
+import _ "embed"
+
+//go:generate go run ../cmd/openapi-gen -spec openapiv1.yaml -out server.gen.go -package api
+
+// Spec исходный текст api/openapiv1.yaml, встроенный в бинарник
+//
+//go:embed openapiv1.yaml
+var Spec []byte
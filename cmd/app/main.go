@@ -1,20 +1,42 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"log"
 	"os"
 
+	"github.com/TrueSmartcomm/backend/api"
 	"github.com/TrueSmartcomm/backend/config"
+	"github.com/TrueSmartcomm/backend/internal/access"
 	"github.com/TrueSmartcomm/backend/internal/auth"
 	"github.com/TrueSmartcomm/backend/internal/handler"
-	"github.com/TrueSmartcomm/backend/internal/middleware"
+	"github.com/TrueSmartcomm/backend/internal/migrate"
+	"github.com/TrueSmartcomm/backend/internal/openapi"
+	"github.com/TrueSmartcomm/backend/internal/pagination"
 	"github.com/TrueSmartcomm/backend/internal/repository"
+	"github.com/TrueSmartcomm/backend/internal/server"
 	"github.com/TrueSmartcomm/backend/internal/storage"
-	"github.com/gin-contrib/cors"
-	"github.com/gin-gonic/gin"
+	"github.com/TrueSmartcomm/backend/internal/workflow"
+	"github.com/TrueSmartcomm/backend/migrations"
 )
 
 func main() {
+	// app migrate up|down|status|redo|create
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		runMigrate(os.Args[2:])
+		return
+	}
+	// app owner LOGIN
+	if len(os.Args) > 1 && os.Args[1] == "owner" {
+		runOwner(os.Args[2:])
+		return
+	}
+
+	migrateOnStart := flag.Bool("migrate-on-start", false,
+		"применить миграции перед запуском сервера (под advisory lock, безопасно для нескольких реплик)")
+	flag.Parse()
+
 	cfg := config.MustLoad()
 
 	db, err := storage.New(cfg.DatabaseURL)
@@ -23,8 +45,20 @@ func main() {
 	}
 	defer db.Close()
 
+	if *migrateOnStart || cfg.MigrateOnStart {
+		m, err := migrate.New(db.DB, migrations.FS)
+		if err != nil {
+			log.Fatalf("Failed to load migrations: %v", err)
+		}
+		if err := m.Up(context.Background()); err != nil {
+			log.Fatalf("Failed to apply migrations: %v", err)
+		}
+		m.Close()
+	}
+
 	// --- Инициализация зависимостей для аутентификации ---
 	userRepo := repository.NewUserRepository(db.DB) // Репозиторий для пользователей
+	userRepo.DefaultRole = cfg.DefaultWorkspaceRole // Роль новых пользователей в рабочем пространстве
 
 	secretKey := []byte(os.Getenv("SECRET_KEY")) // Получаем ключ из переменной окружения
 	if len(secretKey) == 0 {
@@ -33,81 +67,53 @@ func main() {
 	authService := auth.NewAuthService(userRepo, secretKey) // Сервис аутентификации
 	// --- Конец инициализации аутентификации ---
 
+	// Права пользователей на доски и задачи проверяются в репозиториях
+	checker := access.New()
+
 	// Инициализация репозиториев и хендлеров для задач
 	taskRepo := repository.NewTaskRepository(db.DB)
-	taskHandler := handlers.NewTaskHandler(taskRepo) // Хендлер задач
+	taskRepo.Workflow = workflow.New(cfg.WorkflowParentPolicy)    // Правила переходов между статусами
+	taskRepo.Rebalancer = repository.NewRebalancer(db.DB)         // Выравнивание рангов карточек в колонках
+	cursorCodec := pagination.NewCodec(secretKey)                 // Подпись курсоров пагинации
+	taskHandler := handlers.NewTaskHandler(taskRepo, cursorCodec) // Хендлер задач
+	taskRepo.Access = checker
+	taskRepo.Workflow.Advancer = taskRepo // Автоматический перевод родителей пишется в историю
+	boardRepo := repository.NewBoardRepository(db.DB)
+	boardRepo.Access = checker
+	boardHandler := handlers.NewBoardHandler(boardRepo)
+	memberRepo := repository.NewMemberRepository(db.DB)
+	memberRepo.Access = checker
+	memberHandler := handlers.NewMemberHandler(memberRepo) // Роли на досках и в рабочем пространстве
+	go taskRepo.Rebalancer.Run(context.Background())
+	go repository.NewTrashPurger(db.DB, cfg.TrashRetention).Run(context.Background()) // Очистка корзины
+	if cfg.ArchiveAfter > 0 {
+		go repository.NewTaskArchiver(db.DB, cfg.ArchiveAfter).Run(context.Background()) // Архивация завершённых задач
+	}
 
 	// Инициализация хендлеров аутентификации
 	authHandler := handlers.NewAuthHandler(authService) // Хендлер аутентификации
 
-	// --- Настройка маршрутов ---
-	r := gin.New()
-	corsConfig := cors.DefaultConfig()
-
-	corsConfig.AllowOrigins = []string{"*"}
-	corsConfig.AllowMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS"}
-	corsConfig.AllowHeaders = []string{
-		"Origin",
-		"Content-Length",
-		"Content-Type",
-		"Authorization",
+	// Спецификация API: по ней проверяются маршруты, запросы и (в разработке) ответы
+	spec, err := openapi.Load(api.Spec)
+	if err != nil {
+		log.Fatalf("Failed to load OpenAPI spec: %v", err)
 	}
 
-	r.Use(gin.Recovery(), gin.Logger(), cors.New(corsConfig))
-
-	// Healthcheck endpoint (публичный)
-	r.GET("/health", func(ctx *gin.Context) {
-		ctx.JSON(200, gin.H{"status": "ok"})
-	})
-
-	// Проверка подключения к БД (публичный)
-	r.GET("/db-check", func(ctx *gin.Context) {
-		var now string
-		if err := db.DB.QueryRow(ctx, "SELECT NOW()").Scan(&now); err != nil {
-			ctx.JSON(500, gin.H{"error": err.Error()})
-			return
-		}
-		ctx.JSON(200, gin.H{"db_time": now})
+	// --- Настройка маршрутов ---
+	r := server.New(server.Deps{
+		DB:                db.DB,
+		AuthService:       authService,
+		Tasks:             taskHandler,
+		Boards:            boardHandler,
+		Members:           memberHandler,
+		Auth:              authHandler,
+		Spec:              spec,
+		ValidateResponses: cfg.ValidateResponses,
 	})
 
-	// --- Публичные маршруты (не требуют аутентификации) ---
-	// Регистрация
-	r.POST("/api/v1/auth/register", authHandler.RegisterHandler)
-	// Логин
-	r.POST("/api/v1/auth/login", authHandler.LoginHandler)
-	// Обновление токена (публичный маршрут, так как использует refresh токен из тела)
-	r.POST("/api/v1/auth/refresh", authHandler.RefreshTokenHandler)
-
-	// --- Защищённые маршруты (требуют аутентификацию через JWT) ---
-	// Создаём группу маршрутов с middleware
-	authorized := r.Group("/api/v1") // Можно использовать и другой префикс, например /api/v1
-	// Применяем middleware ко всей группе
-	authorized.Use(middleware.AuthRequired(authService)) // Передаём authService для проверки токена
-
-	{
-
-		authorized.POST("/api/v1/tasks", taskHandler.CreateTask)
-		authorized.GET("/api/v1/tasks", taskHandler.GetTask)
-		authorized.PUT("/api/v1/tasks", taskHandler.UpdateTask)
-		authorized.DELETE("/api/v1/tasks", taskHandler.DeleteTask)
-		authorized.POST("/api/v1/tasks/move", taskHandler.MoveTask)
-		authorized.POST("/api/v1/tasks/dependency", taskHandler.AddTaskDependency)
-		authorized.DELETE("/api/v1/tasks/dependency", taskHandler.RemoveTaskDependency)
-		authorized.GET("/api/v1/tasks/with-dependencies", taskHandler.GetTaskWithDependencies)
-
-		authorized.GET("/api/v1/profile", func(c *gin.Context) {
-			userID, exists := middleware.GetUserIDFromContext(c)
-			if !exists {
-
-				log.Println("Main: user_id not found in context after AuthRequired middleware")
-				c.JSON(500, gin.H{"error": "Internal server error"})
-				return
-			}
-			c.JSON(200, gin.H{
-				"message": "Welcome to your profile!",
-				"user_id": userID,
-			})
-		})
+	// Маршруты должны совпадать с api/openapiv1.yaml
+	if err := r.CheckSpec(spec); err != nil {
+		log.Fatal(err)
 	}
 
 	// HTTP сервер
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/TrueSmartcomm/backend/config"
+	"github.com/TrueSmartcomm/backend/internal/migrate"
+	"github.com/TrueSmartcomm/backend/internal/storage"
+	"github.com/TrueSmartcomm/backend/migrations"
+)
+
+const migrateUsage = `usage: app migrate <command>
+
+commands:
+  up             применить все новые миграции
+  down           откатить последнюю миграцию
+  status         показать состояние миграций
+  redo           откатить и заново применить последнюю миграцию
+  create NAME    создать файл новой миграции в -dir
+`
+
+// runMigrate обрабатывает подкоманду `app migrate ...`
+func runMigrate(args []string) {
+	fs := flag.NewFlagSet("migrate", flag.ExitOnError)
+	dir := fs.String("dir", "migrations", "каталог миграций для create")
+	fs.Usage = func() {
+		fmt.Fprint(os.Stderr, migrateUsage)
+		fs.PrintDefaults()
+	}
+	_ = fs.Parse(args)
+
+	if fs.NArg() == 0 {
+		fs.Usage()
+		os.Exit(2)
+	}
+	command := fs.Arg(0)
+
+	// create работает с файлами в репозитории, база не нужна
+	if command == "create" {
+		if fs.NArg() != 2 {
+			fs.Usage()
+			os.Exit(2)
+		}
+		path, err := migrate.Create(*dir, fs.Arg(1))
+		if err != nil {
+			log.Fatalf("create migration: %v", err)
+		}
+		log.Printf("created %s", path)
+		return
+	}
+
+	cfg := config.MustLoad()
+	db, err := storage.New(cfg.DatabaseURL)
+	if err != nil {
+		log.Fatalf("Failed to connect DB: %v", err)
+	}
+	defer db.Close()
+
+	m, err := migrate.New(db.DB, migrations.FS)
+	if err != nil {
+		log.Fatalf("load migrations: %v", err)
+	}
+	defer m.Close()
+
+	ctx := context.Background()
+	switch command {
+	case "up":
+		err = m.Up(ctx)
+	case "down":
+		err = m.Down(ctx)
+	case "redo":
+		err = m.Redo(ctx)
+	case "status":
+		var statuses []migrate.Status
+		statuses, err = m.Status(ctx)
+		for _, st := range statuses {
+			applied := "Pending"
+			if st.AppliedAt != nil {
+				applied = st.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Printf("%-25s %s\n", applied, st.Name)
+		}
+	default:
+		fs.Usage()
+		os.Exit(2)
+	}
+	if err != nil {
+		log.Fatalf("migrate %s: %v", command, err)
+	}
+}
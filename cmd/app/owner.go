@@ -0,0 +1,45 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/TrueSmartcomm/backend/config"
+	"github.com/TrueSmartcomm/backend/internal/access"
+	"github.com/TrueSmartcomm/backend/internal/repository"
+	"github.com/TrueSmartcomm/backend/internal/storage"
+)
+
+const ownerUsage = `usage: app owner LOGIN
+
+назначить зарегистрированного пользователя LOGIN владельцем рабочего пространства
+`
+
+// runOwner обрабатывает подкоманду `app owner LOGIN`. Сервер никого не назначает владельцем
+// сам: первого владельца создаёт оператор, дальше владельцы назначаются через API.
+func runOwner(args []string) {
+	if len(args) != 1 {
+		fmt.Fprint(os.Stderr, ownerUsage)
+		os.Exit(2)
+	}
+
+	cfg := config.MustLoad()
+	db, err := storage.New(cfg.DatabaseURL)
+	if err != nil {
+		log.Fatalf("Failed to connect DB: %v", err)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	user, err := repository.NewUserRepository(db.DB).GetUserByLogin(ctx, args[0])
+	if err != nil {
+		log.Fatalf("owner %s: %v", args[0], err)
+	}
+	// без Checker права не проверяются: команду запускает оператор с доступом к базе
+	if _, err := repository.NewMemberRepository(db.DB).SetMember(ctx, nil, user.ID, access.RoleOwner); err != nil {
+		log.Fatalf("owner %s: %v", args[0], err)
+	}
+	log.Printf("%s is now a workspace owner", user.Login)
+}
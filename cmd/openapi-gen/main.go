@@ -0,0 +1,671 @@
+// Команда openapi-gen генерирует из api/openapiv1.yaml типы запросов и ответов,
+// интерфейсы серверов (по одному на тег) и обёртки для gin, которые разбирают
+// параметры пути, query-параметры и тело запроса в типизированные значения.
+//
+// Запуск: go generate ./api
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/TrueSmartcomm/backend/internal/openapi"
+)
+
+func main() {
+	specPath := flag.String("spec", "openapiv1.yaml", "путь к OpenAPI-документу")
+	outPath := flag.String("out", "server.gen.go", "файл для сгенерированного кода")
+	pkg := flag.String("package", "api", "имя пакета")
+	flag.Parse()
+
+	raw, err := os.ReadFile(*specPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	doc, err := openapi.Load(raw)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	g := newGenerator(doc)
+	if err := g.run(); err != nil {
+		log.Fatal(err)
+	}
+
+	src, err := format.Source(g.output(*pkg))
+	if err != nil {
+		log.Fatalf("format generated code: %v", err)
+	}
+	if err := os.WriteFile(*outPath, src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// operation операция спецификации с вычисленными для генерации данными
+type operation struct {
+	id         string
+	method     string
+	path       string
+	summary    string
+	tag        string
+	pathParams []param
+	query      []param
+	paramsType string // имя структуры query-параметров или ""
+	bodyType   string // тип тела запроса или ""
+	bodyStrict bool   // additionalProperties: false
+}
+
+type param struct {
+	name     string
+	field    string
+	goType   string
+	schema   *openapi.Schema
+	required bool
+	optional bool // поле-указатель в структуре параметров
+	enum     bool // значение проверяется методом Valid()
+}
+
+type generator struct {
+	doc          *openapi.Document
+	types        map[string]string // имя типа -> объявление
+	ops          []*operation
+	imports      map[string]bool
+	usesNullable bool
+}
+
+func newGenerator(doc *openapi.Document) *generator {
+	return &generator{
+		doc:     doc,
+		types:   map[string]string{},
+		imports: map[string]bool{},
+	}
+}
+
+func (g *generator) run() error {
+	names := make([]string, 0, len(g.doc.Components.Schemas))
+	for name := range g.doc.Components.Schemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := g.namedType(name, g.doc.Components.Schemas[name]); err != nil {
+			return err
+		}
+	}
+
+	paths := make([]string, 0, len(g.doc.Paths))
+	for path := range g.doc.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	seen := map[string]string{}
+	for _, path := range paths {
+		item := g.doc.Paths[path]
+		for _, method := range openapi.Methods {
+			op := item.Operation(method)
+			if op == nil {
+				continue
+			}
+			if op.OperationID == "" {
+				return fmt.Errorf("%s %s: operationId is required", method, path)
+			}
+			if prev, ok := seen[op.OperationID]; ok {
+				return fmt.Errorf("operationId %s is used by both %s and %s %s", op.OperationID, prev, method, path)
+			}
+			seen[op.OperationID] = method + " " + path
+			o, err := g.operation(method, path, op)
+			if err != nil {
+				return err
+			}
+			g.ops = append(g.ops, o)
+		}
+	}
+	return nil
+}
+
+func (g *generator) operation(method, path string, op *openapi.Operation) (*operation, error) {
+	o := &operation{id: op.OperationID, method: method, path: path, summary: op.Summary, tag: "default"}
+	if len(op.Tags) > 0 {
+		o.tag = op.Tags[0]
+	}
+
+	for _, p := range op.Parameters {
+		// Заголовки (If-Match и т.п.) читаются обработчиками напрямую
+		if p.In == "header" {
+			continue
+		}
+		goType, err := g.goType(p.Schema, o.id+"Params"+camel(p.Name))
+		if err != nil {
+			return nil, fmt.Errorf("%s parameter %s: %w", o.id, p.Name, err)
+		}
+		prm := param{name: p.Name, field: camel(p.Name), goType: goType, schema: p.Schema, required: p.Required,
+			enum: len(g.doc.Resolve(p.Schema).Enum) > 0}
+		switch p.In {
+		case "path":
+			o.pathParams = append(o.pathParams, prm)
+		case "query":
+			prm.optional = !p.Required && g.doc.Resolve(p.Schema).Default == nil
+			o.query = append(o.query, prm)
+		default:
+			return nil, fmt.Errorf("%s parameter %s: unsupported location %q", o.id, p.Name, p.In)
+		}
+	}
+	if len(o.query) > 0 {
+		o.paramsType = o.id + "Params"
+		g.paramsStruct(o)
+	}
+
+	if op.RequestBody != nil {
+		schema := openapi.JSONSchema(op.RequestBody.Content)
+		if schema == nil {
+			return nil, fmt.Errorf("%s: only application/json request bodies are supported", o.id)
+		}
+		bodyType, err := g.goType(schema, o.id+"JSONBody")
+		if err != nil {
+			return nil, fmt.Errorf("%s request body: %w", o.id, err)
+		}
+		o.bodyType = bodyType
+		resolved := g.doc.Resolve(schema)
+		o.bodyStrict = resolved.AdditionalProperties != nil && !*resolved.AdditionalProperties
+	}
+	return o, nil
+}
+
+// namedType объявляет тип для схемы из components
+func (g *generator) namedType(name string, s *openapi.Schema) error {
+	if _, ok := g.types[name]; ok {
+		return nil
+	}
+	switch {
+	case len(s.Enum) > 0:
+		g.enumType(name, s)
+	case s.Type == "object" || len(s.Properties) > 0:
+		return g.structType(name, s)
+	default:
+		goType, err := g.goType(s, name+"Value")
+		if err != nil {
+			return err
+		}
+		g.types[name] = fmt.Sprintf("%s\ntype %s = %s\n", comment(name, s.Description), name, goType)
+	}
+	return nil
+}
+
+func (g *generator) enumType(name string, s *openapi.Schema) {
+	var b strings.Builder
+	b.WriteString(comment(name, s.Description))
+	fmt.Fprintf(&b, "type %s string\n\n", name)
+	fmt.Fprintf(&b, "// Допустимые значения %s\nconst (\n", name)
+	for _, v := range s.Enum {
+		fmt.Fprintf(&b, "\t%s%s %s = %q\n", name, camel(v), name, v)
+	}
+	b.WriteString(")\n\n")
+	fmt.Fprintf(&b, "// Valid сообщает, входит ли значение в перечисление\nfunc (v %s) Valid() bool {\n\tswitch v {\n\tcase ", name)
+	for i, v := range s.Enum {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString(name + camel(v))
+	}
+	b.WriteString(":\n\t\treturn true\n\t}\n\treturn false\n}\n\n")
+	fmt.Fprintf(&b, "// UnmarshalJSON отклоняет значения вне перечисления\nfunc (v *%s) UnmarshalJSON(data []byte) error {\n", name)
+	b.WriteString("\tvar s string\n\tif err := json.Unmarshal(data, &s); err != nil {\n\t\treturn err\n\t}\n")
+	fmt.Fprintf(&b, "\tif !%s(s).Valid() {\n\t\treturn fmt.Errorf(\"unexpected %s value %%q\", s)\n\t}\n", name, name)
+	fmt.Fprintf(&b, "\t*v = %s(s)\n\treturn nil\n}\n", name)
+	g.types[name] = b.String()
+}
+
+func (g *generator) structType(name string, s *openapi.Schema) error {
+	g.types[name] = "" // защита от рекурсии
+	props := make([]string, 0, len(s.Properties))
+	for p := range s.Properties {
+		props = append(props, p)
+	}
+	sort.Strings(props)
+
+	var b strings.Builder
+	b.WriteString(comment(name, s.Description))
+	fmt.Fprintf(&b, "type %s struct {\n", name)
+	for _, p := range props {
+		ps := s.Properties[p]
+		goType, err := g.goType(ps, name+camel(p))
+		if err != nil {
+			return fmt.Errorf("%s.%s: %w", name, p, err)
+		}
+		resolved := g.doc.Resolve(ps)
+		required := s.IsRequired(p)
+		tag := p
+		switch {
+		case s.MergePatch && !required && resolved.Nullable:
+			g.usesNullable = true
+			goType = "Nullable[" + goType + "]"
+		case required && !resolved.Nullable:
+		case strings.HasPrefix(goType, "[]") || strings.HasPrefix(goType, "map[") || goType == "interface{}":
+			tag += ",omitempty"
+		default:
+			goType = "*" + goType
+			tag += ",omitempty"
+		}
+		if d := firstLine(resolved.Description); d != "" && ps.Ref == "" && len(ps.AllOf) == 0 {
+			fmt.Fprintf(&b, "\t// %s\n", d)
+		}
+		fmt.Fprintf(&b, "\t%s %s `json:%q`\n", camel(p), goType, tag)
+	}
+	b.WriteString("}\n")
+	g.types[name] = b.String()
+	return nil
+}
+
+func (g *generator) paramsStruct(o *operation) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "// %s query-параметры операции %s\ntype %s struct {\n", o.paramsType, o.id, o.paramsType)
+	for _, p := range o.query {
+		t := p.goType
+		if p.optional {
+			t = "*" + t
+		}
+		fmt.Fprintf(&b, "\t%s %s `form:%q`\n", p.field, t, p.name)
+	}
+	b.WriteString("}\n")
+	g.types[o.paramsType] = b.String()
+}
+
+// goType возвращает Go-тип для схемы; inline-перечисления и объекты
+// объявляются как именованные типы с именем hint
+func (g *generator) goType(s *openapi.Schema, hint string) (string, error) {
+	if s == nil {
+		return "", fmt.Errorf("missing schema")
+	}
+	if s.Ref != "" {
+		name := s.RefName()
+		target, ok := g.doc.Components.Schemas[name]
+		if !ok {
+			return "", fmt.Errorf("unknown schema reference %s", s.Ref)
+		}
+		if err := g.namedType(name, target); err != nil {
+			return "", err
+		}
+		return name, nil
+	}
+	if len(s.AllOf) == 1 {
+		return g.goType(s.AllOf[0], hint)
+	}
+
+	switch s.Type {
+	case "string":
+		if len(s.Enum) > 0 {
+			g.enumType(hint, s)
+			return hint, nil
+		}
+		switch s.Format {
+		case "uuid":
+			g.imports["github.com/google/uuid"] = true
+			return "uuid.UUID", nil
+		case "date-time":
+			g.imports["time"] = true
+			return "time.Time", nil
+		}
+		return "string", nil
+	case "integer":
+		return "int", nil
+	case "number":
+		return "float64", nil
+	case "boolean":
+		return "bool", nil
+	case "array":
+		item, err := g.goType(s.Items, hint+"Item")
+		if err != nil {
+			return "", err
+		}
+		return "[]" + item, nil
+	case "object", "":
+		if len(s.Properties) == 0 {
+			// схема без типа допускает любое JSON-значение
+			if s.Type == "" {
+				return "interface{}", nil
+			}
+			return "map[string]interface{}", nil
+		}
+		if err := g.structType(hint, s); err != nil {
+			return "", err
+		}
+		return hint, nil
+	}
+	return "", fmt.Errorf("unsupported schema type %q", s.Type)
+}
+
+func (g *generator) output(pkg string) []byte {
+	var b bytes.Buffer
+	b.WriteString("// Code generated by cmd/openapi-gen from openapiv1.yaml. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&b, "package %s\n\n", pkg)
+
+	imports := []string{"encoding/json", "errors", "fmt", "net/http", "github.com/gin-gonic/gin"}
+	for _, o := range g.ops {
+		for _, p := range append(append([]param{}, o.pathParams...), o.query...) {
+			switch {
+			case p.goType == "int":
+				g.imports["strconv"] = true
+			case p.goType == "time.Time":
+				g.imports["time"] = true
+			case p.goType == "uuid.UUID":
+				g.imports["github.com/google/uuid"] = true
+			}
+		}
+	}
+	for imp := range g.imports {
+		imports = append(imports, imp)
+	}
+	sort.Strings(imports)
+	b.WriteString("import (\n")
+	for _, std := range []bool{true, false} {
+		for _, imp := range imports {
+			if isStd := !strings.Contains(imp, "."); isStd == std {
+				fmt.Fprintf(&b, "\t%q\n", imp)
+			}
+		}
+		if std {
+			b.WriteString("\n")
+		}
+	}
+	b.WriteString(")\n\n")
+
+	if g.usesNullable {
+		b.WriteString(nullableSource)
+	}
+
+	names := make([]string, 0, len(g.types))
+	for name := range g.types {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		b.WriteString(g.types[name])
+		b.WriteString("\n")
+	}
+
+	b.WriteString(runtimeSource)
+
+	byTag := map[string][]*operation{}
+	var tags []string
+	for _, o := range g.ops {
+		if _, ok := byTag[o.tag]; !ok {
+			tags = append(tags, o.tag)
+		}
+		byTag[o.tag] = append(byTag[o.tag], o)
+	}
+	sort.Strings(tags)
+	for _, tag := range tags {
+		g.writeServer(&b, tag, byTag[tag])
+	}
+	return b.Bytes()
+}
+
+func (g *generator) writeServer(b *bytes.Buffer, tag string, ops []*operation) {
+	iface := camel(tag) + "Server"
+	wrapper := lowerFirst(camel(tag)) + "Wrapper"
+
+	fmt.Fprintf(b, "// %s операции с тегом %q\ntype %s interface {\n", iface, tag, iface)
+	for _, o := range ops {
+		fmt.Fprintf(b, "\t// %s\n\t// %s %s\n", o.summary, o.method, o.path)
+		fmt.Fprintf(b, "\t%s(%s)\n", o.id, signature(o))
+	}
+	b.WriteString("}\n\n")
+
+	fmt.Fprintf(b, "type %s struct {\n\thandler %s\n\terrorHandler func(*gin.Context, error)\n}\n\n", wrapper, iface)
+
+	for _, o := range ops {
+		fmt.Fprintf(b, "func (w *%s) %s(c *gin.Context) {\n", wrapper, o.id)
+		args := []string{"c"}
+		for _, p := range o.pathParams {
+			v := lowerFirst(p.field)
+			writeParse(b, p, v, fmt.Sprintf("c.Param(%q)", p.name), "path")
+			args = append(args, v)
+		}
+		if o.paramsType != "" {
+			fmt.Fprintf(b, "\tvar params %s\n", o.paramsType)
+			for _, p := range o.query {
+				writeQueryParam(b, g.doc, p)
+			}
+			args = append(args, "params")
+		}
+		if o.bodyType != "" {
+			fmt.Fprintf(b, "\tvar body %s\n", o.bodyType)
+			fmt.Fprintf(b, "\tif err := decodeJSONBody(c, &body, %t); err != nil {\n\t\tw.errorHandler(c, err)\n\t\treturn\n\t}\n", o.bodyStrict)
+			args = append(args, "body")
+		}
+		fmt.Fprintf(b, "\tw.handler.%s(%s)\n}\n\n", o.id, strings.Join(args, ", "))
+	}
+
+	fmt.Fprintf(b, "// Register%sHandlers регистрирует операции %s по путям из спецификации\n", camel(tag), iface)
+	fmt.Fprintf(b, "func Register%sHandlers(router gin.IRoutes, si %s, opts HandlerOptions) {\n", camel(tag), iface)
+	fmt.Fprintf(b, "\tw := &%s{handler: si, errorHandler: opts.errorHandler()}\n", wrapper)
+	for _, o := range ops {
+		fmt.Fprintf(b, "\trouter.Handle(http.Method%s, %q, w.%s)\n", methodConst(o.method), openapi.GinPath(o.path), o.id)
+	}
+	b.WriteString("}\n\n")
+}
+
+func signature(o *operation) string {
+	parts := []string{"c *gin.Context"}
+	for _, p := range o.pathParams {
+		parts = append(parts, lowerFirst(p.field)+" "+p.goType)
+	}
+	if o.paramsType != "" {
+		parts = append(parts, "params "+o.paramsType)
+	}
+	if o.bodyType != "" {
+		parts = append(parts, "body "+o.bodyType)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// writeParse генерирует разбор строкового значения src в переменную dst
+func writeParse(b *bytes.Buffer, p param, dst, src, in string) {
+	fail := fmt.Sprintf("\t\tw.errorHandler(c, &InvalidParamError{Name: %q, In: %q, Err: err})\n\t\treturn\n", p.name, in)
+	switch p.goType {
+	case "uuid.UUID":
+		fmt.Fprintf(b, "\t%s, err := uuid.Parse(%s)\n\tif err != nil {\n%s\t}\n", dst, src, fail)
+	case "int":
+		fmt.Fprintf(b, "\t%s, err := strconv.Atoi(%s)\n\tif err != nil {\n%s\t}\n", dst, src, fail)
+	case "bool":
+		fmt.Fprintf(b, "\t%s, err := strconv.ParseBool(%s)\n\tif err != nil {\n%s\t}\n", dst, src, fail)
+	case "time.Time":
+		fmt.Fprintf(b, "\t%s, err := time.Parse(time.RFC3339, %s)\n\tif err != nil {\n%s\t}\n", dst, src, fail)
+	case "string":
+		fmt.Fprintf(b, "\t%s := %s\n", dst, src)
+	default:
+		if !p.enum { // именованный строковый тип без перечисления
+			fmt.Fprintf(b, "\t%s := %s\n", dst, src)
+			return
+		}
+		fmt.Fprintf(b, "\t%s := %s(%s)\n\tif !%s.Valid() {\n\t\terr := fmt.Errorf(\"unexpected value %%q\", %s)\n%s\t}\n",
+			dst, p.goType, src, dst, src, fail)
+	}
+}
+
+func writeQueryParam(b *bytes.Buffer, doc *openapi.Document, p param) {
+	s := doc.Resolve(p.schema)
+	if s.Default != nil {
+		fmt.Fprintf(b, "\tparams.%s = %s\n", p.field, literal(p.goType, s.Default))
+	}
+	fmt.Fprintf(b, "\tif raw, ok := c.GetQuery(%q); ok {\n", p.name)
+	var inner bytes.Buffer
+	writeParse(&inner, p, "v", "raw", "query")
+	b.WriteString(indent(inner.String()))
+	if p.goType == "int" {
+		fail := fmt.Sprintf("\t\t\tw.errorHandler(c, &InvalidParamError{Name: %q, In: \"query\", Err: err})\n\t\t\treturn\n", p.name)
+		if s.Minimum != nil {
+			fmt.Fprintf(b, "\t\tif v < %s {\n\t\t\terr := errors.New(\"must be >= %s\")\n%s\t\t}\n", num(*s.Minimum), num(*s.Minimum), fail)
+		}
+		if s.Maximum != nil {
+			fmt.Fprintf(b, "\t\tif v > %s {\n\t\t\terr := errors.New(\"must be <= %s\")\n%s\t\t}\n", num(*s.Maximum), num(*s.Maximum), fail)
+		}
+	}
+	if p.optional {
+		fmt.Fprintf(b, "\t\tparams.%s = &v\n", p.field)
+	} else {
+		fmt.Fprintf(b, "\t\tparams.%s = v\n", p.field)
+	}
+	if p.required {
+		fmt.Fprintf(b, "\t} else {\n\t\tw.errorHandler(c, &InvalidParamError{Name: %q, In: \"query\", Err: errors.New(\"is required\")})\n\t\treturn\n", p.name)
+	}
+	b.WriteString("\t}\n")
+}
+
+func literal(goType string, v interface{}) string {
+	switch goType {
+	case "int", "float64", "bool":
+		return fmt.Sprint(v)
+	case "string":
+		return strconv.Quote(fmt.Sprint(v))
+	}
+	return goType + camel(fmt.Sprint(v))
+}
+
+func num(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+func indent(s string) string {
+	lines := strings.Split(strings.TrimRight(s, "\n"), "\n")
+	for i, l := range lines {
+		lines[i] = "\t" + l
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func methodConst(method string) string {
+	return camel(strings.ToLower(method))
+}
+
+var initialisms = map[string]string{"id": "ID", "uuid": "UUID", "url": "URL", "json": "JSON", "http": "HTTP", "api": "API"}
+
+// camel переводит snake_case/kebab-case в CamelCase с учётом аббревиатур
+func camel(s string) string {
+	var b strings.Builder
+	for _, part := range strings.FieldsFunc(s, func(r rune) bool { return r == '_' || r == '-' || r == ' ' || r == '.' }) {
+		if up, ok := initialisms[strings.ToLower(part)]; ok {
+			b.WriteString(up)
+			continue
+		}
+		b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	return b.String()
+}
+
+func lowerFirst(s string) string {
+	if up, ok := initialisms[strings.ToLower(s)]; ok && up == s {
+		return strings.ToLower(s)
+	}
+	r, size := utf8.DecodeRuneInString(s)
+	return string(unicode.ToLower(r)) + s[size:]
+}
+
+func firstLine(s string) string {
+	s, _, _ = strings.Cut(strings.TrimSpace(s), "\n")
+	return s
+}
+
+func comment(name, description string) string {
+	if d := firstLine(description); d != "" {
+		// аббревиатуры в начале описания (JSON, JWT) не понижаем
+		if r := []rune(d); len(r) < 2 || !unicode.IsUpper(r[1]) {
+			d = lowerFirst(d)
+		}
+		return fmt.Sprintf("// %s %s\n", name, d)
+	}
+	return fmt.Sprintf("// %s схема из спецификации\n", name)
+}
+
+const nullableSource = `// Nullable поле JSON Merge Patch: различает «не передано» (Set == false),
+// «передан null» (Null == true) и переданное значение
+type Nullable[T any] struct {
+	Set   bool
+	Null  bool
+	Value T
+}
+
+// UnmarshalJSON вызывается только для присутствующих в JSON ключей
+func (n *Nullable[T]) UnmarshalJSON(data []byte) error {
+	n.Set = true
+	if string(data) == "null" {
+		n.Null = true
+		return nil
+	}
+	return json.Unmarshal(data, &n.Value)
+}
+
+// MarshalJSON кодирует непереданное поле и null как null
+func (n Nullable[T]) MarshalJSON() ([]byte, error) {
+	if !n.Set || n.Null {
+		return []byte("null"), nil
+	}
+	return json.Marshal(n.Value)
+}
+
+`
+
+const runtimeSource = `// InvalidParamError параметр пути или query-строки не соответствует спецификации
+type InvalidParamError struct {
+	Name string
+	In   string
+	Err  error
+}
+
+func (e *InvalidParamError) Error() string {
+	return fmt.Sprintf("invalid %s parameter %s: %v", e.In, e.Name, e.Err)
+}
+
+func (e *InvalidParamError) Unwrap() error { return e.Err }
+
+// InvalidBodyError тело запроса не удалось разобрать
+type InvalidBodyError struct {
+	Err error
+}
+
+func (e *InvalidBodyError) Error() string {
+	return "invalid request body: " + e.Err.Error()
+}
+
+func (e *InvalidBodyError) Unwrap() error { return e.Err }
+
+// HandlerOptions настройки сгенерированных обёрток
+type HandlerOptions struct {
+	// ErrorHandler отвечает клиенту при ошибке разбора запроса.
+	// По умолчанию возвращает 400 с текстом ошибки.
+	ErrorHandler func(c *gin.Context, err error)
+}
+
+func (o HandlerOptions) errorHandler() func(*gin.Context, error) {
+	if o.ErrorHandler != nil {
+		return o.ErrorHandler
+	}
+	return func(c *gin.Context, err error) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	}
+}
+
+func decodeJSONBody(c *gin.Context, dst interface{}, strict bool) error {
+	if c.Request.Body == nil || c.Request.Body == http.NoBody {
+		return &InvalidBodyError{Err: errors.New("request body is required")}
+	}
+	dec := json.NewDecoder(c.Request.Body)
+	if strict {
+		dec.DisallowUnknownFields()
+	}
+	if err := dec.Decode(dst); err != nil {
+		return &InvalidBodyError{Err: err}
+	}
+	return nil
+}
+
+`
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"bytes"
+	"go/format"
+	"os"
+	"testing"
+
+	"github.com/TrueSmartcomm/backend/internal/openapi"
+)
+
+// TestGeneratedUpToDate проверяет, что api/server.gen.go совпадает с результатом генерации
+// по api/openapiv1.yaml; при расхождении нужно выполнить go generate ./api
+func TestGeneratedUpToDate(t *testing.T) {
+	raw, err := os.ReadFile("../../api/openapiv1.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc, err := openapi.Load(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := newGenerator(doc)
+	if err := g.run(); err != nil {
+		t.Fatal(err)
+	}
+	src, err := format.Source(g.output("api"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	committed, err := os.ReadFile("../../api/server.gen.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(src, committed) {
+		t.Error("api/server.gen.go is out of date, run go generate ./api")
+	}
+}
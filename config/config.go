@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"strconv"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -17,6 +19,31 @@ const (
 type Config struct {
 	Port        string
 	DatabaseURL string
+
+	// ValidateResponses проверять ответы по OpenAPI-спецификации (только для разработки)
+	ValidateResponses bool
+
+	// MigrateOnStart применять миграции перед запуском сервера (MIGRATE_ON_START=true
+	// или флаг --migrate-on-start)
+	MigrateOnStart bool
+
+	// WorkflowParentPolicy что делать с родительской задачей, когда завершена её последняя
+	// подзадача: "" — ничего, "notify" — событие, "advance" — перевести в done
+	WorkflowParentPolicy string
+
+	// TrashRetention сколько удалённая задача хранится в корзине до окончательного удаления
+	// (TRASH_RETENTION, длительность Go, по умолчанию 720h — 30 дней)
+	TrashRetention time.Duration
+
+	// ArchiveAfter через сколько после перехода в завершающий статус задача архивируется
+	// автоматически (ARCHIVE_AFTER_DAYS, по умолчанию 14; 0 — не архивировать)
+	ArchiveAfter time.Duration
+
+	// DefaultWorkspaceRole роль, которую новый пользователь получает в рабочем пространстве
+	// (DEFAULT_WORKSPACE_ROLE: none — без роли, по умолчанию, или viewer). Право изменять задачи
+	// выдаёт администратор, назначая роль явно.
+	// Владельца назначает команда `app owner LOGIN`.
+	DefaultWorkspaceRole string
 }
 
 func Load() (*Config, error) {
@@ -42,14 +69,58 @@ func Load() (*Config, error) {
 	}
 
 	cfg := &Config{
-		Port:        getEnv("PORT", "8080"),
-		DatabaseURL: databaseURL,
+		Port:           getEnv("PORT", "8080"),
+		DatabaseURL:    databaseURL,
+		MigrateOnStart: getEnv("MIGRATE_ON_START", "false") == "true",
+
+		WorkflowParentPolicy: os.Getenv("WORKFLOW_PARENT_POLICY"),
+		DefaultWorkspaceRole: getEnv("DEFAULT_WORKSPACE_ROLE", "none"),
+	}
+	if cfg.DefaultWorkspaceRole == "none" {
+		cfg.DefaultWorkspaceRole = ""
+	}
+
+	// Проверка ответов буферизует их целиком, поэтому вне локальной разработки не включается
+	if getEnv("OPENAPI_VALIDATE_RESPONSES", "false") == "true" {
+		if env == productionEnv || env == demoEnv {
+			log.Printf("[WARN] OPENAPI_VALIDATE_RESPONSES is ignored in %s environment", env)
+		} else {
+			cfg.ValidateResponses = true
+		}
 	}
 
+	retention, err := time.ParseDuration(getEnv("TRASH_RETENTION", "720h"))
+	if err != nil {
+		return nil, fmt.Errorf("TRASH_RETENTION: %v", err)
+	}
+	cfg.TrashRetention = retention
+
+	archiveDays, err := strconv.Atoi(getEnv("ARCHIVE_AFTER_DAYS", "14"))
+	if err != nil {
+		return nil, fmt.Errorf("ARCHIVE_AFTER_DAYS: %v", err)
+	}
+	cfg.ArchiveAfter = time.Duration(archiveDays) * 24 * time.Hour
+
 	// Валидация
 	if cfg.Port == "" {
 		return nil, fmt.Errorf("PORT is required")
 	}
+	switch cfg.WorkflowParentPolicy {
+	case "", "notify", "advance":
+	default:
+		return nil, fmt.Errorf("WORKFLOW_PARENT_POLICY must be empty, notify or advance, got %q", cfg.WorkflowParentPolicy)
+	}
+	switch cfg.DefaultWorkspaceRole {
+	case "", "viewer":
+	default:
+		return nil, fmt.Errorf("DEFAULT_WORKSPACE_ROLE must be none or viewer, got %q", cfg.DefaultWorkspaceRole)
+	}
+	if cfg.TrashRetention <= 0 {
+		return nil, fmt.Errorf("TRASH_RETENTION must be positive, got %s", cfg.TrashRetention)
+	}
+	if cfg.ArchiveAfter < 0 {
+		return nil, fmt.Errorf("ARCHIVE_AFTER_DAYS must not be negative, got %d", archiveDays)
+	}
 	if cfg.DatabaseURL == "" {
 		log.Println("[WARN] DATABASE_URL is empty - database connection may fail")
 	}
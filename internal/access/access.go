@@ -0,0 +1,183 @@
+// Package access проверяет права пользователя на доски и задачи. Роль назначается на доску
+// (board_members) или на всё рабочее пространство (workspace_members); на доске действует
+// старшая из двух ролей. Проверки вызываются репозиториями, поэтому изменения через любой
+// обработчик проходят через них.
+//
+// Пользователь берётся из контекста (requser.WithUser); без пользователя прав нет.
+package access
+
+import (
+	"context"
+
+	"github.com/TrueSmartcomm/backend/api"
+	"github.com/TrueSmartcomm/backend/internal/apperror"
+	"github.com/TrueSmartcomm/backend/internal/requser"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// Роли участников, от младшей к старшей
+const (
+	RoleViewer = string(api.MemberRoleViewer)
+	RoleMember = string(api.MemberRoleMember)
+	RoleAdmin  = string(api.MemberRoleAdmin)
+	RoleOwner  = string(api.MemberRoleOwner)
+)
+
+// roleRank старшинство ролей; неизвестная роль и её отсутствие — 0
+var roleRank = map[string]int{RoleViewer: 1, RoleMember: 2, RoleAdmin: 3, RoleOwner: 4}
+
+// ValidRole сообщает, что role — одна из ролей участников
+func ValidRole(role string) bool {
+	return roleRank[role] > 0
+}
+
+// Permission право на действие; имя права отдаётся клиенту в ответе 403
+type Permission string
+
+// Права и младшая роль, которой они доступны
+const (
+	BoardsRead    Permission = "boards:read"    // viewer: доска, колонки, workflow, участники
+	TasksRead     Permission = "tasks:read"     // viewer: задачи доски, их история и связи
+	TasksWrite    Permission = "tasks:write"    // member: создание, изменение и удаление задач
+	BoardsCreate  Permission = "boards:create"  // member рабочего пространства: новые доски
+	BoardsManage  Permission = "boards:manage"  // admin: доска, колонки и workflow
+	MembersManage Permission = "members:manage" // admin: роли admin, member и viewer
+	OwnersManage  Permission = "owners:manage"  // owner: назначение и снятие владельцев
+	BoardsDelete  Permission = "boards:delete"  // owner: удаление доски
+)
+
+var minRole = map[Permission]string{
+	BoardsRead:    RoleViewer,
+	TasksRead:     RoleViewer,
+	TasksWrite:    RoleMember,
+	BoardsCreate:  RoleMember,
+	BoardsManage:  RoleAdmin,
+	MembersManage: RoleAdmin,
+	OwnersManage:  RoleOwner,
+	BoardsDelete:  RoleOwner,
+}
+
+// Allows сообщает, что роль role даёт право perm. Неизвестное право не даёт никакая роль.
+func Allows(role string, perm Permission) bool {
+	need, ok := minRole[perm]
+	return ok && roleRank[role] > 0 && roleRank[role] >= roleRank[need]
+}
+
+// strongest старшая из ролей roles; пусто — ни одной известной роли
+func strongest(roles ...string) string {
+	role := ""
+	for _, r := range roles {
+		if roleRank[r] > roleRank[role] {
+			role = r
+		}
+	}
+	return role
+}
+
+// PermissionError у пользователя нет права Permission
+type PermissionError struct {
+	Permission Permission
+}
+
+func (e *PermissionError) Error() string {
+	return "permission denied: " + string(e.Permission) + " is required"
+}
+
+// AppError 403 с недостающим правом в поле permission
+func (e *PermissionError) AppError() *apperror.Error {
+	err := apperror.Forbidden(apperror.CodeForbidden, e.Error())
+	err.Extensions = map[string]any{"permission": string(e.Permission)}
+	return err
+}
+
+// Querier общая часть пула и транзакции
+type Querier interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// Checker проверяет права пользователя из контекста. Методы nil-Checker разрешают всё,
+// поэтому репозиторий без Checker работает без ограничений.
+type Checker struct{}
+
+func New() *Checker {
+	return &Checker{}
+}
+
+// Role роль пользователя из контекста на доске boardID (nil — в рабочем пространстве);
+// пусто — роли нет
+func Role(ctx context.Context, q Querier, boardID *uuid.UUID) (string, error) {
+	userID, ok := requser.FromContext(ctx)
+	if !ok {
+		return "", nil
+	}
+	var board, workspace *string
+	err := q.QueryRow(ctx, `SELECT (SELECT role FROM board_members WHERE board_id = $1 AND user_id = $2),
+                                   (SELECT role FROM workspace_members WHERE user_id = $2)`, boardID, userID).
+		Scan(&board, &workspace)
+	if err != nil {
+		return "", err
+	}
+	var roles []string
+	for _, r := range []*string{board, workspace} {
+		if r != nil {
+			roles = append(roles, *r)
+		}
+	}
+	return strongest(roles...), nil
+}
+
+// Board проверяет право perm на доске boardID. Для несуществующей доски действует только
+// роль в рабочем пространстве, поэтому проверка до поиска доски отдаёт 404 лишь тем,
+// кто мог бы её видеть.
+func (c *Checker) Board(ctx context.Context, q Querier, boardID uuid.UUID, perm Permission) error {
+	if c == nil {
+		return nil
+	}
+	return check(ctx, q, &boardID, perm)
+}
+
+// Workspace проверяет право perm во всём рабочем пространстве
+func (c *Checker) Workspace(ctx context.Context, q Querier, perm Permission) error {
+	if c == nil {
+		return nil
+	}
+	return check(ctx, q, nil, perm)
+}
+
+func check(ctx context.Context, q Querier, boardID *uuid.UUID, perm Permission) error {
+	role, err := Role(ctx, q, boardID)
+	if err != nil {
+		return err
+	}
+	if !Allows(role, perm) {
+		return &PermissionError{Permission: perm}
+	}
+	return nil
+}
+
+// ReadableBoards доски, задачи которых пользователь может читать. all — все доски
+// (роль в рабочем пространстве или проверка прав отключена), иначе только boards.
+func (c *Checker) ReadableBoards(ctx context.Context, q Querier) (boards []uuid.UUID, all bool, err error) {
+	if c == nil {
+		return nil, true, nil
+	}
+	role, err := Role(ctx, q, nil)
+	if err != nil || Allows(role, TasksRead) {
+		return nil, err == nil, err
+	}
+	userID, ok := requser.FromContext(ctx)
+	if !ok {
+		return []uuid.UUID{}, false, nil
+	}
+	rows, err := q.Query(ctx, `SELECT board_id FROM board_members WHERE user_id = $1`, userID)
+	if err != nil {
+		return nil, false, err
+	}
+	boards, err = pgx.CollectRows(rows, pgx.RowTo[uuid.UUID])
+	if boards == nil {
+		boards = []uuid.UUID{}
+	}
+	return boards, false, err
+}
@@ -0,0 +1,76 @@
+package access
+
+import (
+	"testing"
+
+	"github.com/TrueSmartcomm/backend/internal/models"
+)
+
+func TestAllows(t *testing.T) {
+	perms := []Permission{
+		BoardsRead, TasksRead, TasksWrite, BoardsCreate,
+		BoardsManage, MembersManage, OwnersManage, BoardsDelete,
+	}
+	// разрешённые права каждой роли; остальные из perms запрещены
+	tests := []struct {
+		role  string
+		allow []Permission
+	}{
+		{role: "", allow: nil}, // новый пользователь без роли
+		{role: RoleViewer, allow: []Permission{BoardsRead, TasksRead}},
+		{role: RoleMember, allow: []Permission{BoardsRead, TasksRead, TasksWrite, BoardsCreate}},
+		{role: RoleAdmin, allow: []Permission{BoardsRead, TasksRead, TasksWrite, BoardsCreate, BoardsManage, MembersManage}},
+		{role: RoleOwner, allow: perms},
+		// роли относительно задачи не дают прав участника
+		{role: models.RoleTaskOwner, allow: nil},
+		{role: models.RoleAssignee, allow: nil},
+		{role: "superuser", allow: nil},
+	}
+	for _, tt := range tests {
+		allowed := map[Permission]bool{}
+		for _, p := range tt.allow {
+			allowed[p] = true
+		}
+		for _, p := range perms {
+			if got := Allows(tt.role, p); got != allowed[p] {
+				t.Errorf("Allows(%q, %s) = %v, want %v", tt.role, p, got, allowed[p])
+			}
+		}
+		if Allows(tt.role, "tasks:destroy") {
+			t.Errorf("Allows(%q, unknown permission) = true", tt.role)
+		}
+	}
+}
+
+func TestValidRole(t *testing.T) {
+	for role, want := range map[string]bool{
+		RoleViewer: true, RoleMember: true, RoleAdmin: true, RoleOwner: true,
+		"": false, models.RoleTaskOwner: false, models.RoleAssignee: false, "Owner": false,
+	} {
+		if got := ValidRole(role); got != want {
+			t.Errorf("ValidRole(%q) = %v, want %v", role, got, want)
+		}
+	}
+}
+
+// На доске действует старшая из ролей доски и рабочего пространства
+func TestStrongest(t *testing.T) {
+	tests := []struct {
+		board, workspace string
+		want             string
+	}{
+		{board: "", workspace: "", want: ""},
+		{board: RoleViewer, workspace: "", want: RoleViewer},
+		{board: "", workspace: RoleMember, want: RoleMember},
+		{board: RoleAdmin, workspace: RoleViewer, want: RoleAdmin},
+		{board: RoleViewer, workspace: RoleOwner, want: RoleOwner},
+		{board: RoleMember, workspace: RoleMember, want: RoleMember},
+		{board: models.RoleTaskOwner, workspace: RoleViewer, want: RoleViewer},
+		{board: models.RoleTaskOwner, workspace: "", want: ""},
+	}
+	for _, tt := range tests {
+		if got := strongest(tt.board, tt.workspace); got != tt.want {
+			t.Errorf("strongest(%q, %q) = %q, want %q", tt.board, tt.workspace, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,225 @@
+// Package apperror описывает типизированные ошибки сервиса. Репозитории и сервисы
+// возвращают *Error, а HTTP-слой переводит их в application/problem+json.
+package apperror
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+// Kind класс ошибки, определяет HTTP-статус
+type Kind int
+
+const (
+	KindInternal           Kind = iota // непредвиденная ошибка, детали не раскрываются клиенту
+	KindBadRequest                     // запрос не соответствует контракту API
+	KindValidation                     // данные корректны по форме, но нарушают правила предметной области
+	KindNotFound                       // ресурс не найден
+	KindConflict                       // конфликт с текущим состоянием ресурса
+	KindUnauthorized                   // нет или неверные учётные данные
+	KindForbidden                      // недостаточно прав
+	KindPreconditionFailed             // условие запроса (If-Match) не выполнено
+)
+
+// Status HTTP-статус, соответствующий классу ошибки
+func (k Kind) Status() int {
+	switch k {
+	case KindBadRequest:
+		return http.StatusBadRequest
+	case KindValidation:
+		return http.StatusUnprocessableEntity
+	case KindNotFound:
+		return http.StatusNotFound
+	case KindConflict:
+		return http.StatusConflict
+	case KindUnauthorized:
+		return http.StatusUnauthorized
+	case KindForbidden:
+		return http.StatusForbidden
+	case KindPreconditionFailed:
+		return http.StatusPreconditionFailed
+	}
+	return http.StatusInternalServerError
+}
+
+// Стабильные коды ошибок. Клиенты опираются на них, поэтому коды не переименовываются.
+const (
+	CodeInternal           = "internal_error"
+	CodeInvalidRequest     = "invalid_request"
+	CodeValidationFailed   = "validation_failed"
+	CodeTaskNotFound       = "task_not_found"
+	CodeUserNotFound       = "user_not_found"
+	CodeLoginTaken         = "login_taken"
+	CodeEmailTaken         = "email_taken"
+	CodeInvalidCredentials = "invalid_credentials"
+	CodeInvalidToken       = "invalid_token"
+	CodeInvalidRefresh     = "invalid_refresh_token"
+	CodeForbidden          = "forbidden"
+	CodeVersionMismatch    = "version_mismatch"
+	CodeDependencyCycle    = "dependency_cycle"
+	CodeTransitionBlocked  = "transition_blocked"
+	CodeBoardNotFound      = "board_not_found"
+	CodeColumnNotFound     = "column_not_found"
+	CodeBoardNotEmpty      = "board_not_empty"
+	CodeColumnNotEmpty     = "column_not_empty"
+	CodeColumnKeyTaken     = "column_key_taken"
+	CodeDefaultBoard       = "default_board"
+	CodeLastColumn         = "last_column"
+	CodeTransitionDenied   = "transition_not_allowed"
+	CodeStatusInUse        = "status_in_use"
+	CodeWIPLimitExceeded   = "wip_limit_exceeded"
+	CodeTaskArchived       = "task_archived"
+	CodeLastOwner          = "last_owner"
+)
+
+// FieldError ошибка в конкретном поле или параметре запроса
+type FieldError struct {
+	Name   string `json:"name"`
+	In     string `json:"in,omitempty"` // path, query, body; пусто для полей ресурса
+	Reason string `json:"reason"`
+}
+
+// Error ошибка сервиса с классом и стабильным кодом
+type Error struct {
+	Kind       Kind
+	Code       string
+	Message    string         // текст для клиента
+	Fields     []FieldError   // детали для ошибок валидации
+	Extensions map[string]any // дополнительные поля problem+json (RFC 7807, 3.2)
+	Err        error          // причина; клиенту не показывается
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() error { return e.Err }
+
+// Is сравнивает ошибки по коду, чтобы работал errors.Is(err, apperror.NotFound(code, ""))
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+// New создаёт ошибку заданного класса
+func New(kind Kind, code, message string) *Error {
+	return &Error{Kind: kind, Code: code, Message: message}
+}
+
+// Wrap создаёт ошибку заданного класса с причиной
+func Wrap(kind Kind, code, message string, err error) *Error {
+	return &Error{Kind: kind, Code: code, Message: message, Err: err}
+}
+
+// BadRequest запрос не соответствует контракту API
+func BadRequest(message string, fields ...FieldError) *Error {
+	return &Error{Kind: KindBadRequest, Code: CodeInvalidRequest, Message: message, Fields: fields}
+}
+
+// Validation данные нарушают правила предметной области
+func Validation(message string, fields ...FieldError) *Error {
+	return &Error{Kind: KindValidation, Code: CodeValidationFailed, Message: message, Fields: fields}
+}
+
+// NotFound ресурс не найден
+func NotFound(code, message string) *Error {
+	return New(KindNotFound, code, message)
+}
+
+// Conflict конфликт с текущим состоянием ресурса
+func Conflict(code, message string) *Error {
+	return New(KindConflict, code, message)
+}
+
+// Unauthorized нет или неверные учётные данные
+func Unauthorized(code, message string) *Error {
+	return New(KindUnauthorized, code, message)
+}
+
+// Forbidden недостаточно прав
+func Forbidden(code, message string) *Error {
+	return New(KindForbidden, code, message)
+}
+
+// Internal непредвиденная ошибка; err сохраняется для логов
+func Internal(err error) *Error {
+	return Wrap(KindInternal, CodeInternal, "internal server error", err)
+}
+
+// Converter ошибки других пакетов, которые знают своё место в таксономии
+// (например, models.ValidationError)
+type Converter interface {
+	AppError() *Error
+}
+
+// From приводит произвольную ошибку к *Error. Неизвестные ошибки считаются внутренними.
+func From(err error) *Error {
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return appErr
+	}
+	var conv Converter
+	if errors.As(err, &conv) {
+		return conv.AppError()
+	}
+	return Internal(err)
+}
+
+// IsKind сообщает, относится ли ошибка к классу kind
+func IsKind(err error, kind Kind) bool {
+	var appErr *Error
+	return errors.As(err, &appErr) && appErr.Kind == kind
+}
+
+// ProblemContentType тип содержимого ответа с ошибкой (RFC 7807)
+const ProblemContentType = "application/problem+json"
+
+// Problem тело ответа application/problem+json
+type Problem struct {
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+
+	Code          string       `json:"code"`
+	InvalidParams []FieldError `json:"invalid_params,omitempty"`
+
+	// Extensions члены верхнего уровня, специфичные для кода ошибки.
+	// Не должны совпадать с именами полей выше.
+	Extensions map[string]any `json:"-"`
+}
+
+// MarshalJSON добавляет Extensions к стандартным полям
+func (p Problem) MarshalJSON() ([]byte, error) {
+	type plain Problem
+	data, err := json.Marshal(plain(p))
+	if err != nil || len(p.Extensions) == 0 {
+		return data, err
+	}
+	ext, err := json.Marshal(p.Extensions)
+	if err != nil {
+		return nil, err
+	}
+	// {"type":...} + {"ext":...} -> {"type":...,"ext":...}
+	return append(append(data[:len(data)-1], ','), ext[1:]...), nil
+}
+
+// Problem представление ошибки для клиента; instance — путь запроса
+func (e *Error) Problem(instance string) Problem {
+	status := e.Kind.Status()
+	return Problem{
+		Type:          "urn:truesmartcomm:problem:" + e.Code,
+		Title:         http.StatusText(status),
+		Status:        status,
+		Detail:        e.Message,
+		Instance:      instance,
+		Code:          e.Code,
+		InvalidParams: e.Fields,
+		Extensions:    e.Extensions,
+	}
+}
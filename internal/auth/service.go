@@ -5,14 +5,21 @@ import (
 	"crypto/rand"
 	"encoding/base64"
 	"errors"
+	"fmt"
 	"time"
 
+	"github.com/TrueSmartcomm/backend/internal/apperror"
 	"github.com/TrueSmartcomm/backend/internal/models"
 	"github.com/TrueSmartcomm/backend/internal/repository"
 	"github.com/golang-jwt/jwt/v5"
 	"golang.org/x/crypto/bcrypt"
 )
 
+var (
+	errInvalidCredentials = apperror.Unauthorized(apperror.CodeInvalidCredentials, "invalid login or password")
+	errInvalidToken       = apperror.Unauthorized(apperror.CodeInvalidToken, "invalid or expired token")
+)
+
 // AuthService содержит зависимости для бизнес-логики аутентификации
 type AuthService struct {
 	repo      *repository.UserRepository // Репозиторий для работы с пользователями в БД
@@ -34,12 +41,18 @@ func (s *AuthService) Register(ctx context.Context, req *models.UserRegisterRequ
 	_, err := s.repo.GetUserByLogin(ctx, req.Login)
 	if err == nil {
 		// Если err == nil, значит пользователь найден
-		return errors.New("user with this login already exists")
+		return apperror.Conflict(apperror.CodeLoginTaken, "user with this login already exists")
+	}
+	if !apperror.IsKind(err, apperror.KindNotFound) {
+		return err
 	}
 	// Проверим email
 	_, err = s.repo.GetUserByEmail(ctx, req.Email)
 	if err == nil {
-		return errors.New("user with this email already exists")
+		return apperror.Conflict(apperror.CodeEmailTaken, "user with this email already exists")
+	}
+	if !apperror.IsKind(err, apperror.KindNotFound) {
+		return err
 	}
 
 	//Хеширование пароля
@@ -63,10 +76,8 @@ func (s *AuthService) Login(ctx context.Context, req *models.UserLoginRequest) (
 	//Получение пользователя из БД по логину
 	user, err := s.repo.GetUserByLogin(ctx, req.Login)
 	if err != nil {
-
-		if err.Error() == "user not found" {
-
-			return nil, errors.New("invalid login or password")
+		if apperror.IsKind(err, apperror.KindNotFound) {
+			return nil, errInvalidCredentials
 		}
 		// Другая ошибка БД
 		return nil, err
@@ -76,7 +87,7 @@ func (s *AuthService) Login(ctx context.Context, req *models.UserLoginRequest) (
 	err = ComparePassword(user.PasswordHash, req.Password)
 	if err != nil {
 		// Пароль не совпадает
-		return nil, errors.New("invalid login or password")
+		return nil, errInvalidCredentials
 	}
 
 	//Генерация JWT Access Token
@@ -95,8 +106,7 @@ func (s *AuthService) Login(ctx context.Context, req *models.UserLoginRequest) (
 	// 7 дней = 7 * 24 * 3600 секунд
 	err = s.repo.SaveRefreshToken(ctx, user.ID, refreshToken, 7*24*time.Hour)
 	if err != nil {
-
-		return nil, errors.New("failed to save refresh token")
+		return nil, fmt.Errorf("failed to save refresh token: %w", err)
 	}
 
 	//Возврат токенов
@@ -112,11 +122,8 @@ func (s *AuthService) RefreshToken(ctx context.Context, req *models.UserRefreshR
 	// 1. Найти refresh token в БД
 	userID, expiresAt, err := s.repo.GetRefreshToken(ctx, req.RefreshToken)
 	if err != nil {
-		// Если токен не найден в БД
-		if err.Error() == "refresh token not found" {
-			return nil, errors.New("invalid or expired refresh token")
-		}
-		// Другая ошибка БД
+		// Если токен не найден в БД, репозиторий уже вернул 401 invalid_refresh_token;
+		// другие ошибки БД остаются внутренними
 		return nil, err
 	}
 
@@ -124,7 +131,7 @@ func (s *AuthService) RefreshToken(ctx context.Context, req *models.UserRefreshR
 	if time.Now().After(expiresAt) {
 		// Удалить просроченный токен (опционально, но рекомендуется)
 		s.repo.DeleteRefreshToken(ctx, req.RefreshToken) // Не критично, если ошибка при удалении
-		return nil, errors.New("refresh token has expired")
+		return nil, apperror.Unauthorized(apperror.CodeInvalidRefresh, "refresh token has expired")
 	}
 
 	// 3. (Ротация) Сгенерировать новый Refresh Token и сохранить его.
@@ -142,7 +149,7 @@ func (s *AuthService) RefreshToken(ctx context.Context, req *models.UserRefreshR
 		// Логируем ошибку, но не обязательно возвращать её пользователю сразу
 		// В зависимости от требований, можно вернуть ошибку или Proceed without deleting old token
 		// Здесь возвращаем ошибку, чтобы убедиться, что старый токен удален.
-		return nil, errors.New("failed to delete old refresh token")
+		return nil, fmt.Errorf("failed to delete old refresh token: %w", err)
 	}
 
 	// Сохранить новый refresh token в БД
@@ -152,7 +159,7 @@ func (s *AuthService) RefreshToken(ctx context.Context, req *models.UserRefreshR
 		// Логируем ошибку, но не обязательно возвращать её пользователю сразу
 		// В зависимости от требований, можно вернуть ошибку или Proceed without saving new token
 		// Здесь возвращаем ошибку, чтобы убедиться, что новый токен сохранён.
-		return nil, errors.New("failed to save new refresh token")
+		return nil, fmt.Errorf("failed to save new refresh token: %w", err)
 	}
 
 	// 4. Сгенерировать новый Access Token (JWT) для найденного userID
@@ -195,17 +202,17 @@ func (s *AuthService) ValidateToken(tokenString string) (int, error) {
 	})
 
 	if err != nil || !token.Valid {
-		return 0, errors.New("invalid token")
+		return 0, errInvalidToken
 	}
 
 	claims, ok := token.Claims.(jwt.MapClaims)
 	if !ok {
-		return 0, errors.New("invalid token claims")
+		return 0, apperror.Unauthorized(apperror.CodeInvalidToken, "invalid token claims")
 	}
 
 	userIDFloat, ok := claims["user_id"].(float64) // JWT числа как float64
 	if !ok {
-		return 0, errors.New("user_id not found in token claims")
+		return 0, apperror.Unauthorized(apperror.CodeInvalidToken, "user_id not found in token claims")
 	}
 
 	return int(userIDFloat), nil
@@ -0,0 +1,168 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/TrueSmartcomm/backend/api"
+	"github.com/TrueSmartcomm/backend/internal/models"
+	"github.com/TrueSmartcomm/backend/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// BoardHandler реализует операции спецификации с тегом boards
+type BoardHandler struct {
+	repo *repository.BoardRepository
+}
+
+var _ api.BoardsServer = (*BoardHandler)(nil)
+
+func NewBoardHandler(repo *repository.BoardRepository) *BoardHandler {
+	return &BoardHandler{repo: repo}
+}
+
+// GET /boards
+func (h *BoardHandler) ListBoards(c *gin.Context) {
+	boards, err := h.repo.ListBoards(actorContext(c))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, boards)
+}
+
+// POST /boards
+func (h *BoardHandler) CreateBoard(c *gin.Context, body api.BoardInput) {
+	board := models.Board{Name: body.Name}
+	if body.Description != nil {
+		board.Description = *body.Description
+	}
+	for _, col := range body.Columns {
+		board.Columns = append(board.Columns, columnFromInput(col))
+	}
+
+	if err := h.repo.CreateBoard(actorContext(c), &board); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusCreated, board)
+}
+
+// GET /boards/{id}
+func (h *BoardHandler) GetBoard(c *gin.Context, id uuid.UUID) {
+	board, err := h.repo.GetBoard(actorContext(c), id)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, board)
+}
+
+// PUT /boards/{id}
+func (h *BoardHandler) UpdateBoard(c *gin.Context, id uuid.UUID, body api.BoardUpdate) {
+	board := models.Board{ID: id, Name: body.Name}
+	if body.Description != nil {
+		board.Description = *body.Description
+	}
+
+	if err := h.repo.UpdateBoard(actorContext(c), &board); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, board)
+}
+
+// DELETE /boards/{id}
+func (h *BoardHandler) DeleteBoard(c *gin.Context, id uuid.UUID) {
+	if err := h.repo.DeleteBoard(actorContext(c), id); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// GET /boards/{id}/snapshot
+// Колонки доски с карточками и сводками за один запрос; фильтры те же, что у GET /tasks
+func (h *BoardHandler) GetBoardSnapshot(c *gin.Context, id uuid.UUID, params api.GetBoardSnapshotParams) {
+	filter := taskFilterFromParams(api.ListTasksParams{
+		ColumnID: params.ColumnID, KanbanSpace: params.KanbanSpace, Status: params.Status,
+		Priority: params.Priority, OwnerID: params.OwnerID, AssigneeID: params.AssigneeID,
+		DueFrom: params.DueFrom, DueTo: params.DueTo, CreatedFrom: params.CreatedFrom,
+		CreatedTo: params.CreatedTo, UpdatedFrom: params.UpdatedFrom, UpdatedTo: params.UpdatedTo,
+	})
+	filter.Mine = mineFilter(c, params.Mine)
+
+	snapshot, err := h.repo.GetBoardSnapshot(actorContext(c), id, filter, params.Limit)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, snapshot)
+}
+
+// GET /boards/{id}/columns
+func (h *BoardHandler) ListBoardColumns(c *gin.Context, id uuid.UUID) {
+	columns, err := h.repo.ListColumns(actorContext(c), id)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, columns)
+}
+
+// POST /boards/{id}/columns
+func (h *BoardHandler) CreateBoardColumn(c *gin.Context, id uuid.UUID, body api.ColumnInput) {
+	column := columnFromInput(body)
+	column.BoardID = id
+
+	if err := h.repo.CreateColumn(actorContext(c), &column, body.Position); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusCreated, column)
+}
+
+// PUT /boards/{id}/columns/{column_id}
+func (h *BoardHandler) UpdateBoardColumn(c *gin.Context, id, columnID uuid.UUID, body api.ColumnUpdate) {
+	column := models.Column{ID: columnID, BoardID: id, Name: body.Name, Status: body.Status,
+		WIPLimit: body.WipLimit, WIPLimitPerAssignee: body.WipLimitPerAssignee}
+	if body.WipMode != nil {
+		column.WIPMode = string(*body.WipMode)
+	}
+
+	if err := h.repo.UpdateColumn(actorContext(c), &column, body.Position); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, column)
+}
+
+// DELETE /boards/{id}/columns/{column_id}
+func (h *BoardHandler) DeleteBoardColumn(c *gin.Context, id, columnID uuid.UUID) {
+	if err := h.repo.DeleteColumn(actorContext(c), id, columnID); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// GET /boards/{id}/workflow
+func (h *BoardHandler) GetBoardWorkflow(c *gin.Context, id uuid.UUID) {
+	w, err := h.repo.GetWorkflow(actorContext(c), id)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, w)
+}
+
+// PUT /boards/{id}/workflow
+func (h *BoardHandler) UpdateBoardWorkflow(c *gin.Context, id uuid.UUID, body api.Workflow) {
+	w := workflowFromAPI(body)
+
+	if err := h.repo.UpdateWorkflow(actorContext(c), id, &w); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, w)
+}
@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"time"
+
+	"github.com/TrueSmartcomm/backend/api"
+	"github.com/TrueSmartcomm/backend/internal/models"
+)
+
+// taskFromInput переводит тело запроса из спецификации в модель задачи
+func taskFromInput(in api.TaskInput) models.Task {
+	task := models.Task{
+		Title:         in.Title,
+		DueDate:       in.DueDate,
+		EstimateHours: in.EstimateHours,
+	}
+	task.SetAssignees(in.AssigneeIds)
+	if in.Status != nil {
+		task.Status = *in.Status
+	}
+	if in.Description != nil {
+		task.Description = *in.Description
+	}
+	if in.BoardID != nil {
+		task.BoardID = *in.BoardID
+	}
+	if in.ColumnID != nil {
+		task.ColumnID = *in.ColumnID
+	}
+	if in.KanbanSpace != nil {
+		task.KanbanSpace = *in.KanbanSpace
+	}
+	if in.Priority != nil {
+		task.Priority = string(*in.Priority)
+	}
+	return task
+}
+
+// columnFromInput переводит колонку из спецификации в модель; без wip_mode режим hard
+func columnFromInput(in api.ColumnInput) models.Column {
+	c := models.Column{Key: in.Key, Name: in.Name, Status: in.Status,
+		WIPLimit: in.WipLimit, WIPLimitPerAssignee: in.WipLimitPerAssignee}
+	if in.WipMode != nil {
+		c.WIPMode = string(*in.WipMode)
+	}
+	return c
+}
+
+// taskPatchFromAPI переводит merge-patch из спецификации в модель
+func taskPatchFromAPI(p api.TaskPatch) models.TaskPatch {
+	return models.TaskPatch{
+		Title:         nullable(p.Title, func(v string) string { return v }),
+		Description:   nullable(p.Description, func(v string) string { return v }),
+		Status:        nullable(p.Status, func(v api.TaskStatus) string { return v }),
+		BoardID:       p.BoardID,
+		ColumnID:      p.ColumnID,
+		KanbanSpace:   nullable(p.KanbanSpace, func(v api.KanbanSpace) string { return v }),
+		AssigneeIDs:   nullable(p.AssigneeIds, func(v []int) []int { return v }),
+		Priority:      nullable(p.Priority, func(v api.TaskPriority) string { return string(v) }),
+		DueDate:       nullable(p.DueDate, func(v time.Time) time.Time { return v }),
+		EstimateHours: nullable(p.EstimateHours, func(v float64) float64 { return v }),
+	}
+}
+
+// nullable переносит трёхзначное поле merge-patch с преобразованием значения
+func nullable[S, D any](src api.Nullable[S], conv func(S) D) api.Nullable[D] {
+	dst := api.Nullable[D]{Set: src.Set, Null: src.Null}
+	if src.Set && !src.Null {
+		dst.Value = conv(src.Value)
+	}
+	return dst
+}
+
+// taskFilterFromParams переводит разобранные query-параметры в фильтр репозитория.
+// Значения по умолчанию и границы limit/offset уже проверены обёрткой из спецификации.
+func taskFilterFromParams(p api.ListTasksParams) models.TaskFilter {
+	f := models.TaskFilter{
+		SortBy: string(p.SortBy),
+		Desc:   p.Order == api.ListTasksParamsOrderDesc,
+		Limit:  p.Limit,
+		Offset: p.Offset,
+	}
+	f.Archived = string(p.Archived)
+	f.BoardID, f.ColumnID = p.BoardID, p.ColumnID
+	f.OwnerID, f.AssigneeID = p.OwnerID, p.AssigneeID
+	if p.KanbanSpace != nil {
+		f.KanbanSpace = *p.KanbanSpace
+	}
+	if p.Status != nil {
+		f.Status = string(*p.Status)
+	}
+	if p.Priority != nil {
+		f.Priority = string(*p.Priority)
+	}
+
+	// в БД хранится timestamp без часового пояса в UTC
+	f.DueFrom, f.DueTo = utc(p.DueFrom), utc(p.DueTo)
+	f.CreatedFrom, f.CreatedTo = utc(p.CreatedFrom), utc(p.CreatedTo)
+	f.UpdatedFrom, f.UpdatedTo = utc(p.UpdatedFrom), utc(p.UpdatedTo)
+	return f
+}
+
+func utc(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	u := t.UTC()
+	return &u
+}
+
+// workflowFromAPI переводит workflow доски из спецификации в модель
+func workflowFromAPI(in api.Workflow) models.Workflow {
+	w := models.Workflow{
+		Statuses:    make([]models.BoardStatus, len(in.Statuses)),
+		Transitions: make([]models.BoardTransition, len(in.Transitions)),
+	}
+	for i, s := range in.Statuses {
+		w.Statuses[i] = models.BoardStatus{Key: s.Key, Name: s.Name}
+		if s.Final != nil {
+			w.Statuses[i].Final = *s.Final
+		}
+	}
+	for i, t := range in.Transitions {
+		tr := models.BoardTransition{From: t.From, To: t.To,
+			RequiredFields: make([]string, len(t.RequiredFields)), Roles: make([]string, len(t.Roles))}
+		for j, f := range t.RequiredFields {
+			tr.RequiredFields[j] = string(f)
+		}
+		for j, r := range t.Roles {
+			tr.Roles[j] = string(r)
+		}
+		if t.RequireAssignee != nil {
+			tr.RequireAssignee = *t.RequireAssignee
+		}
+		w.Transitions[i] = tr
+	}
+	return w
+}
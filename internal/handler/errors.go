@@ -0,0 +1,27 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/TrueSmartcomm/backend/api"
+	"github.com/TrueSmartcomm/backend/internal/apperror"
+	"github.com/gin-gonic/gin"
+)
+
+// RequestError ErrorHandler для сгенерированных обёрток: ошибки разбора параметров
+// и тела запроса отдаются как 400 invalid_request
+func RequestError(c *gin.Context, err error) {
+	var paramErr *api.InvalidParamError
+	if errors.As(err, &paramErr) {
+		_ = c.Error(apperror.BadRequest(err.Error(),
+			apperror.FieldError{Name: paramErr.Name, In: paramErr.In, Reason: paramErr.Err.Error()}))
+		return
+	}
+	var bodyErr *api.InvalidBodyError
+	if errors.As(err, &bodyErr) {
+		_ = c.Error(apperror.BadRequest(err.Error(),
+			apperror.FieldError{In: "body", Reason: bodyErr.Err.Error()}))
+		return
+	}
+	_ = c.Error(apperror.BadRequest(err.Error()))
+}
@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/TrueSmartcomm/backend/internal/apperror"
+	"github.com/TrueSmartcomm/backend/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+// Версия задачи отдаётся как сильный ETag вида "3". PUT, PATCH, move и DELETE
+// принимают If-Match, GET — If-None-Match.
+
+// taskETag ETag для версии задачи
+func taskETag(version int64) string {
+	return `"` + strconv.FormatInt(version, 10) + `"`
+}
+
+// setTaskETag выставляет заголовок ETag для задачи
+func setTaskETag(c *gin.Context, task *models.Task) {
+	c.Header("ETag", taskETag(task.Version))
+}
+
+// parseETags разбирает список ETag из If-Match/If-None-Match.
+// wildcard == true для "*". weak — признак W/-тегов, которые для If-Match никогда не совпадают.
+func parseETags(header string) (versions []int64, weak []bool, wildcard bool, err error) {
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" {
+			wildcard = true
+			continue
+		}
+		isWeak := strings.HasPrefix(tag, "W/")
+		tag = strings.TrimPrefix(tag, "W/")
+		if len(tag) < 2 || tag[0] != '"' || tag[len(tag)-1] != '"' {
+			return nil, nil, false, errors.New("malformed entity tag")
+		}
+		v, convErr := strconv.ParseInt(tag[1:len(tag)-1], 10, 64)
+		if convErr != nil {
+			// Чужой ETag не совпадает ни с одной версией, но и ошибкой не является
+			continue
+		}
+		versions = append(versions, v)
+		weak = append(weak, isWeak)
+	}
+	return versions, weak, wildcard, nil
+}
+
+// ifMatch возвращает список допустимых версий из If-Match.
+// nil — заголовка нет или передан "*", изменение безусловное.
+func ifMatch(c *gin.Context) ([]int64, error) {
+	header := c.GetHeader("If-Match")
+	if header == "" {
+		return nil, nil
+	}
+	versions, weak, wildcard, err := parseETags(header)
+	if err != nil {
+		return nil, apperror.BadRequest("invalid If-Match header",
+			apperror.FieldError{Name: "If-Match", In: "header", Reason: err.Error()})
+	}
+	if wildcard {
+		return nil, nil
+	}
+	// Для If-Match используется сильное сравнение (RFC 9110, 13.1.1)
+	strong := []int64{}
+	for i, v := range versions {
+		if !weak[i] {
+			strong = append(strong, v)
+		}
+	}
+	return strong, nil
+}
+
+// notModified сообщает, совпадает ли If-None-Match с версией задачи (слабое сравнение)
+func notModified(c *gin.Context, task *models.Task) bool {
+	header := c.GetHeader("If-None-Match")
+	if header == "" {
+		return false
+	}
+	versions, _, wildcard, err := parseETags(header)
+	if err != nil {
+		// Некорректный заголовок игнорируется, отдаётся полный ответ
+		return false
+	}
+	if wildcard {
+		return true
+	}
+	for _, v := range versions {
+		if v == task.Version {
+			return true
+		}
+	}
+	return false
+}
+
+// taskError передаёт ошибку в middleware.Errors. Для устаревшей версии
+// отвечает 412 с актуальным состоянием задачи и её ETag, чтобы клиент мог
+// сразу повторить изменение.
+func taskError(c *gin.Context, err error) {
+	var conflict *models.VersionConflictError
+	if errors.As(err, &conflict) && conflict.Current != nil {
+		setTaskETag(c, conflict.Current)
+		c.JSON(http.StatusPreconditionFailed, conflict.Current)
+		return
+	}
+	_ = c.Error(err)
+}
@@ -3,225 +3,472 @@ package handlers
 import (
 	"context"
 	"net/http"
+	"strconv"
+	"time"
 
+	"github.com/TrueSmartcomm/backend/api"
+	"github.com/TrueSmartcomm/backend/internal/apperror"
+	"github.com/TrueSmartcomm/backend/internal/middleware"
 	"github.com/TrueSmartcomm/backend/internal/models"
+	"github.com/TrueSmartcomm/backend/internal/pagination"
 	"github.com/TrueSmartcomm/backend/internal/repository"
+	"github.com/TrueSmartcomm/backend/internal/requser"
 	"github.com/google/uuid"
 
 	"github.com/gin-gonic/gin"
 )
 
+// TaskHandler реализует операции спецификации с тегом tasks
 type TaskHandler struct {
-	repo *repository.TaskRepository
+	repo    *repository.TaskRepository
+	cursors *pagination.Codec
 }
 
-func NewTaskHandler(repo *repository.TaskRepository) *TaskHandler {
-	return &TaskHandler{repo: repo}
+var _ api.TasksServer = (*TaskHandler)(nil)
+
+func NewTaskHandler(repo *repository.TaskRepository, cursors *pagination.Codec) *TaskHandler {
+	return &TaskHandler{repo: repo, cursors: cursors}
 }
 
 // POST /tasks
-func (h *TaskHandler) CreateTask(c *gin.Context) {
-	var task models.Task
-	if err := c.ShouldBindJSON(&task); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+func (h *TaskHandler) CreateTask(c *gin.Context, body api.TaskInput) {
+	task := taskFromInput(body)
+
+	if err := h.repo.CreateTask(actorContext(c), &task); err != nil {
+		_ = c.Error(err)
 		return
 	}
+	setTaskETag(c, &task)
+	setWIPWarnings(c, &task)
+	c.JSON(http.StatusCreated, task)
+}
 
-	if task.Status == "" {
-		task.Status = "todo"
+// GET /tasks
+// Возвращает страницу задач по фильтрам. Для совместимости запрос с ?id= отдаёт одну задачу.
+func (h *TaskHandler) ListTasks(c *gin.Context, params api.ListTasksParams) {
+	if idStr := c.Query("id"); idStr != "" {
+		middleware.MarkDeprecated(c, "/api/v1/tasks/{id}")
+		id, err := uuid.Parse(idStr)
+		if err != nil {
+			_ = c.Error(apperror.BadRequest("invalid uuid format",
+				apperror.FieldError{Name: "id", In: "query", Reason: "must be a UUID"}))
+			return
+		}
+		h.GetTask(c, id)
+		return
 	}
 
-	if err := h.repo.CreateTask(context.Background(), &task); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	filter := taskFilterFromParams(params)
+	filter.Mine = mineFilter(c, params.Mine)
+
+	if params.Cursor != nil && *params.Cursor != "" {
+		cur, err := h.cursors.Decode(*params.Cursor)
+		if err != nil {
+			_ = c.Error(apperror.BadRequest(err.Error(),
+				apperror.FieldError{Name: "cursor", In: "query", Reason: "malformed or tampered cursor"}))
+			return
+		}
+		// Курсор привязан к сортировке, в которой был выдан
+		if cur.SortBy != filter.SortBy || cur.Desc != filter.Desc {
+			_ = c.Error(apperror.BadRequest("cursor does not match sort_by/order",
+				apperror.FieldError{Name: "cursor", In: "query", Reason: "issued for a different sort_by/order"}))
+			return
+		}
+		filter.Cursor = cur
+		filter.Offset = 0
+	}
+
+	page, err := h.repo.GetAllTasks(actorContext(c), filter)
+	if err != nil {
+		_ = c.Error(err)
 		return
 	}
-	c.JSON(http.StatusCreated, task)
+
+	resp := models.TaskList{
+		Items:  page.Tasks,
+		Total:  page.Total,
+		Limit:  filter.Limit,
+		Offset: filter.Offset,
+	}
+	if page.Next != nil {
+		if resp.NextCursor, err = h.cursors.Encode(*page.Next); err != nil {
+			_ = c.Error(err)
+			return
+		}
+	}
+	if page.Prev != nil {
+		if resp.PrevCursor, err = h.cursors.Encode(*page.Prev); err != nil {
+			_ = c.Error(err)
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, resp)
 }
 
-// GET /tasks
-func (h *TaskHandler) GetTask(c *gin.Context) {
-	idStr := c.Query("id")
-	id := uuid.MustParse(idStr)
+// GET /tasks/:id
+// Отдаёт ETag; при совпадении If-None-Match отвечает 304 без тела.
+func (h *TaskHandler) GetTask(c *gin.Context, id uuid.UUID) {
+	task, err := h.repo.GetTaskByID(actorContext(c), id)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	setTaskETag(c, task)
+	if notModified(c, task) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	c.JSON(http.StatusOK, task)
+}
 
-	task, err := h.repo.GetTaskByID(context.Background(), id)
+// PUT /tasks/:id
+func (h *TaskHandler) UpdateTask(c *gin.Context, id uuid.UUID, body api.TaskInput) {
+	versions, err := ifMatch(c)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "task not found"})
+		_ = c.Error(err)
 		return
 	}
+
+	task := taskFromInput(body)
+	task.ID = id
+
+	if err := h.repo.UpdateTask(actorContext(c), &task, versions); err != nil {
+		taskError(c, err)
+		return
+	}
+	setTaskETag(c, &task)
+	setWIPWarnings(c, &task)
 	c.JSON(http.StatusOK, task)
 }
 
-// PUT /tasks
-func (h *TaskHandler) UpdateTask(c *gin.Context) {
-	var task models.Task
-	if err := c.ShouldBindJSON(&task); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+// PATCH /tasks/:id
+// Частичное обновление по семантике JSON Merge Patch: отсутствующее поле не меняется,
+// явный null очищает его. Неизвестные поля отклоняются (additionalProperties: false).
+func (h *TaskHandler) PatchTask(c *gin.Context, id uuid.UUID, body api.TaskPatch) {
+	versions, err := ifMatch(c)
+	if err != nil {
+		_ = c.Error(err)
 		return
 	}
 
-	if err := h.repo.UpdateTask(context.Background(), &task); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	patch := taskPatchFromAPI(body)
+
+	task, err := h.repo.PatchTask(actorContext(c), id, &patch, versions)
+	if err != nil {
+		taskError(c, err)
 		return
 	}
+	setTaskETag(c, task)
+	setWIPWarnings(c, task)
 	c.JSON(http.StatusOK, task)
 }
 
-// DELETE
-func (h *TaskHandler) DeleteTask(c *gin.Context) {
-	idStr := c.Query("id")
-	id := uuid.MustParse(idStr)
+// DELETE /tasks/:id
+func (h *TaskHandler) DeleteTask(c *gin.Context, id uuid.UUID) {
+	versions, err := ifMatch(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
 
-	if err := h.repo.DeleteTask(context.Background(), id); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.repo.DeleteTask(actorContext(c), id, versions); err != nil {
+		taskError(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"status": "deleted"})
+	c.Status(http.StatusNoContent)
 }
 
-func (h *TaskHandler) MoveTask(c *gin.Context) {
-	idStr := c.Query("id")
-	if idStr == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "id parameter is required"})
+// POST /tasks/archive
+func (h *TaskHandler) ArchiveTasks(c *gin.Context, body api.ArchiveRequest) {
+	ids, err := h.repo.ArchiveTasks(actorContext(c), body.Ids)
+	if err != nil {
+		_ = c.Error(err)
 		return
 	}
+	c.JSON(http.StatusOK, api.ArchiveResult{Ids: ids})
+}
 
-	id, err := uuid.Parse(idStr)
+// POST /tasks/unarchive
+func (h *TaskHandler) UnarchiveTasks(c *gin.Context, body api.ArchiveRequest) {
+	tasks, err := h.repo.UnarchiveTasks(actorContext(c), body.Ids)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid uuid format"})
+		_ = c.Error(err)
 		return
 	}
+	ids := make([]uuid.UUID, 0, len(tasks))
+	for i := range tasks {
+		setWIPWarnings(c, &tasks[i])
+		ids = append(ids, tasks[i].ID)
+	}
+	c.JSON(http.StatusOK, api.ArchiveResult{Ids: ids})
+}
 
-	var req struct {
-		Space  string `json:"space" binding:"required"`
-		Status string `json:"status" binding:"required"`
+// POST /tasks/{id}/restore
+func (h *TaskHandler) RestoreTask(c *gin.Context, id uuid.UUID) {
+	task, err := h.repo.RestoreTask(actorContext(c), id)
+	if err != nil {
+		_ = c.Error(err)
+		return
 	}
+	setTaskETag(c, task)
+	setWIPWarnings(c, task)
+	c.JSON(http.StatusOK, task)
+}
 
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+// GET /trash
+func (h *TaskHandler) ListTrash(c *gin.Context, params api.ListTrashParams) {
+	page, err := h.repo.ListTrash(actorContext(c), params.BoardID, params.Limit, params.Offset)
+	if err != nil {
+		_ = c.Error(err)
 		return
 	}
+	c.JSON(http.StatusOK, models.TaskList{
+		Items:  page.Tasks,
+		Total:  page.Total,
+		Limit:  params.Limit,
+		Offset: params.Offset,
+	})
+}
 
+// POST /tasks/:id/move
+func (h *TaskHandler) MoveTask(c *gin.Context, id uuid.UUID, body api.MoveTaskRequest) {
 	// Валидация значений
-	validSpaces := map[string]bool{
-		models.SpaceBacklog: true, models.SpaceTodo: true, models.SpaceInProgress: true,
-		models.SpaceReview: true, models.SpaceDone: true,
+	if body.Space == nil && body.ColumnID == nil && body.AfterID == nil && body.BeforeID == nil {
+		_ = c.Error(apperror.BadRequest("space, column_id, after_id or before_id is required",
+			apperror.FieldError{Name: "space", In: "body", Reason: "space, column_id, after_id or before_id is required"}))
+		return
 	}
-	validStatuses := map[string]bool{
-		models.StatusTodo: true, models.StatusInProgress: true,
-		models.StatusReview: true, models.StatusDone: true,
+
+	versions, err := ifMatch(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
 	}
 
-	if !validSpaces[req.Space] {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid space"})
+	move := models.TaskMove{ColumnID: body.ColumnID, AfterID: body.AfterID, BeforeID: body.BeforeID}
+	if body.Space != nil {
+		move.Space = *body.Space
+	}
+	if body.Status != nil {
+		move.Status = *body.Status
+	}
+	task, err := h.repo.MoveTaskToSpace(actorContext(c), id, move, versions)
+	if err != nil {
+		taskError(c, err)
 		return
 	}
-	if !validStatuses[req.Status] {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid status"})
+
+	setTaskETag(c, task)
+	setWIPWarnings(c, task)
+	c.JSON(http.StatusOK, task)
+}
+
+// GET /tasks/{id}/transitions
+// Переходы из текущего статуса задачи по графу её доски; роли проверяются для текущего пользователя
+func (h *TaskHandler) GetTaskTransitions(c *gin.Context, id uuid.UUID) {
+	transitions, err := h.repo.GetTaskTransitions(actorContext(c), id)
+	if err != nil {
+		_ = c.Error(err)
 		return
 	}
+	c.JSON(http.StatusOK, transitions)
+}
 
-	if err := h.repo.MoveTaskToSpace(context.Background(), id, req.Space, req.Status); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+// GET /tasks/{id}/history
+// История изменений задачи, новые события первыми; доступна и после удаления задачи
+func (h *TaskHandler) GetTaskHistory(c *gin.Context, id uuid.UUID, params api.GetTaskHistoryParams) {
+	page, err := h.repo.GetTaskHistory(actorContext(c), id, params.Limit, params.Offset)
+	if err != nil {
+		_ = c.Error(err)
 		return
 	}
+	c.JSON(http.StatusOK, page)
+}
 
-	c.JSON(http.StatusOK, gin.H{"mesage": "moved", "space": req.Space, "status": req.Status})
+// setWIPWarnings отдаёт превышенные soft WIP-лимиты в заголовках Warning (RFC 7234, 5.5)
+func setWIPWarnings(c *gin.Context, task *models.Task) {
+	for _, w := range task.Warnings {
+		c.Writer.Header().Add("Warning", "199 - "+strconv.Quote(w))
+	}
 }
 
-// AddTaskDependency добавляет зависимость между задачами
-func (h *TaskHandler) AddTaskDependency(c *gin.Context) {
-	var req struct {
-		TaskID          string `json:"task_id" binding:"required"`
-		DependentTaskID string `json:"dependent_task_id" binding:"required"`
+// actorContext контекст запроса с текущим пользователем: по нему проверяются права
+// на доски, роли в условиях переходов workflow и записывается автор изменения в истории задачи
+func actorContext(c *gin.Context) context.Context {
+	ctx := c.Request.Context()
+	if userID, ok := middleware.GetUserIDFromContext(c); ok {
+		ctx = requser.WithUser(ctx, userID)
 	}
+	return ctx
+}
 
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
+// mineFilter id текущего пользователя для фильтра mine или nil, если фильтр не запрошен.
+// Без пользователя в контексте фильтр не находит ни одной задачи.
+func mineFilter(c *gin.Context, mine bool) *int {
+	if !mine {
+		return nil
 	}
+	userID, _ := middleware.GetUserIDFromContext(c)
+	return &userID
+}
 
-	taskID, err := uuid.Parse(req.TaskID)
+// POST /tasks/:id/assignees
+// AddTaskAssignee добавляет исполнителя; он становится и наблюдателем задачи
+func (h *TaskHandler) AddTaskAssignee(c *gin.Context, id uuid.UUID, body api.AddAssigneeRequest) {
+	task, err := h.repo.AddAssignee(actorContext(c), id, body.UserID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid task_id format"})
+		_ = c.Error(err)
 		return
 	}
+	setTaskETag(c, task)
+	setWIPWarnings(c, task)
+	c.JSON(http.StatusOK, task)
+}
 
-	dependentTaskID, err := uuid.Parse(req.DependentTaskID)
+// DELETE /tasks/:id/assignees/:user_id
+func (h *TaskHandler) RemoveTaskAssignee(c *gin.Context, id uuid.UUID, userID int) {
+	task, err := h.repo.RemoveAssignee(actorContext(c), id, userID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid dependent_task_id format"})
+		_ = c.Error(err)
 		return
 	}
+	setTaskETag(c, task)
+	c.JSON(http.StatusOK, task)
+}
 
-	// Проверка существования обеих задач
-	_, err = h.repo.GetTaskByID(c, taskID)
+// GET /tasks/:id/watchers
+func (h *TaskHandler) ListTaskWatchers(c *gin.Context, id uuid.UUID) {
+	watchers, err := h.repo.ListWatchers(actorContext(c), id)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "task not found"})
+		_ = c.Error(err)
 		return
 	}
+	c.JSON(http.StatusOK, watchers)
+}
 
-	_, err = h.repo.GetTaskByID(c, dependentTaskID)
-	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "dependent task not found"})
+// POST /tasks/:id/watchers
+// AddTaskWatcher подписывает на задачу пользователя из тела, а без него — текущего
+func (h *TaskHandler) AddTaskWatcher(c *gin.Context, id uuid.UUID, body api.AddWatcherRequest) {
+	var userID int
+	if body.UserID != nil {
+		userID = *body.UserID
+	} else if current, ok := middleware.GetUserIDFromContext(c); ok {
+		userID = current
+	} else {
+		_ = c.Error(apperror.Validation("user_id is required",
+			apperror.FieldError{Name: "user_id", Reason: "user_id is required"}))
 		return
 	}
 
-	if err := h.repo.AddDependency(c, taskID, dependentTaskID); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	watchers, err := h.repo.AddWatcher(actorContext(c), id, userID)
+	if err != nil {
+		_ = c.Error(err)
 		return
 	}
-
-	c.JSON(http.StatusOK, gin.H{"status": "dependency added"})
+	c.JSON(http.StatusOK, watchers)
 }
 
-func (h *TaskHandler) RemoveTaskDependency(c *gin.Context) {
-	var req struct {
-		TaskID          string `json:"task_id" binding:"required"`
-		DependentTaskID string `json:"dependent_task_id" binding:"required"`
+// DELETE /tasks/:id/watchers/:user_id
+func (h *TaskHandler) RemoveTaskWatcher(c *gin.Context, id uuid.UUID, userID int) {
+	watchers, err := h.repo.RemoveWatcher(actorContext(c), id, userID)
+	if err != nil {
+		_ = c.Error(err)
+		return
 	}
+	c.JSON(http.StatusOK, watchers)
+}
 
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
+// POST /tasks/:id/dependencies
+// AddTaskDependency добавляет связь между задачами. Связь blocks или subtask_of,
+// замыкающая цикл, отклоняется с 409 и путём цикла.
+func (h *TaskHandler) AddTaskDependency(c *gin.Context, taskID uuid.UUID, body api.AddDependencyRequest) {
+	dependentTaskID := body.DependentTaskID
+	relation := models.DefaultRelation
+	if body.Relation != nil {
+		relation = string(*body.Relation)
 	}
 
-	taskID, err := uuid.Parse(req.TaskID)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid task_id format"})
+	// существование задач и права на них проверяются в транзакции AddDependency
+	if err := h.repo.AddDependency(actorContext(c), taskID, dependentTaskID, relation); err != nil {
+		_ = c.Error(err)
 		return
 	}
 
-	dependentTaskID, err := uuid.Parse(req.DependentTaskID)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid dependent_task_id format"})
-		return
+	c.JSON(http.StatusOK, gin.H{"status": "dependency added"})
+}
+
+// DELETE /tasks/:id/dependencies/:dependent_id
+func (h *TaskHandler) RemoveTaskDependency(c *gin.Context, taskID, dependentTaskID uuid.UUID, params api.RemoveTaskDependencyParams) {
+	var relation *string
+	if params.Relation != nil {
+		r := string(*params.Relation)
+		relation = &r
 	}
 
-	if err := h.repo.RemoveDependency(c, taskID, dependentTaskID); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.repo.RemoveDependency(actorContext(c), taskID, dependentTaskID, relation); err != nil {
+		_ = c.Error(err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"status": "dependency removed"})
 }
 
-func (h *TaskHandler) GetTaskWithDependencies(c *gin.Context) {
-	idStr := c.Query("id")
-	if idStr == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "id parameter is required"})
+// GET /tasks/:id/dependencies
+func (h *TaskHandler) GetTaskWithDependencies(c *gin.Context, id uuid.UUID) {
+	task, err := h.repo.GetTaskByIDWithDependencies(actorContext(c), id)
+	if err != nil {
+		_ = c.Error(err)
 		return
 	}
 
-	id, err := uuid.Parse(idStr)
+	c.JSON(http.StatusOK, task)
+}
+
+// GET /tasks/:id/graph
+// Граф связей задачи: JSON или текст для Graphviz / Mermaid
+func (h *TaskHandler) GetTaskGraph(c *gin.Context, id uuid.UUID, params api.GetTaskGraphParams) {
+	q := models.TaskGraphQuery{
+		Root:      id,
+		Direction: string(params.Direction),
+		Depth:     params.Depth,
+	}
+	if params.Relation != nil {
+		q.Relation = string(*params.Relation)
+	}
+
+	graph, err := h.repo.GetTaskGraph(actorContext(c), q)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid uuid format"})
+		_ = c.Error(err)
 		return
 	}
 
-	task, err := h.repo.GetTaskByIDWithDependencies(c, id)
+	switch params.Format {
+	case api.GetTaskGraphParamsFormatDot:
+		c.Data(http.StatusOK, "text/vnd.graphviz; charset=utf-8", []byte(graph.DOT()))
+	case api.GetTaskGraphParamsFormatMermaid:
+		c.Data(http.StatusOK, "text/plain; charset=utf-8", []byte(graph.Mermaid()))
+	default:
+		c.JSON(http.StatusOK, graph)
+	}
+}
+
+// GET /tasks/{id}/schedule
+func (h *TaskHandler) GetTaskSchedule(c *gin.Context, id uuid.UUID) {
+	h.schedule(c, &id, nil)
+}
+
+// GET /schedule
+func (h *TaskHandler) GetSchedule(c *gin.Context, params api.GetScheduleParams) {
+	h.schedule(c, nil, params.BoardID)
+}
+
+func (h *TaskHandler) schedule(c *gin.Context, root, boardID *uuid.UUID) {
+	// в БД хранится timestamp без часового пояса в UTC
+	s, err := h.repo.GetSchedule(actorContext(c), root, boardID, time.Now().UTC())
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "task not found"})
+		_ = c.Error(err)
 		return
 	}
-
-	c.JSON(http.StatusOK, task)
+	c.JSON(http.StatusOK, s)
 }
@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"github.com/TrueSmartcomm/backend/api"
+	"github.com/TrueSmartcomm/backend/internal/apperror"
+	"github.com/google/uuid"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Устаревшие маршруты (id в query-параметре или в теле запроса) не описаны
+// в спецификации. Обработчики ниже только извлекают идентификаторы
+// и вызывают соответствующие операции TasksServer.
+
+// queryTaskID возвращает id задачи из query-параметра ?id=
+func queryTaskID(c *gin.Context) (uuid.UUID, error) {
+	idStr := c.Query("id")
+	if idStr == "" {
+		return uuid.Nil, apperror.BadRequest("id parameter is required",
+			apperror.FieldError{Name: "id", In: "query", Reason: "is required"})
+	}
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return uuid.Nil, apperror.BadRequest("invalid uuid format",
+			apperror.FieldError{Name: "id", In: "query", Reason: "must be a UUID"})
+	}
+	return id, nil
+}
+
+// PUT /tasks (id в теле)
+func (h *TaskHandler) LegacyUpdateTask(c *gin.Context) {
+	var req struct {
+		ID uuid.UUID `json:"id"`
+		api.TaskInput
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apperror.BadRequest(err.Error()))
+		return
+	}
+	h.UpdateTask(c, req.ID, req.TaskInput)
+}
+
+// DELETE /tasks?id=
+func (h *TaskHandler) LegacyDeleteTask(c *gin.Context) {
+	id, err := queryTaskID(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	h.DeleteTask(c, id)
+}
+
+// POST /tasks/move?id=
+func (h *TaskHandler) LegacyMoveTask(c *gin.Context) {
+	id, err := queryTaskID(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	var body api.MoveTaskRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		_ = c.Error(apperror.BadRequest(err.Error()))
+		return
+	}
+	h.MoveTask(c, id, body)
+}
+
+// legacyDependencyRequest тело запросов /tasks/dependency
+type legacyDependencyRequest struct {
+	TaskID          uuid.UUID `json:"task_id"`
+	DependentTaskID uuid.UUID `json:"dependent_task_id"`
+}
+
+func bindLegacyDependency(c *gin.Context) (legacyDependencyRequest, error) {
+	var req legacyDependencyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		return req, apperror.BadRequest(err.Error())
+	}
+	if req.TaskID == uuid.Nil || req.DependentTaskID == uuid.Nil {
+		return req, apperror.BadRequest("task_id and dependent_task_id are required")
+	}
+	return req, nil
+}
+
+// POST /tasks/dependency
+func (h *TaskHandler) LegacyAddTaskDependency(c *gin.Context) {
+	req, err := bindLegacyDependency(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	h.AddTaskDependency(c, req.TaskID, api.AddDependencyRequest{DependentTaskID: req.DependentTaskID})
+}
+
+// DELETE /tasks/dependency
+func (h *TaskHandler) LegacyRemoveTaskDependency(c *gin.Context) {
+	req, err := bindLegacyDependency(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	h.RemoveTaskDependency(c, req.TaskID, req.DependentTaskID, api.RemoveTaskDependencyParams{})
+}
+
+// GET /tasks/with-dependencies?id=
+func (h *TaskHandler) LegacyGetTaskWithDependencies(c *gin.Context) {
+	id, err := queryTaskID(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	h.GetTaskWithDependencies(c, id)
+}
@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/TrueSmartcomm/backend/api"
+	"github.com/TrueSmartcomm/backend/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// MemberHandler реализует операции спецификации с тегом members
+type MemberHandler struct {
+	repo *repository.MemberRepository
+}
+
+var _ api.MembersServer = (*MemberHandler)(nil)
+
+func NewMemberHandler(repo *repository.MemberRepository) *MemberHandler {
+	return &MemberHandler{repo: repo}
+}
+
+// GET /members
+func (h *MemberHandler) ListWorkspaceMembers(c *gin.Context) {
+	h.list(c, nil)
+}
+
+// PUT /members/{user_id}
+func (h *MemberHandler) SetWorkspaceMember(c *gin.Context, userID int, body api.MemberInput) {
+	h.set(c, nil, userID, body)
+}
+
+// DELETE /members/{user_id}
+func (h *MemberHandler) RemoveWorkspaceMember(c *gin.Context, userID int) {
+	h.remove(c, nil, userID)
+}
+
+// GET /boards/{id}/members
+func (h *MemberHandler) ListBoardMembers(c *gin.Context, id uuid.UUID) {
+	h.list(c, &id)
+}
+
+// PUT /boards/{id}/members/{user_id}
+func (h *MemberHandler) SetBoardMember(c *gin.Context, id uuid.UUID, userID int, body api.MemberInput) {
+	h.set(c, &id, userID, body)
+}
+
+// DELETE /boards/{id}/members/{user_id}
+func (h *MemberHandler) RemoveBoardMember(c *gin.Context, id uuid.UUID, userID int) {
+	h.remove(c, &id, userID)
+}
+
+// list участники доски boardID или, без неё, рабочего пространства
+func (h *MemberHandler) list(c *gin.Context, boardID *uuid.UUID) {
+	members, err := h.repo.ListMembers(actorContext(c), boardID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, members)
+}
+
+func (h *MemberHandler) set(c *gin.Context, boardID *uuid.UUID, userID int, body api.MemberInput) {
+	member, err := h.repo.SetMember(actorContext(c), boardID, userID, string(body.Role))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, member)
+}
+
+func (h *MemberHandler) remove(c *gin.Context, boardID *uuid.UUID, userID int) {
+	if err := h.repo.RemoveMember(actorContext(c), boardID, userID); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
@@ -2,9 +2,9 @@ package middleware
 
 import (
 	"log"
-	"net/http"
 	"strings"
 
+	"github.com/TrueSmartcomm/backend/internal/apperror"
 	"github.com/TrueSmartcomm/backend/internal/auth"
 	"github.com/gin-gonic/gin"
 )
@@ -22,8 +22,8 @@ func AuthRequired(authService *auth.AuthService) gin.HandlerFunc {
 
 		//Проверить, начинается ли он с "Bearer "
 		if authHeader == "" || !strings.HasPrefix(authHeader, "Bearer ") {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header is missing or invalid"})
-			c.Abort() // Прерываем цепочку выполнения
+			// Прерываем цепочку выполнения
+			Fail(c, apperror.Unauthorized(apperror.CodeInvalidToken, "Authorization header is missing or invalid"))
 			return
 		}
 
@@ -34,8 +34,7 @@ func AuthRequired(authService *auth.AuthService) gin.HandlerFunc {
 		userID, err := authService.ValidateToken(tokenString)
 		if err != nil {
 			log.Printf("Auth Middleware: Invalid token - %v", err)
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
-			c.Abort()
+			Fail(c, err)
 			return
 		}
 
@@ -0,0 +1,22 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+// Deprecated возвращает middleware для устаревших маршрутов: выставляет заголовок
+// Deprecation и ссылку на маршрут, который следует использовать вместо него
+func Deprecated(successor string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		MarkDeprecated(c, successor)
+		c.Next()
+	}
+}
+
+// MarkDeprecated помечает ответ как устаревший (draft-ietf-httpapi-deprecation-header)
+func MarkDeprecated(c *gin.Context, successor string) {
+	c.Header("Deprecation", "true")
+	if successor != "" {
+		c.Header("Link", "<"+successor+">; rel=\"successor-version\"")
+	}
+}
@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"log"
+
+	"github.com/TrueSmartcomm/backend/internal/apperror"
+	"github.com/gin-gonic/gin"
+)
+
+// Errors отвечает клиенту в формате application/problem+json, если обработчик
+// завершился ошибкой, добавленной через c.Error. Подключается первым, чтобы
+// обрабатывать ошибки всех последующих middleware и обработчиков.
+func Errors() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+		WriteProblem(c, c.Errors.Last().Err)
+	}
+}
+
+// WriteProblem переводит ошибку в problem+json и прерывает цепочку обработчиков.
+// Причины внутренних ошибок пишутся в лог и клиенту не отдаются.
+func WriteProblem(c *gin.Context, err error) {
+	appErr := apperror.From(err)
+	if appErr.Kind == apperror.KindInternal {
+		log.Printf("[ERROR] %s %s: %v", c.Request.Method, c.Request.URL.Path, err)
+	}
+	c.Header("Content-Type", apperror.ProblemContentType)
+	c.AbortWithStatusJSON(appErr.Kind.Status(), appErr.Problem(c.Request.URL.Path))
+}
+
+// Fail регистрирует ошибку для middleware Errors и прерывает цепочку
+func Fail(c *gin.Context, err error) {
+	_ = c.Error(err)
+	c.Abort()
+}
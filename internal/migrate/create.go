@@ -0,0 +1,58 @@
+package migrate
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/pressly/goose/v3"
+)
+
+// template заготовка новой миграции
+const template = `-- +goose Up
+-- +goose StatementBegin
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+-- +goose StatementEnd
+`
+
+var nonWord = regexp.MustCompile(`[^a-z0-9]+`)
+
+// Create создаёт в dir файл следующей по номеру миграции и возвращает его путь
+func Create(dir, name string) (string, error) {
+	name = strings.Trim(nonWord.ReplaceAllString(strings.ToLower(name), "_"), "_")
+	if name == "" {
+		return "", fmt.Errorf("migration name is required")
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.sql"))
+	if err != nil {
+		return "", err
+	}
+	var next int64 = 1
+	for _, file := range files {
+		v, err := goose.NumericComponent(file)
+		if err != nil {
+			return "", err
+		}
+		next = max(next, v+1)
+	}
+
+	path := filepath.Join(dir, fmt.Sprintf("%05d_%s.sql", next, name))
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	if _, err := f.WriteString(template); err != nil {
+		return "", err
+	}
+	return path, nil
+}
@@ -0,0 +1,127 @@
+// Package migrate применяет SQL-миграции из migrations/ через goose без внешнего CLI.
+// Состояние хранится в таблице goose_db_version, поэтому базы, которые раньше
+// мигрировали goose CLI, продолжают работать.
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io/fs"
+	"log"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5/stdlib"
+	"github.com/pressly/goose/v3"
+	"github.com/pressly/goose/v3/lock"
+)
+
+// lockKey ключ advisory lock, под которым выполняются миграции.
+// Несколько реплик, стартующих одновременно, применяют миграции по очереди.
+const lockKey int64 = 0x74736d6d6967
+
+// Migrator применяет встроенные миграции к базе
+type Migrator struct {
+	db *sql.DB
+	// locked меняет схему под advisory lock; status только читает goose_db_version
+	// и не ждёт, пока другая реплика закончит миграции
+	locked *goose.Provider
+	status *goose.Provider
+}
+
+// New загружает миграции из fsys
+func New(pool *pgxpool.Pool, fsys fs.FS) (*Migrator, error) {
+	db := stdlib.OpenDBFromPool(pool)
+	locker, err := lock.NewPostgresSessionLocker(lock.WithLockID(lockKey))
+	if err != nil {
+		return nil, err
+	}
+	locked, err := goose.NewProvider(goose.DialectPostgres, db, fsys, goose.WithSessionLocker(locker))
+	if err != nil {
+		return nil, fmt.Errorf("load migrations: %w", err)
+	}
+	status, err := goose.NewProvider(goose.DialectPostgres, db, fsys)
+	if err != nil {
+		return nil, fmt.Errorf("load migrations: %w", err)
+	}
+	return &Migrator{db: db, locked: locked, status: status}, nil
+}
+
+// Close освобождает соединения Migrator; пул, переданный в New, остаётся открытым
+func (m *Migrator) Close() error {
+	return m.db.Close()
+}
+
+// Status состояние одной миграции
+type Status struct {
+	Version   int64
+	Name      string
+	AppliedAt *time.Time // nil — не применена
+}
+
+// Up применяет все неприменённые миграции по порядку
+func (m *Migrator) Up(ctx context.Context) error {
+	results, err := m.locked.Up(ctx)
+	logResults(results...)
+	if err != nil {
+		return err
+	}
+	if len(results) == 0 {
+		current, err := m.status.GetDBVersion(ctx)
+		if err != nil {
+			return err
+		}
+		log.Printf("[INFO] migrations: database is up to date (version %d)", current)
+	}
+	return nil
+}
+
+// Down откатывает последнюю применённую миграцию
+func (m *Migrator) Down(ctx context.Context) error {
+	result, err := m.locked.Down(ctx)
+	logResults(result)
+	return err
+}
+
+// Redo откатывает и заново применяет последнюю миграцию
+func (m *Migrator) Redo(ctx context.Context) error {
+	down, err := m.locked.Down(ctx)
+	logResults(down)
+	if err != nil {
+		return err
+	}
+	up, err := m.locked.ApplyVersion(ctx, down.Source.Version, true)
+	logResults(up)
+	return err
+}
+
+// Status возвращает состояние всех известных миграций. Блокировка миграций не берётся:
+// во время применения миграций другой репликой состояние может быть промежуточным.
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	statuses, err := m.status.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]Status, 0, len(statuses))
+	for _, st := range statuses {
+		s := Status{Version: st.Source.Version, Name: st.Source.Path}
+		if st.State == goose.StateApplied {
+			at := st.AppliedAt
+			s.AppliedAt = &at
+		}
+		result = append(result, s)
+	}
+	return result, nil
+}
+
+// logResults пишет в лог применённые и откатанные миграции
+func logResults(results ...*goose.MigrationResult) {
+	for _, r := range results {
+		// Ошибка миграции возвращается вызывающему вместе с именем файла
+		if r == nil || r.Error != nil {
+			continue
+		}
+		log.Printf("[INFO] migrations: %s %s (%s)", r.Direction, r.Source.Path, r.Duration.Round(time.Millisecond))
+	}
+}
@@ -0,0 +1,41 @@
+package models
+
+import "github.com/google/uuid"
+
+// Ограничения числа карточек колонки в снимке доски
+const (
+	DefaultSnapshotCards = 100
+	MaxSnapshotCards     = 500
+)
+
+// BoardSnapshot доска целиком: колонки с карточками и сводки по задачам,
+// подходящим под фильтры запроса
+type BoardSnapshot struct {
+	ID          uuid.UUID        `json:"id"`
+	Name        string           `json:"name"`
+	Description string           `json:"description"`
+	IsDefault   bool             `json:"is_default"`
+	Columns     []ColumnSnapshot `json:"columns"` // в порядке position
+	Total       int              `json:"total"`
+	Assignees   []AssigneeCount  `json:"assignees"`  // по убыванию count, без исполнителя — последними
+	Priorities  []PriorityCount  `json:"priorities"` // от urgent к low
+}
+
+// ColumnSnapshot колонка доски с карточками в порядке rank
+type ColumnSnapshot struct {
+	Column
+	Count int    `json:"count"` // задач в колонке; Tasks может содержать меньше
+	Tasks []Task `json:"tasks"`
+}
+
+// AssigneeCount число задач исполнителя; Assignee nil — задачи без исполнителя
+type AssigneeCount struct {
+	Assignee *UserSummary `json:"assignee"`
+	Count    int          `json:"count"`
+}
+
+// PriorityCount число задач с приоритетом
+type PriorityCount struct {
+	Priority string `json:"priority"`
+	Count    int    `json:"count"`
+}
@@ -0,0 +1,217 @@
+package models
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/google/uuid"
+)
+
+// BoardStatus статус задач доски. Final — задача в этом статусе считается завершённой:
+// она не блокирует другие задачи и не учитывается в расписании.
+type BoardStatus struct {
+	Key      string `db:"key" json:"key"`
+	Name     string `db:"name" json:"name"`
+	Position int    `db:"position" json:"position"`
+	Final    bool   `db:"final" json:"final"`
+}
+
+// BoardTransition разрешённый переход между статусами доски и его условия
+type BoardTransition struct {
+	From            string   `db:"from_status" json:"from"`
+	To              string   `db:"to_status" json:"to"`
+	RequiredFields  []string `db:"required_fields" json:"required_fields"` // поля задачи, которые должны быть заполнены
+	RequireAssignee bool     `db:"require_assignee" json:"require_assignee"`
+	Roles           []string `db:"roles" json:"roles"` // пусто — переход доступен всем
+}
+
+// Workflow статусы доски и граф переходов между ними.
+// Пустой список переходов разрешает любую смену статуса.
+type Workflow struct {
+	Statuses    []BoardStatus     `json:"statuses"`
+	Transitions []BoardTransition `json:"transitions"`
+}
+
+// Роли пользователя относительно задачи, на которые ссылаются условия переходов
+const (
+	RoleTaskOwner = "task_owner" // владелец задачи; не путать с ролью owner в рабочем пространстве и на доске
+	RoleAssignee  = "assignee"   // исполнитель задачи
+)
+
+// TaskRoles роли пользователя userID относительно задачи; 0 — пользователь неизвестен
+func TaskRoles(t *Task, userID int) []string {
+	var roles []string
+	if userID == 0 {
+		return roles
+	}
+	if t.OwnerID != nil && *t.OwnerID == userID {
+		roles = append(roles, RoleTaskOwner)
+	}
+	if slices.Contains(t.AssigneeIDs, userID) {
+		roles = append(roles, RoleAssignee)
+	}
+	return roles
+}
+
+// transitionFields поля задачи, которые можно потребовать заполнить перед переходом
+var transitionFields = map[string]func(t *Task) bool{
+	"description":    func(t *Task) bool { return t.Description != "" },
+	"assignees":      func(t *Task) bool { return len(t.AssigneeIDs) > 0 },
+	"due_date":       func(t *Task) bool { return t.DueDate != nil },
+	"estimate_hours": func(t *Task) bool { return t.EstimateHours != nil },
+}
+
+var transitionRoles = []string{RoleTaskOwner, RoleAssignee}
+
+// MissingFields поля, которые нужно заполнить для перехода (с учётом RequireAssignee)
+func (tr *BoardTransition) MissingFields(t *Task) []string {
+	var missing []string
+	for _, f := range tr.RequiredFields {
+		if filled, ok := transitionFields[f]; ok && !filled(t) {
+			missing = append(missing, f)
+		}
+	}
+	if tr.RequireAssignee && !transitionFields["assignees"](t) && !slices.Contains(missing, "assignees") {
+		missing = append(missing, "assignees")
+	}
+	return missing
+}
+
+// Permits сообщает, доступен ли переход пользователю с ролями roles
+func (tr *BoardTransition) Permits(roles []string) bool {
+	if len(tr.Roles) == 0 {
+		return true
+	}
+	for _, r := range roles {
+		if slices.Contains(tr.Roles, r) {
+			return true
+		}
+	}
+	return false
+}
+
+// Status статус workflow по ключу
+func (w *Workflow) Status(key string) (BoardStatus, bool) {
+	for _, s := range w.Statuses {
+		if s.Key == key {
+			return s, true
+		}
+	}
+	return BoardStatus{}, false
+}
+
+// Restricted сообщает, ограничены ли переходы графом
+func (w *Workflow) Restricted() bool {
+	return len(w.Transitions) > 0
+}
+
+// Transition переход from → to или nil, если его нет в графе
+func (w *Workflow) Transition(from, to string) *BoardTransition {
+	for i := range w.Transitions {
+		if w.Transitions[i].From == from && w.Transitions[i].To == to {
+			return &w.Transitions[i]
+		}
+	}
+	return nil
+}
+
+// Next статусы, в которые можно перейти из from (без учёта условий переходов)
+func (w *Workflow) Next(from string) []string {
+	var next []string
+	for _, s := range w.Statuses {
+		if s.Key == from {
+			continue
+		}
+		if !w.Restricted() || w.Transition(from, s.Key) != nil {
+			next = append(next, s.Key)
+		}
+	}
+	return next
+}
+
+// DefaultWorkflow статусы новой доски: прежний фиксированный набор без ограничений переходов
+func DefaultWorkflow() Workflow {
+	return Workflow{
+		Statuses: []BoardStatus{
+			{Key: StatusTodo, Name: "To Do"},
+			{Key: StatusInProgress, Name: "In Progress", Position: 1},
+			{Key: StatusReview, Name: "Review", Position: 2},
+			{Key: StatusDone, Name: "Done", Position: 3, Final: true},
+		},
+		Transitions: []BoardTransition{},
+	}
+}
+
+// Validate проверяет статусы и переходы. Позиции статусов выставляются по порядку в списке.
+func (w *Workflow) Validate() error {
+	if len(w.Statuses) == 0 {
+		return &ValidationError{"statuses", "at least one status is required"}
+	}
+	keys := map[string]bool{}
+	for i := range w.Statuses {
+		s := &w.Statuses[i]
+		s.Position = i
+		field := fmt.Sprintf("statuses[%d]", i)
+		if !columnKeyRe.MatchString(s.Key) {
+			return &ValidationError{field + ".key", "key must be 1-50 characters of a-z, 0-9 and _"}
+		}
+		if s.Name == "" {
+			return &ValidationError{field + ".name", "name is required"}
+		}
+		if keys[s.Key] {
+			return &ValidationError{field + ".key", "duplicate status key " + s.Key}
+		}
+		keys[s.Key] = true
+	}
+
+	pairs := map[[2]string]bool{}
+	for i := range w.Transitions {
+		tr := &w.Transitions[i]
+		field := fmt.Sprintf("transitions[%d]", i)
+		if !keys[tr.From] {
+			return &ValidationError{field + ".from", "unknown status " + tr.From}
+		}
+		if !keys[tr.To] {
+			return &ValidationError{field + ".to", "unknown status " + tr.To}
+		}
+		if tr.From == tr.To {
+			return &ValidationError{field, "transition must change the status"}
+		}
+		if pairs[[2]string{tr.From, tr.To}] {
+			return &ValidationError{field, "duplicate transition " + tr.From + " -> " + tr.To}
+		}
+		pairs[[2]string{tr.From, tr.To}] = true
+		for _, f := range tr.RequiredFields {
+			if _, ok := transitionFields[f]; !ok {
+				return &ValidationError{field + ".required_fields", "unsupported field " + f}
+			}
+		}
+		for _, r := range tr.Roles {
+			if !slices.Contains(transitionRoles, r) {
+				return &ValidationError{field + ".roles", "unknown role " + r}
+			}
+		}
+		if tr.RequiredFields == nil {
+			tr.RequiredFields = []string{}
+		}
+		if tr.Roles == nil {
+			tr.Roles = []string{}
+		}
+	}
+	return nil
+}
+
+// TaskTransition переход, доступный задаче из её текущего статуса (GET /tasks/{id}/transitions)
+type TaskTransition struct {
+	To              string   `json:"to"`
+	Name            string   `json:"name"`
+	Final           bool     `json:"final"`
+	RequiredFields  []string `json:"required_fields"`
+	RequireAssignee bool     `json:"require_assignee"`
+	Roles           []string `json:"roles"`
+	Available       bool     `json:"available"`                // условия перехода выполнены
+	MissingFields   []string `json:"missing_fields,omitempty"` // незаполненные обязательные поля
+	Forbidden       bool     `json:"forbidden,omitempty"`      // у пользователя нет нужной роли
+	// BlockingTasks незавершённые блокирующие задачи и подзадачи (для завершающих статусов)
+	BlockingTasks []uuid.UUID `json:"blocking_tasks,omitempty"`
+}
@@ -0,0 +1,124 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWorkflowValidate(t *testing.T) {
+	statuses := func() []BoardStatus {
+		return []BoardStatus{{Key: "todo", Name: "To Do"}, {Key: "doing", Name: "Doing"}, {Key: "done", Name: "Done", Final: true}}
+	}
+
+	tests := []struct {
+		name        string
+		workflow    Workflow
+		wantField   string // пусто — workflow корректен
+		wantMessage string
+	}{
+		{
+			name: "valid",
+			workflow: Workflow{Statuses: statuses(), Transitions: []BoardTransition{
+				{From: "todo", To: "doing"},
+				{From: "doing", To: "done", RequiredFields: []string{"estimate_hours"}, RequireAssignee: true,
+					Roles: []string{RoleTaskOwner, RoleAssignee}},
+			}},
+		},
+		{
+			name:        "no statuses",
+			workflow:    Workflow{},
+			wantField:   "statuses",
+			wantMessage: "at least one status is required",
+		},
+		{
+			name:        "bad status key",
+			workflow:    Workflow{Statuses: []BoardStatus{{Key: "To Do", Name: "To Do"}}},
+			wantField:   "statuses[0].key",
+			wantMessage: "key must be 1-50 characters of a-z, 0-9 and _",
+		},
+		{
+			name:        "duplicate status",
+			workflow:    Workflow{Statuses: append(statuses(), BoardStatus{Key: "todo", Name: "Again"})},
+			wantField:   "statuses[3].key",
+			wantMessage: "duplicate status key todo",
+		},
+		{
+			name:        "unknown from status",
+			workflow:    Workflow{Statuses: statuses(), Transitions: []BoardTransition{{From: "backlog", To: "todo"}}},
+			wantField:   "transitions[0].from",
+			wantMessage: "unknown status backlog",
+		},
+		{
+			name:        "unknown to status",
+			workflow:    Workflow{Statuses: statuses(), Transitions: []BoardTransition{{From: "todo", To: "closed"}}},
+			wantField:   "transitions[0].to",
+			wantMessage: "unknown status closed",
+		},
+		{
+			name:        "transition to the same status",
+			workflow:    Workflow{Statuses: statuses(), Transitions: []BoardTransition{{From: "todo", To: "todo"}}},
+			wantField:   "transitions[0]",
+			wantMessage: "transition must change the status",
+		},
+		{
+			name: "duplicate transition",
+			workflow: Workflow{Statuses: statuses(), Transitions: []BoardTransition{
+				{From: "todo", To: "doing"}, {From: "doing", To: "done"}, {From: "todo", To: "doing", RequireAssignee: true},
+			}},
+			wantField:   "transitions[2]",
+			wantMessage: "duplicate transition todo -> doing",
+		},
+		{
+			name: "unknown required field",
+			workflow: Workflow{Statuses: statuses(), Transitions: []BoardTransition{
+				{From: "todo", To: "doing", RequiredFields: []string{"story_points"}},
+			}},
+			wantField:   "transitions[0].required_fields",
+			wantMessage: "unsupported field story_points",
+		},
+		{
+			// роль участника доски owner не входит в условия переходов
+			name: "unknown role",
+			workflow: Workflow{Statuses: statuses(), Transitions: []BoardTransition{
+				{From: "todo", To: "doing", Roles: []string{"owner"}},
+			}},
+			wantField:   "transitions[0].roles",
+			wantMessage: "unknown role owner",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.workflow.Validate()
+			if tt.wantField == "" {
+				if err != nil {
+					t.Fatalf("Validate() = %v, want nil", err)
+				}
+				return
+			}
+			want := &ValidationError{Field: tt.wantField, Message: tt.wantMessage}
+			if !reflect.DeepEqual(err, want) {
+				t.Errorf("Validate() = %#v, want %#v", err, want)
+			}
+		})
+	}
+}
+
+// Validate нумерует статусы по порядку и заменяет пустые списки условий на пустые срезы
+func TestWorkflowValidateNormalizes(t *testing.T) {
+	w := Workflow{
+		Statuses:    []BoardStatus{{Key: "todo", Name: "To Do", Position: 7}, {Key: "done", Name: "Done", Position: 3}},
+		Transitions: []BoardTransition{{From: "todo", To: "done"}},
+	}
+	if err := w.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	for i, s := range w.Statuses {
+		if s.Position != i {
+			t.Errorf("%s: position = %d, want %d", s.Key, s.Position, i)
+		}
+	}
+	tr := w.Transitions[0]
+	if tr.RequiredFields == nil || tr.Roles == nil {
+		t.Errorf("transition conditions = %#v, want empty slices", tr)
+	}
+}
@@ -0,0 +1,134 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/TrueSmartcomm/backend/api"
+	"github.com/TrueSmartcomm/backend/internal/apperror"
+	"github.com/google/uuid"
+)
+
+// Board доска (проект) со своими колонками. Задачи принадлежат одной доске.
+type Board struct {
+	ID          uuid.UUID `db:"id" json:"id"`
+	Name        string    `db:"name" json:"name"`
+	Description string    `db:"description" json:"description"`
+	IsDefault   bool      `db:"is_default" json:"is_default"` // сюда попадают задачи без board_id
+	Columns     []Column  `db:"-" json:"columns"`             // в порядке position
+	CreatedAt   time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt   time.Time `db:"updated_at" json:"updated_at"`
+}
+
+// Column колонка доски. Key не меняется после создания и служит kanban_space задач.
+type Column struct {
+	ID       uuid.UUID `db:"id" json:"id"`
+	BoardID  uuid.UUID `db:"board_id" json:"board_id"`
+	Key      string    `db:"key" json:"key"`
+	Name     string    `db:"name" json:"name"`
+	Position int       `db:"position" json:"position"` // с нуля, без пропусков
+	// Status статус, который обязаны иметь задачи в колонке; nil — любой
+	Status *string `db:"status" json:"status"`
+	// WIP-лимиты: сколько задач может быть в колонке всего и у одного исполнителя; nil — без лимита
+	WIPLimit            *int   `db:"wip_limit" json:"wip_limit"`
+	WIPLimitPerAssignee *int   `db:"wip_limit_per_assignee" json:"wip_limit_per_assignee"`
+	WIPMode             string `db:"wip_mode" json:"wip_mode"` // hard — отказ, soft — только предупреждение
+}
+
+// Режимы WIP-лимитов колонки
+const (
+	WIPModeHard = string(api.WIPModeHard)
+	WIPModeSoft = string(api.WIPModeSoft)
+)
+
+// DefaultColumns колонки новой доски, если они не заданы явно; совпадают
+// с прежним фиксированным набором kanban_space
+func DefaultColumns() []Column {
+	return []Column{
+		{Key: SpaceBacklog, Name: "Backlog"},
+		{Key: SpaceTodo, Name: "To Do"},
+		{Key: SpaceInProgress, Name: "In Progress"},
+		{Key: SpaceReview, Name: "Review"},
+		{Key: SpaceDone, Name: "Done"},
+	}
+}
+
+var columnKeyRe = regexp.MustCompile(`^[a-z0-9_]{1,50}$`)
+
+// Validate проверяет доску и её колонки
+func (b *Board) Validate() error {
+	if b.Name == "" {
+		return &ValidationError{"name", "name is required"}
+	}
+	keys := map[string]bool{}
+	for i := range b.Columns {
+		c := &b.Columns[i]
+		if err := c.Validate(); err != nil {
+			return err
+		}
+		if keys[c.Key] {
+			return &ValidationError{"columns", "duplicate column key " + c.Key}
+		}
+		keys[c.Key] = true
+	}
+	return nil
+}
+
+// Validate проверяет колонку
+func (c *Column) Validate() error {
+	if !columnKeyRe.MatchString(c.Key) {
+		return &ValidationError{"key", "key must be 1-50 characters of a-z, 0-9 and _"}
+	}
+	if c.Name == "" {
+		return &ValidationError{"name", "name is required"}
+	}
+	if c.Position < 0 {
+		return &ValidationError{"position", "position must not be negative"}
+	}
+	return c.ValidateWIP()
+}
+
+// ValidateWIP проверяет WIP-лимиты колонки; пустой режим означает hard
+func (c *Column) ValidateWIP() error {
+	if c.WIPLimit != nil && *c.WIPLimit < 1 {
+		return &ValidationError{"wip_limit", "wip_limit must be positive"}
+	}
+	if c.WIPLimitPerAssignee != nil && *c.WIPLimitPerAssignee < 1 {
+		return &ValidationError{"wip_limit_per_assignee", "wip_limit_per_assignee must be positive"}
+	}
+	switch c.WIPMode {
+	case "":
+		c.WIPMode = WIPModeHard
+	case WIPModeHard, WIPModeSoft:
+	default:
+		return &ValidationError{"wip_mode", "wip_mode must be hard or soft"}
+	}
+	return nil
+}
+
+// WIPLimitError задача не помещается в колонку: достигнут WIP-лимит колонки
+// или лимит на одного исполнителя (Assignee не nil)
+type WIPLimitError struct {
+	Column   string // key колонки
+	Assignee *int   // id исполнителя
+	Limit    int
+	Count    int // задач в колонке (у исполнителя) вместе с этой
+}
+
+func (e *WIPLimitError) Error() string {
+	if e.Assignee != nil {
+		return fmt.Sprintf("column %s WIP limit per assignee exceeded for user %d: %d of %d", e.Column, *e.Assignee, e.Count, e.Limit)
+	}
+	return fmt.Sprintf("column %s WIP limit exceeded: %d of %d", e.Column, e.Count, e.Limit)
+}
+
+// AppError 409 Conflict с лимитом и числом задач
+func (e *WIPLimitError) AppError() *apperror.Error {
+	err := apperror.Conflict(apperror.CodeWIPLimitExceeded, e.Error())
+	err.Extensions = map[string]any{"column": e.Column, "wip_limit": e.Limit, "wip_count": e.Count}
+	if e.Assignee != nil {
+		err.Extensions["assignee"] = *e.Assignee
+	}
+	return err
+}
@@ -0,0 +1,7 @@
+package models
+
+// Member пользователь с ролью на доске или во всём рабочем пространстве
+type Member struct {
+	User UserSummary `json:"user"`
+	Role string      `json:"role"`
+}
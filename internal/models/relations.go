@@ -0,0 +1,51 @@
+package models
+
+import (
+	"strings"
+
+	"github.com/TrueSmartcomm/backend/api"
+	"github.com/TrueSmartcomm/backend/internal/apperror"
+	"github.com/google/uuid"
+)
+
+// Типы связей между задачами. Строка task_dependencies (task_id, dependent_task_id, relation)
+// направлена от task_id к dependent_task_id:
+//   - blocks: task_id блокирует dependent_task_id;
+//   - subtask_of: dependent_task_id — подзадача task_id;
+//   - relates_to: задачи связаны, направление не важно;
+//   - duplicates: dependent_task_id дублирует task_id.
+const (
+	RelationBlocks     = string(api.TaskRelationBlocks)
+	RelationSubtaskOf  = string(api.TaskRelationSubtaskOf)
+	RelationRelatesTo  = string(api.TaskRelationRelatesTo)
+	RelationDuplicates = string(api.TaskRelationDuplicates)
+)
+
+// DefaultRelation тип связи, если он не указан: прежние зависимости были подзадачами
+const DefaultRelation = RelationSubtaskOf
+
+// RelationAcyclic сообщает, запрещены ли для типа связи циклы
+func RelationAcyclic(relation string) bool {
+	return relation == RelationBlocks || relation == RelationSubtaskOf
+}
+
+// DependencyCycleError новая связь типа Relation замкнула бы цикл в графе предшествования.
+// Path начинается и заканчивается одной и той же задачей и идёт по порядку выполнения:
+// блокирующая задача раньше заблокированной, подзадача раньше родителя.
+type DependencyCycleError struct {
+	Relation string
+	Path     []uuid.UUID
+}
+
+func (e *DependencyCycleError) Error() string {
+	ids := make([]string, len(e.Path))
+	for i, id := range e.Path {
+		ids[i] = id.String()
+	}
+	return e.Relation + " dependency would create a cycle: " + strings.Join(ids, " -> ")
+}
+
+// AppError 409 Conflict
+func (e *DependencyCycleError) AppError() *apperror.Error {
+	return apperror.Conflict(apperror.CodeDependencyCycle, e.Error())
+}
@@ -0,0 +1,71 @@
+package models
+
+import (
+	"time"
+
+	"github.com/TrueSmartcomm/backend/api"
+	"github.com/TrueSmartcomm/backend/internal/pagination"
+	"github.com/google/uuid"
+)
+
+// Поля, по которым разрешена сортировка списка задач (параметр sort_by спецификации)
+const (
+	SortByCreatedAt = string(api.ListTasksParamsSortByCreatedAt)
+	SortByUpdatedAt = string(api.ListTasksParamsSortByUpdatedAt)
+	SortByDueDate   = string(api.ListTasksParamsSortByDueDate)
+	SortByPriority  = string(api.ListTasksParamsSortByPriority)
+	SortByTitle     = string(api.ListTasksParamsSortByTitle)
+	SortByStatus    = string(api.ListTasksParamsSortByStatus)
+	SortByRank      = string(api.ListTasksParamsSortByRank)
+)
+
+// Выборка архивных задач (параметр archived спецификации)
+const (
+	ArchivedExclude = string(api.ListTasksParamsArchivedExclude)
+	ArchivedInclude = string(api.ListTasksParamsArchivedInclude)
+	ArchivedOnly    = string(api.ListTasksParamsArchivedOnly)
+)
+
+// Ограничения пагинации
+const (
+	DefaultTaskListLimit = 50
+	MaxTaskListLimit     = 200
+)
+
+// TaskFilter параметры выборки списка задач
+type TaskFilter struct {
+	BoardID     *uuid.UUID
+	ColumnID    *uuid.UUID
+	KanbanSpace string
+	Status      string
+	Priority    string
+	OwnerID     *int
+	AssigneeID  *int   // один из исполнителей
+	Mine        *int   // пользователь — владелец, исполнитель или наблюдатель задачи
+	Archived    string // одно из Archived*; пусто — ArchivedExclude
+
+	DueFrom     *time.Time
+	DueTo       *time.Time
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+	UpdatedFrom *time.Time
+	UpdatedTo   *time.Time
+
+	SortBy string // одно из SortBy*
+	Desc   bool
+
+	Limit  int
+	Offset int
+	Cursor *pagination.Cursor // если задан, Offset игнорируется
+}
+
+// TaskList ответ со страницей задач
+type TaskList struct {
+	Items  []Task `json:"items"`
+	Total  int    `json:"total"`
+	Limit  int    `json:"limit"`
+	Offset int    `json:"offset"`
+
+	NextCursor string `json:"next_cursor,omitempty"`
+	PrevCursor string `json:"prev_cursor,omitempty"`
+}
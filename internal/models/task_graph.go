@@ -0,0 +1,107 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/TrueSmartcomm/backend/api"
+	"github.com/google/uuid"
+)
+
+// Направления обхода графа связей (параметр direction спецификации)
+const (
+	GraphDown = string(api.GetTaskGraphParamsDirectionDown) // по направлению связей
+	GraphUp   = string(api.GetTaskGraphParamsDirectionUp)   // против направления связей
+	GraphBoth = string(api.GetTaskGraphParamsDirectionBoth)
+)
+
+// TaskGraphQuery параметры обхода графа связей
+type TaskGraphQuery struct {
+	Root      uuid.UUID
+	Direction string
+	Depth     int
+	Relation  string // пусто — связи всех типов
+}
+
+// TaskGraphNode задача в графе связей
+type TaskGraphNode struct {
+	ID          uuid.UUID `json:"id"`
+	Title       string    `json:"title"`
+	Status      string    `json:"status"`
+	KanbanSpace string    `json:"kanban_space"`
+	Priority    string    `json:"priority"`
+	Depth       int       `json:"depth"` // расстояние от корневой задачи
+}
+
+// TaskGraphEdge связь from → to
+type TaskGraphEdge struct {
+	From     uuid.UUID `json:"from"`
+	To       uuid.UUID `json:"to"`
+	Relation string    `json:"relation"`
+}
+
+// TaskGraph транзитивные связи задачи
+type TaskGraph struct {
+	Root      uuid.UUID       `json:"root"`
+	Nodes     []TaskGraphNode `json:"nodes"`
+	Edges     []TaskGraphEdge `json:"edges"`
+	Truncated bool            `json:"truncated"` // есть задачи глубже Depth
+}
+
+// DOT представление графа для Graphviz. Связи с задачами вне Nodes не выводятся.
+func (g *TaskGraph) DOT() string {
+	known := make(map[uuid.UUID]bool, len(g.Nodes))
+	var b strings.Builder
+	b.WriteString("digraph tasks {\n  rankdir=LR;\n  node [shape=box];\n")
+	for _, n := range g.Nodes {
+		known[n.ID] = true
+		attrs := ""
+		if n.ID == g.Root {
+			attrs = ", style=bold"
+		}
+		fmt.Fprintf(&b, "  %q [label=%s%s];\n", n.ID.String(), dotString(n.Title+"\n"+n.Status), attrs)
+	}
+	for _, e := range g.Edges {
+		if !known[e.From] || !known[e.To] {
+			continue
+		}
+		fmt.Fprintf(&b, "  %q -> %q [label=%s];\n", e.From.String(), e.To.String(), dotString(e.Relation))
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// Mermaid представление графа в виде блока flowchart. Связи с задачами вне Nodes не выводятся.
+func (g *TaskGraph) Mermaid() string {
+	// Mermaid не принимает UUID как идентификаторы узлов, поэтому узлы нумеруются
+	ids := make(map[uuid.UUID]string, len(g.Nodes))
+	var b strings.Builder
+	b.WriteString("flowchart LR\n")
+	for i, n := range g.Nodes {
+		ids[n.ID] = fmt.Sprintf("t%d", i)
+		fmt.Fprintf(&b, "  %s[\"%s<br/>%s\"]\n", ids[n.ID], mermaidString(n.Title), n.Status)
+	}
+	for _, e := range g.Edges {
+		from, okFrom := ids[e.From]
+		to, okTo := ids[e.To]
+		if !okFrom || !okTo {
+			continue
+		}
+		fmt.Fprintf(&b, "  %s -->|%s| %s\n", from, e.Relation, to)
+	}
+	if id, ok := ids[g.Root]; ok {
+		fmt.Fprintf(&b, "  style %s stroke-width:3px\n", id)
+	}
+	return b.String()
+}
+
+// dotString строка в кавычках по правилам DOT
+func dotString(s string) string {
+	s = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s)
+	return `"` + s + `"`
+}
+
+// mermaidString текст метки узла Mermaid без символов, ломающих разметку
+func mermaidString(s string) string {
+	return strings.NewReplacer(`"`, "#quot;", "\n", " ", "<", "#lt;", ">", "#gt;").Replace(s)
+}
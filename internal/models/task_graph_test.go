@@ -0,0 +1,85 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestTaskGraphRender(t *testing.T) {
+	a := uuid.MustParse("00000000-0000-0000-0000-00000000000a")
+	b := uuid.MustParse("00000000-0000-0000-0000-00000000000b")
+	hidden := uuid.MustParse("00000000-0000-0000-0000-0000000000ff")
+
+	tests := []struct {
+		name    string
+		graph   TaskGraph
+		dot     string
+		mermaid string
+	}{
+		{
+			name:    "empty graph",
+			graph:   TaskGraph{},
+			dot:     "digraph tasks {\n  rankdir=LR;\n  node [shape=box];\n}\n",
+			mermaid: "flowchart LR\n",
+		},
+		{
+			name: "root and edge",
+			graph: TaskGraph{
+				Root:  a,
+				Nodes: []TaskGraphNode{{ID: a, Title: "Design", Status: "todo"}, {ID: b, Title: "Build", Status: "in_progress"}},
+				Edges: []TaskGraphEdge{{From: a, To: b, Relation: RelationBlocks}},
+			},
+			dot: "digraph tasks {\n  rankdir=LR;\n  node [shape=box];\n" +
+				`  "00000000-0000-0000-0000-00000000000a" [label="Design\ntodo", style=bold];` + "\n" +
+				`  "00000000-0000-0000-0000-00000000000b" [label="Build\nin_progress"];` + "\n" +
+				`  "00000000-0000-0000-0000-00000000000a" -> "00000000-0000-0000-0000-00000000000b" [label="blocks"];` + "\n" +
+				"}\n",
+			mermaid: "flowchart LR\n" +
+				`  t0["Design<br/>todo"]` + "\n" +
+				`  t1["Build<br/>in_progress"]` + "\n" +
+				"  t0 -->|blocks| t1\n" +
+				"  style t0 stroke-width:3px\n",
+		},
+		{
+			name: "titles with quotes, brackets and newlines",
+			graph: TaskGraph{
+				Root:  a,
+				Nodes: []TaskGraphNode{{ID: a, Title: "Fix \"login\" [v2] <b>\nnow\\", Status: "todo"}},
+			},
+			dot: "digraph tasks {\n  rankdir=LR;\n  node [shape=box];\n" +
+				`  "00000000-0000-0000-0000-00000000000a" [label="Fix \"login\" [v2] <b>\nnow\\\ntodo", style=bold];` + "\n" +
+				"}\n",
+			mermaid: "flowchart LR\n" +
+				`  t0["Fix #quot;login#quot; [v2] #lt;b#gt; now\<br/>todo"]` + "\n" +
+				"  style t0 stroke-width:3px\n",
+		},
+		{
+			name: "edges to unknown nodes are dropped",
+			graph: TaskGraph{
+				Root:  a,
+				Nodes: []TaskGraphNode{{ID: a, Title: "A", Status: "todo"}},
+				Edges: []TaskGraphEdge{
+					{From: a, To: hidden, Relation: RelationBlocks},
+					{From: hidden, To: a, Relation: RelationSubtaskOf},
+				},
+			},
+			dot: "digraph tasks {\n  rankdir=LR;\n  node [shape=box];\n" +
+				`  "00000000-0000-0000-0000-00000000000a" [label="A\ntodo", style=bold];` + "\n" +
+				"}\n",
+			mermaid: "flowchart LR\n" +
+				`  t0["A<br/>todo"]` + "\n" +
+				"  style t0 stroke-width:3px\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.graph.DOT(); got != tt.dot {
+				t.Errorf("DOT() =\n%s\nwant\n%s", got, tt.dot)
+			}
+			if got := tt.graph.Mermaid(); got != tt.mermaid {
+				t.Errorf("Mermaid() =\n%s\nwant\n%s", got, tt.mermaid)
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package models
+
+import (
+	"reflect"
+	"slices"
+	"time"
+
+	"github.com/TrueSmartcomm/backend/api"
+	"github.com/google/uuid"
+)
+
+// События в истории задачи
+const (
+	HistoryCreated           = string(api.TaskHistoryActionCreated)
+	HistoryUpdated           = string(api.TaskHistoryActionUpdated)
+	HistoryMoved             = string(api.TaskHistoryActionMoved)
+	HistoryDeleted           = string(api.TaskHistoryActionDeleted)
+	HistoryRestored          = string(api.TaskHistoryActionRestored)
+	HistoryPurged            = string(api.TaskHistoryActionPurged)
+	HistoryArchived          = string(api.TaskHistoryActionArchived)
+	HistoryUnarchived        = string(api.TaskHistoryActionUnarchived)
+	HistoryDependencyAdded   = string(api.TaskHistoryActionDependencyAdded)
+	HistoryDependencyRemoved = string(api.TaskHistoryActionDependencyRemoved)
+)
+
+// Ограничения страницы истории
+const (
+	DefaultHistoryLimit = 50
+	MaxHistoryLimit     = 200
+)
+
+// FieldChange изменение одного поля задачи; nil — значения не было
+type FieldChange struct {
+	Field string `json:"field"`
+	Old   any    `json:"old"`
+	New   any    `json:"new"`
+}
+
+// TaskHistoryEntry событие в истории задачи
+type TaskHistoryEntry struct {
+	ID        int64         `db:"id" json:"id"`
+	TaskID    uuid.UUID     `db:"task_id" json:"task_id"`
+	Action    string        `db:"action" json:"action"`
+	ActorID   *int          `db:"actor_id" json:"actor_id"` // nil — автор неизвестен
+	Changes   []FieldChange `db:"changes" json:"changes"`
+	CreatedAt time.Time     `db:"created_at" json:"created_at"`
+}
+
+// TaskHistoryPage страница истории задачи, новые события первыми
+type TaskHistoryPage struct {
+	Items  []TaskHistoryEntry `json:"items"`
+	Total  int                `json:"total"`
+	Limit  int                `json:"limit"`
+	Offset int                `json:"offset"`
+}
+
+// historyFields поля задачи, изменения которых попадают в историю, в порядке вывода
+var historyFields = []string{"title", "description", "status", "board_id", "column_id", "kanban_space", "rank",
+	"owner_id", "assignees", "priority", "due_date", "estimate_hours"}
+
+// historyValue значение поля задачи для истории; пустые необязательные поля — nil
+func (t *Task) historyValue(field string) any {
+	switch field {
+	case "title":
+		return t.Title
+	case "description":
+		return t.Description
+	case "status":
+		return t.Status
+	case "board_id":
+		return t.BoardID
+	case "column_id":
+		return t.ColumnID
+	case "kanban_space":
+		return t.KanbanSpace
+	case "rank":
+		return t.Rank
+	case "owner_id":
+		if t.OwnerID != nil {
+			return *t.OwnerID
+		}
+	case "assignees":
+		if len(t.AssigneeIDs) > 0 {
+			return slices.Clone(t.AssigneeIDs)
+		}
+	case "priority":
+		return t.Priority
+	case "due_date":
+		if t.DueDate != nil {
+			return t.DueDate.UTC().Format(time.RFC3339Nano)
+		}
+	case "estimate_hours":
+		if t.EstimateHours != nil {
+			return *t.EstimateHours
+		}
+	}
+	return nil
+}
+
+// DiffTasks изменённые поля задачи. before == nil — задача создана (все поля новые),
+// after == nil — удалена.
+func DiffTasks(before, after *Task) []FieldChange {
+	changes := []FieldChange{}
+	for _, field := range historyFields {
+		var old, cur any
+		if before != nil {
+			old = before.historyValue(field)
+		}
+		if after != nil {
+			cur = after.historyValue(field)
+		}
+		// списки (assignees) несравнимы через ==
+		if !reflect.DeepEqual(old, cur) {
+			changes = append(changes, FieldChange{Field: field, Old: old, New: cur})
+		}
+	}
+	return changes
+}
+
+// RelationFields поля Task, в которых связь relation видна со стороны task_id (from)
+// и dependent_task_id (to); ими связь подписывается в истории обеих задач
+func RelationFields(relation string) (from, to string) {
+	switch relation {
+	case RelationBlocks:
+		return "blocks", "blocked_by"
+	case RelationSubtaskOf:
+		return "sub_tasks", "parent_tasks"
+	case RelationDuplicates:
+		return "duplicated_by", "duplicates"
+	}
+	return "relates_to", "relates_to"
+}
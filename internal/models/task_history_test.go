@@ -0,0 +1,94 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestDiffTasks(t *testing.T) {
+	boardID, columnID := uuid.New(), uuid.New()
+	owner := 7
+	due := time.Date(2025, 3, 1, 12, 0, 0, 0, time.FixedZone("MSK", 3*3600))
+	estimate := 2.5
+	base := Task{
+		Title: "title", Status: "todo", BoardID: boardID, ColumnID: columnID, KanbanSpace: "todo",
+		Rank: "V", OwnerID: &owner, Priority: PriorityMedium,
+	}
+
+	tests := []struct {
+		name   string
+		before *Task
+		after  func(t Task) *Task
+		want   []FieldChange
+	}{
+		{
+			name:   "no changes",
+			before: &base,
+			after:  func(t Task) *Task { return &t },
+			want:   []FieldChange{},
+		},
+		{
+			name:   "changed fields in output order",
+			before: &base,
+			after: func(t Task) *Task {
+				t.Priority, t.Title = PriorityHigh, "new"
+				return &t
+			},
+			want: []FieldChange{
+				{Field: "title", Old: "title", New: "new"},
+				{Field: "priority", Old: PriorityMedium, New: PriorityHigh},
+			},
+		},
+		{
+			name:   "optional fields set",
+			before: &base,
+			after: func(t Task) *Task {
+				t.AssigneeIDs, t.DueDate, t.EstimateHours = []int{1, 2}, &due, &estimate
+				return &t
+			},
+			want: []FieldChange{
+				{Field: "assignees", Old: nil, New: []int{1, 2}},
+				{Field: "due_date", Old: nil, New: "2025-03-01T09:00:00Z"},
+				{Field: "estimate_hours", Old: nil, New: 2.5},
+			},
+		},
+		{
+			name:   "equal assignees in another slice",
+			before: &Task{AssigneeIDs: []int{1, 2}},
+			after:  func(Task) *Task { return &Task{AssigneeIDs: []int{1, 2}} },
+			want:   []FieldChange{},
+		},
+		{
+			name:   "created",
+			before: nil,
+			after: func(Task) *Task {
+				return &Task{Title: "t", Status: "todo", BoardID: boardID, ColumnID: columnID, Priority: PriorityLow}
+			},
+			want: []FieldChange{
+				{Field: "title", New: "t"},
+				{Field: "description", New: ""},
+				{Field: "status", New: "todo"},
+				{Field: "board_id", New: boardID},
+				{Field: "column_id", New: columnID},
+				{Field: "kanban_space", New: ""},
+				{Field: "rank", New: ""},
+				{Field: "priority", New: PriorityLow},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var before Task
+			if tt.before != nil {
+				before = *tt.before
+			}
+			got := DiffTasks(tt.before, tt.after(before))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DiffTasks() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,13 @@
+package models
+
+import "github.com/google/uuid"
+
+// TaskMove перемещение карточки (POST /tasks/{id}/move). Колонка задаётся ColumnID
+// или ключом Space; без них — колонкой соседней карточки AfterID или BeforeID.
+type TaskMove struct {
+	ColumnID *uuid.UUID
+	Space    string
+	Status   string     // пусто — статус колонки или текущий статус задачи
+	AfterID  *uuid.UUID // карточка встаёт сразу после этой
+	BeforeID *uuid.UUID // карточка встаёт сразу перед этой
+}
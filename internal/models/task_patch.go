@@ -0,0 +1,130 @@
+package models
+
+import (
+	"slices"
+	"time"
+
+	"github.com/TrueSmartcomm/backend/api"
+	"github.com/google/uuid"
+)
+
+// TaskPatch тело PATCH /tasks/{id} (RFC 7396 JSON Merge Patch). api.Nullable различает
+// непереданное поле, null и значение.
+type TaskPatch struct {
+	Title         api.Nullable[string]    `json:"title"`
+	Description   api.Nullable[string]    `json:"description"`
+	Status        api.Nullable[string]    `json:"status"`
+	BoardID       *uuid.UUID              `json:"board_id"`
+	ColumnID      *uuid.UUID              `json:"column_id"`
+	KanbanSpace   api.Nullable[string]    `json:"kanban_space"`
+	AssigneeIDs   api.Nullable[[]int]     `json:"assignee_ids"`
+	Priority      api.Nullable[string]    `json:"priority"`
+	DueDate       api.Nullable[time.Time] `json:"due_date"`
+	EstimateHours api.Nullable[float64]   `json:"estimate_hours"`
+}
+
+// Apply накладывает патч на задачу и возвращает имена изменённых столбцов.
+// null очищает поле; для обязательных полей это приведёт к ошибке в Validate,
+// а priority при null возвращается к значению по умолчанию.
+//
+// При смене колонки или доски ColumnID и KanbanSpace, которые нужно определить
+// заново, обнуляются: репозиторий находит колонку по ключу или берёт первую колонку доски.
+func (p *TaskPatch) Apply(t *Task) []string {
+	var changed []string
+
+	setString := func(f api.Nullable[string], dst *string, column string) {
+		if !f.Set {
+			return
+		}
+		v := f.Value
+		if f.Null {
+			v = ""
+		}
+		if *dst != v {
+			*dst = v
+			changed = append(changed, column)
+		}
+	}
+
+	setString(p.Title, &t.Title, "title")
+	setString(p.Description, &t.Description, "description")
+	setString(p.Status, &t.Status, "status")
+
+	moved := false
+	switch {
+	case p.ColumnID != nil && *p.ColumnID != t.ColumnID:
+		t.ColumnID, t.KanbanSpace = *p.ColumnID, ""
+		moved = true
+	case p.ColumnID == nil && p.KanbanSpace.Set && p.KanbanSpace.Value != t.KanbanSpace:
+		// null возвращает задачу в первую колонку доски
+		t.ColumnID, t.KanbanSpace = uuid.Nil, p.KanbanSpace.Value
+		moved = true
+	}
+	if p.BoardID != nil && *p.BoardID != t.BoardID {
+		t.BoardID = *p.BoardID
+		changed = append(changed, "board_id")
+		// колонки принадлежат доске: без явной колонки она ищется на новой доске заново
+		if p.ColumnID == nil {
+			t.ColumnID = uuid.Nil
+			if !p.KanbanSpace.Set {
+				t.KanbanSpace = ""
+			}
+		}
+		moved = true
+	}
+	if moved {
+		changed = append(changed, "column_id", "kanban_space")
+	}
+
+	if p.Priority.Set {
+		v := p.Priority.Value
+		if p.Priority.Null {
+			v = PriorityMedium
+		}
+		if t.Priority != v {
+			t.Priority = v
+			changed = append(changed, "priority")
+		}
+	}
+
+	// исполнители хранятся не в столбце задачи: "assignees" репозиторий записывает в task_assignees
+	if p.AssigneeIDs.Set {
+		before := t.AssigneeIDs
+		t.SetAssignees(p.AssigneeIDs.Value)
+		if !slices.Equal(before, t.AssigneeIDs) {
+			changed = append(changed, "assignees")
+		}
+	}
+
+	if p.DueDate.Set {
+		var v *time.Time
+		if !p.DueDate.Null {
+			d := p.DueDate.Value.UTC()
+			v = &d
+		}
+		if !equalPtr(t.DueDate, v, time.Time.Equal) {
+			t.DueDate = v
+			changed = append(changed, "due_date")
+		}
+	}
+
+	if p.EstimateHours.Set {
+		var v *float64
+		if !p.EstimateHours.Null {
+			v = &p.EstimateHours.Value
+		}
+		if !equalPtr(t.EstimateHours, v, func(a, b float64) bool { return a == b }) {
+			t.EstimateHours = v
+			changed = append(changed, "estimate_hours")
+		}
+	}
+
+	return changed
+}
+
+func equalPtr[T any](a, b *T, eq func(T, T) bool) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return eq(*a, *b)
+}
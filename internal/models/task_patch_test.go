@@ -0,0 +1,143 @@
+package models
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestTaskPatchApply(t *testing.T) {
+	boardID, columnID := uuid.New(), uuid.New()
+	otherBoard, otherColumn := uuid.New(), uuid.New()
+	due := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	estimate := 4.0
+	base := func() Task {
+		return Task{
+			Title: "title", Description: "description", Status: "todo",
+			BoardID: boardID, ColumnID: columnID, KanbanSpace: "todo",
+			AssigneeIDs: []int{1, 2}, Priority: PriorityHigh,
+			DueDate: &due, EstimateHours: &estimate,
+		}
+	}
+
+	tests := []struct {
+		name    string
+		body    string
+		changed []string
+		check   func(t *testing.T, task Task)
+	}{
+		{name: "empty patch", body: `{}`},
+		{name: "same values", body: `{"title": "title", "priority": "high", "assignee_ids": [2, 1, 2]}`},
+		{
+			name: "value", body: `{"title": "new", "description": ""}`,
+			changed: []string{"title", "description"},
+			check: func(t *testing.T, task Task) {
+				if task.Title != "new" || task.Description != "" {
+					t.Errorf("title %q, description %q", task.Title, task.Description)
+				}
+			},
+		},
+		{
+			name: "null clears fields", body: `{"description": null, "due_date": null, "estimate_hours": null}`,
+			changed: []string{"description", "due_date", "estimate_hours"},
+			check: func(t *testing.T, task Task) {
+				if task.Description != "" || task.DueDate != nil || task.EstimateHours != nil {
+					t.Errorf("fields are not cleared: %+v", task)
+				}
+			},
+		},
+		{
+			name: "null priority resets to default", body: `{"priority": null}`,
+			changed: []string{"priority"},
+			check: func(t *testing.T, task Task) {
+				if task.Priority != PriorityMedium {
+					t.Errorf("priority %q", task.Priority)
+				}
+			},
+		},
+		{
+			name: "due date in another zone", body: `{"due_date": "2025-03-01T15:00:00+03:00"}`,
+		},
+		{
+			name: "assignees are sorted", body: `{"assignee_ids": [3, 1]}`,
+			changed: []string{"assignees"},
+			check: func(t *testing.T, task Task) {
+				if !slices.Equal(task.AssigneeIDs, []int{1, 3}) {
+					t.Errorf("assignees %v", task.AssigneeIDs)
+				}
+			},
+		},
+		{
+			name: "column by key", body: `{"kanban_space": "done"}`,
+			changed: []string{"column_id", "kanban_space"},
+			check: func(t *testing.T, task Task) {
+				if task.ColumnID != uuid.Nil || task.KanbanSpace != "done" {
+					t.Errorf("column %v, space %q", task.ColumnID, task.KanbanSpace)
+				}
+			},
+		},
+		{
+			name: "null column key moves to first column", body: `{"kanban_space": null}`,
+			changed: []string{"column_id", "kanban_space"},
+			check: func(t *testing.T, task Task) {
+				if task.ColumnID != uuid.Nil || task.KanbanSpace != "" {
+					t.Errorf("column %v, space %q", task.ColumnID, task.KanbanSpace)
+				}
+			},
+		},
+		{
+			name: "column id wins over key", body: `{"column_id": "` + otherColumn.String() + `", "kanban_space": "done"}`,
+			changed: []string{"column_id", "kanban_space"},
+			check: func(t *testing.T, task Task) {
+				if task.ColumnID != otherColumn || task.KanbanSpace != "" {
+					t.Errorf("column %v, space %q", task.ColumnID, task.KanbanSpace)
+				}
+			},
+		},
+		{
+			name: "board resets column", body: `{"board_id": "` + otherBoard.String() + `"}`,
+			changed: []string{"board_id", "column_id", "kanban_space"},
+			check: func(t *testing.T, task Task) {
+				if task.BoardID != otherBoard || task.ColumnID != uuid.Nil || task.KanbanSpace != "" {
+					t.Errorf("board %v, column %v, space %q", task.BoardID, task.ColumnID, task.KanbanSpace)
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var patch TaskPatch
+			if err := json.Unmarshal([]byte(tt.body), &patch); err != nil {
+				t.Fatalf("unmarshal %s: %v", tt.body, err)
+			}
+			task := base()
+			changed := patch.Apply(&task)
+			if !slices.Equal(changed, tt.changed) {
+				t.Errorf("changed = %v, want %v", changed, tt.changed)
+			}
+			if tt.check != nil {
+				tt.check(t, task)
+			}
+		})
+	}
+}
+
+// Отсутствующее поле, null и значение различаются
+func TestTaskPatchNullable(t *testing.T) {
+	var patch TaskPatch
+	if err := json.Unmarshal([]byte(`{"title": "x", "description": null}`), &patch); err != nil {
+		t.Fatal(err)
+	}
+	if !patch.Title.Set || patch.Title.Null || patch.Title.Value != "x" {
+		t.Errorf("title = %+v", patch.Title)
+	}
+	if !patch.Description.Set || !patch.Description.Null {
+		t.Errorf("description = %+v", patch.Description)
+	}
+	if patch.Status.Set {
+		t.Errorf("status = %+v, want unset", patch.Status)
+	}
+}
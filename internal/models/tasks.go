@@ -1,28 +1,59 @@
 package models
 
 import (
+	"slices"
 	"time"
 
+	"github.com/TrueSmartcomm/backend/api"
+	"github.com/TrueSmartcomm/backend/internal/apperror"
 	"github.com/google/uuid"
 )
 
 type Task struct {
-	ID          uuid.UUID   `db:"id" json:"id"`
-	Title       string      `db:"title" json:"title" binding:"required"`
-	Description string      `db:"description" json:"description"`
-	Status      string      `db:"status" json:"status" binding:"required"`
-	KanbanSpace string      `db:"kanban_space" json:"kanban_space" binding:"required"` // "todo", "in_progress", "done", "review"
-	Owner       string      `db:"owner" json:"owner" binding:"required"`
-	AssignedTo  *string     `db:"assigned_to" json:"assigned_to,omitempty"` // Может быть nil
-	Priority    string      `db:"priority" json:"priority"`                 // "low", "medium", "high", "urgent"
-	DueDate     *time.Time  `db:"due_date" json:"due_date,omitempty"`
-	CreatedAt   time.Time   `db:"created_at" json:"created_at"`
-	UpdatedAt   time.Time   `db:"updated_at" json:"updated_at"`
-	SubTasks    []uuid.UUID `db:"-" json:"sub_tasks,omitempty"`
-	ParentTasks []uuid.UUID `db:"-" json:"parent_tasks,omitempty"`
+	ID            uuid.UUID     `db:"id" json:"id"`
+	Title         string        `db:"title" json:"title" binding:"required"`
+	Description   string        `db:"description" json:"description"`
+	Status        string        `db:"status" json:"status" binding:"required"`
+	BoardID       uuid.UUID     `db:"board_id" json:"board_id"`
+	ColumnID      uuid.UUID     `db:"column_id" json:"column_id"`
+	KanbanSpace   string        `db:"kanban_space" json:"kanban_space"` // key колонки ColumnID
+	Rank          string        `db:"rank" json:"rank"`                 // порядок карточки в колонке (см. пакет rank)
+	OwnerID       *int          `db:"owner_id" json:"-"`                // nil — владелец удалён или не найден при переносе
+	AssigneeIDs   []int         `db:"-" json:"-"`                       // id исполнителей по возрастанию
+	Owner         *UserSummary  `db:"-" json:"owner"`                   // владелец (создатель) задачи
+	Assignees     []UserSummary `db:"-" json:"assignees"`               // исполнители в порядке id
+	Priority      string        `db:"priority" json:"priority"`         // "low", "medium", "high", "urgent"
+	DueDate       *time.Time    `db:"due_date" json:"due_date,omitempty"`
+	EstimateHours *float64      `db:"estimate_hours" json:"estimate_hours,omitempty"` // оценка трудоёмкости в часах
+	CreatedAt     time.Time     `db:"created_at" json:"created_at"`
+	UpdatedAt     time.Time     `db:"updated_at" json:"updated_at"`
+	Version       int64         `db:"version" json:"-"`                           // отдаётся в заголовке ETag
+	DeletedAt     *time.Time    `db:"deleted_at" json:"deleted_at,omitempty"`     // задан только у задач в корзине
+	CompletedAt   *time.Time    `db:"completed_at" json:"completed_at,omitempty"` // переход в завершающий статус доски
+	ArchivedAt    *time.Time    `db:"archived_at" json:"archived_at,omitempty"`   // задан у архивных задач
+	Warnings      []string      `db:"-" json:"-"`                                 // превышенные мягкие WIP-лимиты; отдаются в заголовке Warning
+	// Связи заполняются в GET /tasks/{id}/dependencies, по одному полю на направление каждого типа
+	SubTasks     []uuid.UUID `db:"-" json:"sub_tasks,omitempty"`     // subtask_of: подзадачи
+	ParentTasks  []uuid.UUID `db:"-" json:"parent_tasks,omitempty"`  // subtask_of: родительские задачи
+	Blocks       []uuid.UUID `db:"-" json:"blocks,omitempty"`        // blocks: задачи, которые блокирует эта
+	BlockedBy    []uuid.UUID `db:"-" json:"blocked_by,omitempty"`    // blocks: задачи, которые блокируют эту
+	RelatesTo    []uuid.UUID `db:"-" json:"relates_to,omitempty"`    // relates_to в любую сторону
+	DuplicatedBy []uuid.UUID `db:"-" json:"duplicated_by,omitempty"` // duplicates: дубликаты этой задачи
+	Duplicates   []uuid.UUID `db:"-" json:"duplicates,omitempty"`    // duplicates: задачи, которые дублирует эта
 }
 
-// Допустимые значения для статусов
+// UserSummary пользователь в ответах с задачами
+type UserSummary struct {
+	ID    int    `json:"id"`
+	Login string `json:"login"`
+	Email string `json:"email"`
+}
+
+// Значения перечислений берутся из типов, сгенерированных по api/openapiv1.yaml,
+// поэтому расхождение со спецификацией ломает сборку.
+
+// Статусы доски по умолчанию (прежний фиксированный набор); StatusDone — завершающий.
+// На других досках status — ключ любого статуса доски (см. Workflow).
 const (
 	StatusTodo       = "todo"
 	StatusInProgress = "in_progress"
@@ -30,7 +61,8 @@ const (
 	StatusDone       = "done"
 )
 
-// Допустимые значения для Kanban-пространств
+// Колонки доски по умолчанию (прежний фиксированный набор Kanban-пространств).
+// На других досках kanban_space — ключ любой колонки доски.
 const (
 	SpaceBacklog    = "backlog"
 	SpaceTodo       = "todo"
@@ -41,12 +73,19 @@ const (
 
 // Допустимые значения для приоритетов
 const (
-	PriorityLow    = "low"
-	PriorityMedium = "medium"
-	PriorityHigh   = "high"
-	PriorityUrgent = "urgent"
+	PriorityLow    = string(api.TaskPriorityLow)
+	PriorityMedium = string(api.TaskPriorityMedium)
+	PriorityHigh   = string(api.TaskPriorityHigh)
+	PriorityUrgent = string(api.TaskPriorityUrgent)
 )
 
+// SetAssignees задаёт исполнителей задачи: id упорядочиваются, повторы отбрасываются
+func (t *Task) SetAssignees(ids []int) {
+	ids = slices.Clone(ids)
+	slices.Sort(ids)
+	t.AssigneeIDs = slices.Compact(ids)
+}
+
 // Validate проверяет валидность задачи
 func (t *Task) Validate() error {
 	if t.Title == "" {
@@ -58,24 +97,8 @@ func (t *Task) Validate() error {
 	if t.KanbanSpace == "" {
 		return &ValidationError{"kanban_space", "kanban_space is required"}
 	}
-	if t.Owner == "" {
-		return &ValidationError{"owner", "owner is required"}
-	}
-
-	// Валидация статуса
-	switch t.Status {
-	case StatusTodo, StatusInProgress, StatusReview, StatusDone:
-		// OK
-	default:
-		return &ValidationError{"status", "invalid status"}
-	}
-
-	// Валидация Kanban-пространства
-	switch t.KanbanSpace {
-	case SpaceBacklog, SpaceTodo, SpaceInProgress, SpaceReview, SpaceDone:
-		// OK
-	default:
-		return &ValidationError{"kanban_space", "invalid kanban space"}
+	if t.EstimateHours != nil && *t.EstimateHours < 0 {
+		return &ValidationError{"estimate_hours", "estimate_hours must not be negative"}
 	}
 
 	// Валидация приоритета
@@ -98,3 +121,22 @@ type ValidationError struct {
 func (e *ValidationError) Error() string {
 	return e.Field + ": " + e.Message
 }
+
+// AppError 422 с указанием поля, не прошедшего проверку
+func (e *ValidationError) AppError() *apperror.Error {
+	return apperror.Validation(e.Error(), apperror.FieldError{Name: e.Field, Reason: e.Message})
+}
+
+// VersionConflictError версия задачи не совпала с If-Match: задачу успели изменить
+type VersionConflictError struct {
+	Current *Task // актуальное состояние задачи
+}
+
+func (e *VersionConflictError) Error() string {
+	return "task has been modified"
+}
+
+// AppError 412 Precondition Failed
+func (e *VersionConflictError) AppError() *apperror.Error {
+	return apperror.New(apperror.KindPreconditionFailed, apperror.CodeVersionMismatch, e.Error())
+}
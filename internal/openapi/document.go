@@ -0,0 +1,210 @@
+// Package openapi загружает OpenAPI-документ сервиса и предоставляет его модель.
+package openapi
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Document корень OpenAPI 3.0 документа (только используемые сервисом части)
+type Document struct {
+	OpenAPI    string               `yaml:"openapi"`
+	Paths      map[string]*PathItem `yaml:"paths"`
+	Components Components           `yaml:"components"`
+}
+
+// Components переиспользуемые части документа
+type Components struct {
+	Schemas map[string]*Schema `yaml:"schemas"`
+}
+
+// PathItem операции одного пути
+type PathItem struct {
+	Get    *Operation `yaml:"get"`
+	Post   *Operation `yaml:"post"`
+	Put    *Operation `yaml:"put"`
+	Patch  *Operation `yaml:"patch"`
+	Delete *Operation `yaml:"delete"`
+}
+
+// Methods порядок HTTP-методов при обходе операций
+var Methods = []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete}
+
+// Operation возвращает операцию по HTTP-методу или nil
+func (p *PathItem) Operation(method string) *Operation {
+	switch method {
+	case http.MethodGet:
+		return p.Get
+	case http.MethodPost:
+		return p.Post
+	case http.MethodPut:
+		return p.Put
+	case http.MethodPatch:
+		return p.Patch
+	case http.MethodDelete:
+		return p.Delete
+	}
+	return nil
+}
+
+// Operations возвращает операции пути по HTTP-методам
+func (p *PathItem) Operations() map[string]*Operation {
+	ops := map[string]*Operation{}
+	for _, method := range Methods {
+		if op := p.Operation(method); op != nil {
+			ops[method] = op
+		}
+	}
+	return ops
+}
+
+// Operation описание одной операции
+type Operation struct {
+	OperationID string               `yaml:"operationId"`
+	Summary     string               `yaml:"summary"`
+	Tags        []string             `yaml:"tags"`
+	Deprecated  bool                 `yaml:"deprecated"`
+	Parameters  []*Parameter         `yaml:"parameters"`
+	RequestBody *RequestBody         `yaml:"requestBody"`
+	Responses   map[string]*Response `yaml:"responses"`
+}
+
+// Parameter параметр пути или query-строки
+type Parameter struct {
+	Name        string  `yaml:"name"`
+	In          string  `yaml:"in"`
+	Description string  `yaml:"description"`
+	Required    bool    `yaml:"required"`
+	Schema      *Schema `yaml:"schema"`
+}
+
+// RequestBody тело запроса
+type RequestBody struct {
+	Required bool                  `yaml:"required"`
+	Content  map[string]*MediaType `yaml:"content"`
+}
+
+// Response описание ответа
+type Response struct {
+	Description string                `yaml:"description"`
+	Content     map[string]*MediaType `yaml:"content"`
+}
+
+// MediaType схема содержимого для конкретного Content-Type
+type MediaType struct {
+	Schema *Schema `yaml:"schema"`
+}
+
+// JSONSchema возвращает схему application/json или nil
+func JSONSchema(content map[string]*MediaType) *Schema {
+	if mt, ok := content["application/json"]; ok {
+		return mt.Schema
+	}
+	return nil
+}
+
+// Schema подмножество JSON Schema, используемое в OpenAPI 3.0
+type Schema struct {
+	Ref         string             `yaml:"$ref"`
+	AllOf       []*Schema          `yaml:"allOf"`
+	Type        string             `yaml:"type"`
+	Format      string             `yaml:"format"`
+	Description string             `yaml:"description"`
+	Enum        []string           `yaml:"enum"`
+	Nullable    bool               `yaml:"nullable"`
+	ReadOnly    bool               `yaml:"readOnly"`
+	Required    []string           `yaml:"required"`
+	Properties  map[string]*Schema `yaml:"properties"`
+	Items       *Schema            `yaml:"items"`
+	MinLength   *int               `yaml:"minLength"`
+	MaxLength   *int               `yaml:"maxLength"`
+	Minimum     *float64           `yaml:"minimum"`
+	Maximum     *float64           `yaml:"maximum"`
+	Default     interface{}        `yaml:"default"`
+	Example     interface{}        `yaml:"example"`
+
+	// AdditionalProperties false запрещает неизвестные поля объекта
+	AdditionalProperties *bool `yaml:"additionalProperties"`
+	// MergePatch помечает схему тела JSON Merge Patch: необязательные nullable-поля
+	// различают «не передано» и «передан null»
+	MergePatch bool `yaml:"x-merge-patch"`
+}
+
+// RefName возвращает имя схемы из ссылки вида #/components/schemas/Name
+func (s *Schema) RefName() string {
+	return strings.TrimPrefix(s.Ref, "#/components/schemas/")
+}
+
+// IsRequired сообщает, обязательно ли свойство объекта
+func (s *Schema) IsRequired(name string) bool {
+	for _, r := range s.Required {
+		if r == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Resolve раскрывает $ref и allOf из одного элемента. Флаг nullable
+// со ссылающейся схемы сохраняется.
+func (d *Document) Resolve(s *Schema) *Schema {
+	for s != nil {
+		switch {
+		case s.Ref != "":
+			target := d.Components.Schemas[s.RefName()]
+			if target == nil {
+				return s
+			}
+			if s.Nullable && !target.Nullable {
+				cp := *target
+				cp.Nullable = true
+				target = &cp
+			}
+			s = target
+		case len(s.AllOf) == 1 && s.Type == "" && len(s.Properties) == 0:
+			inner := *s.AllOf[0]
+			inner.Nullable = inner.Nullable || s.Nullable
+			s = &inner
+		default:
+			return s
+		}
+	}
+	return nil
+}
+
+// Load разбирает OpenAPI-документ в формате YAML
+func Load(spec []byte) (*Document, error) {
+	var doc Document
+	if err := yaml.Unmarshal(spec, &doc); err != nil {
+		return nil, fmt.Errorf("parse openapi document: %w", err)
+	}
+	if !strings.HasPrefix(doc.OpenAPI, "3.") {
+		return nil, fmt.Errorf("unsupported openapi version %q", doc.OpenAPI)
+	}
+	for path, item := range doc.Paths {
+		if item == nil {
+			return nil, fmt.Errorf("path %s has no operations", path)
+		}
+	}
+	return &doc, nil
+}
+
+// GinPath переводит шаблон пути OpenAPI (/tasks/{id}) в формат gin (/tasks/:id)
+func GinPath(path string) string {
+	path = strings.ReplaceAll(path, "{", ":")
+	return strings.ReplaceAll(path, "}", "")
+}
+
+// SpecPath переводит путь gin (/tasks/:id) обратно в шаблон OpenAPI (/tasks/{id})
+func SpecPath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, s := range segments {
+		if strings.HasPrefix(s, ":") {
+			segments[i] = "{" + s[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
@@ -0,0 +1,264 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/TrueSmartcomm/backend/internal/apperror"
+	"github.com/gin-gonic/gin"
+)
+
+// CodeResponseMismatch код ошибки, которой заменяется ответ, расходящийся со спецификацией
+const CodeResponseMismatch = "response_spec_mismatch"
+
+// Validator проверяет запросы (и при необходимости ответы) по операциям документа.
+// Маршруты, не описанные в спецификации и не объявленные алиасами операций
+// (устаревшие маршруты с id в query или в теле), пропускаются.
+type Validator struct {
+	doc               *Document
+	ops               map[string]*Operation // "METHOD /gin/path"
+	aliasPrefixes     []string
+	validateResponses bool
+}
+
+// ValidatorOption настройка Validator
+type ValidatorOption func(*Validator)
+
+// WithResponseValidation включает проверку ответов. Ответ, не соответствующий
+// спецификации, заменяется на 500 с описанием расхождения — только для разработки.
+func WithResponseValidation() ValidatorOption {
+	return func(v *Validator) { v.validateResponses = true }
+}
+
+// WithAliasPrefix проверяет маршруты prefix+путь операции так же, как саму операцию:
+// алиасы с лишним префиксом принимают те же запросы, что и основные маршруты.
+func WithAliasPrefix(prefix string) ValidatorOption {
+	return func(v *Validator) { v.aliasPrefixes = append(v.aliasPrefixes, prefix) }
+}
+
+// NewValidator создаёт Validator для документа
+func NewValidator(doc *Document, opts ...ValidatorOption) *Validator {
+	v := &Validator{doc: doc, ops: map[string]*Operation{}}
+	for _, opt := range opts {
+		opt(v)
+	}
+	for path, item := range doc.Paths {
+		for method, op := range item.Operations() {
+			v.ops[method+" "+GinPath(path)] = op
+			for _, prefix := range v.aliasPrefixes {
+				v.ops[method+" "+prefix+GinPath(path)] = op
+			}
+		}
+	}
+	return v
+}
+
+// Middleware возвращает gin middleware. Должен подключаться к группе до регистрации
+// маршрутов: операция определяется по шаблону пути (c.FullPath()). Ошибки передаются
+// через c.Error и отдаются клиенту middleware ошибок.
+func (v *Validator) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		op := v.ops[c.Request.Method+" "+c.FullPath()]
+		if op == nil {
+			c.Next()
+			return
+		}
+
+		if errs := v.validateRequest(c, op); len(errs) > 0 {
+			_ = c.Error(apperror.BadRequest("request does not conform to API spec", errs...))
+			c.Abort()
+			return
+		}
+
+		if !v.validateResponses {
+			c.Next()
+			return
+		}
+
+		rec := &responseRecorder{ResponseWriter: c.Writer, status: http.StatusOK}
+		c.Writer = rec
+		c.Next()
+		c.Writer = rec.ResponseWriter
+
+		// Обработчик завершился ошибкой через c.Error: ответ сформирует middleware ошибок
+		if !rec.touched {
+			return
+		}
+		// Ответы устаревших алиасов (GET /tasks?id=) описаны у операций-преемников
+		if c.Writer.Header().Get("Deprecation") == "" {
+			if errs := v.validateResponse(op, rec.status, rec.Header().Get("Content-Type"), rec.body.Bytes()); len(errs) > 0 {
+				log.Printf("[WARN] %s %s: %v", c.Request.Method, c.FullPath(), errs)
+				c.Writer.Header().Del("Content-Length")
+				err := apperror.New(apperror.KindInternal, CodeResponseMismatch, "response does not conform to API spec")
+				err.Fields = errs
+				_ = c.Error(err)
+				return
+			}
+		}
+		rec.flush()
+	}
+}
+
+func (v *Validator) validateRequest(c *gin.Context, op *Operation) FieldErrors {
+	var errs FieldErrors
+
+	for _, p := range op.Parameters {
+		var raw string
+		var present bool
+		switch p.In {
+		case "path":
+			raw = c.Param(p.Name)
+			present = raw != ""
+		case "query":
+			raw, present = c.GetQuery(p.Name)
+		default:
+			continue
+		}
+		if !present {
+			if p.Required {
+				errs = append(errs, apperror.FieldError{Name: p.Name, In: p.In, Reason: "is required"})
+			}
+			continue
+		}
+
+		schema := v.doc.Resolve(p.Schema)
+		if schema == nil {
+			continue
+		}
+		val, ok := paramValue(schema, raw)
+		if !ok {
+			errs = append(errs, apperror.FieldError{Name: p.Name, In: p.In, Reason: "must be a " + schema.Type})
+			continue
+		}
+		sv := &schemaValidator{doc: v.doc, in: p.In}
+		sv.validate(schema, val, p.Name)
+		errs = append(errs, sv.errs...)
+	}
+
+	if op.RequestBody == nil {
+		return errs
+	}
+	schema := JSONSchema(op.RequestBody.Content)
+	if schema == nil {
+		return errs
+	}
+
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		return append(errs, apperror.FieldError{In: "body", Reason: "cannot read request body"})
+	}
+	// Тело нужно обработчику, поэтому возвращаем его в запрос
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+	if len(bytes.TrimSpace(body)) == 0 {
+		if op.RequestBody.Required {
+			errs = append(errs, apperror.FieldError{In: "body", Reason: "request body is required"})
+		}
+		return errs
+	}
+	if ct := c.ContentType(); ct != "" {
+		if mt, _, _ := mime.ParseMediaType(ct); mt != "application/json" {
+			return append(errs, apperror.FieldError{In: "body", Reason: "content type must be application/json"})
+		}
+	}
+
+	val, err := decodeJSON(body)
+	if err != nil {
+		return append(errs, apperror.FieldError{In: "body", Reason: "malformed JSON: " + err.Error()})
+	}
+	sv := &schemaValidator{doc: v.doc, in: "body"}
+	sv.validate(schema, val, "")
+	return append(errs, sv.errs...)
+}
+
+func (v *Validator) validateResponse(op *Operation, status int, contentType string, body []byte) FieldErrors {
+	resp, ok := op.Responses[strconv.Itoa(status)]
+	if !ok {
+		resp, ok = op.Responses["default"]
+	}
+	if !ok {
+		// Непредвиденные ошибки сервера не описываются в спецификации
+		if status >= http.StatusInternalServerError {
+			return nil
+		}
+		return FieldErrors{{In: "response", Reason: "status " + strconv.Itoa(status) + " is not described in spec"}}
+	}
+
+	// Схемой проверяются только JSON-ответы; для остальных (например, text/plain)
+	// достаточно, что тип содержимого описан в спецификации
+	if mediaType, _, _ := mime.ParseMediaType(contentType); mediaType != "" && mediaType != "application/json" {
+		if _, ok := resp.Content[mediaType]; !ok && len(resp.Content) > 0 {
+			return FieldErrors{{In: "response", Reason: "content type " + mediaType + " is not described in spec"}}
+		}
+		return nil
+	}
+
+	schema := JSONSchema(resp.Content)
+	if schema == nil {
+		return nil
+	}
+	val, err := decodeJSON(body)
+	if err != nil {
+		return FieldErrors{{In: "response", Reason: "malformed JSON: " + err.Error()}}
+	}
+	sv := &schemaValidator{doc: v.doc, in: "response"}
+	sv.validate(schema, val, "")
+	return sv.errs
+}
+
+func decodeJSON(data []byte) (interface{}, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var val interface{}
+	if err := dec.Decode(&val); err != nil {
+		return nil, err
+	}
+	return val, nil
+}
+
+// responseRecorder буферизует ответ, чтобы проверить его до отправки клиенту
+type responseRecorder struct {
+	gin.ResponseWriter
+	status  int
+	written bool
+	touched bool // обработчик выставил статус или записал тело
+	body    bytes.Buffer
+}
+
+func (w *responseRecorder) WriteHeader(code int) {
+	if code > 0 && !w.written {
+		w.status = code
+		w.touched = true
+	}
+}
+
+func (w *responseRecorder) WriteHeaderNow() { w.written, w.touched = true, true }
+
+func (w *responseRecorder) Write(data []byte) (int, error) {
+	w.written, w.touched = true, true
+	return w.body.Write(data)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.written, w.touched = true, true
+	return w.body.WriteString(s)
+}
+
+func (w *responseRecorder) Status() int   { return w.status }
+func (w *responseRecorder) Size() int     { return w.body.Len() }
+func (w *responseRecorder) Written() bool { return w.written }
+
+// flush отправляет буферизованный ответ клиенту
+func (w *responseRecorder) flush() {
+	w.ResponseWriter.WriteHeader(w.status)
+	if w.body.Len() > 0 {
+		_, _ = w.ResponseWriter.Write(w.body.Bytes())
+	} else {
+		w.ResponseWriter.WriteHeaderNow()
+	}
+}
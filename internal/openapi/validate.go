@@ -0,0 +1,203 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/mail"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/TrueSmartcomm/backend/internal/apperror"
+	"github.com/google/uuid"
+)
+
+// FieldErrors список несоответствий схеме; In — path, query, body или response,
+// Name — имя параметра или путь внутри JSON (items[0].id)
+type FieldErrors []apperror.FieldError
+
+func (e FieldErrors) Error() string {
+	parts := make([]string, len(e))
+	for i, fe := range e {
+		if fe.Name != "" {
+			parts[i] = fmt.Sprintf("%s %s: %s", fe.In, fe.Name, fe.Reason)
+		} else {
+			parts[i] = fmt.Sprintf("%s: %s", fe.In, fe.Reason)
+		}
+	}
+	return strings.Join(parts, "; ")
+}
+
+// schemaValidator проверяет значения, полученные из encoding/json (с UseNumber)
+type schemaValidator struct {
+	doc  *Document
+	in   string
+	errs FieldErrors
+}
+
+func (v *schemaValidator) fail(field, format string, args ...interface{}) {
+	v.errs = append(v.errs, apperror.FieldError{Name: field, In: v.in, Reason: fmt.Sprintf(format, args...)})
+}
+
+func (v *schemaValidator) validate(s *Schema, value interface{}, field string) {
+	s = v.doc.Resolve(s)
+	if s == nil {
+		return
+	}
+	if value == nil {
+		if !s.Nullable {
+			v.fail(field, "must not be null")
+		}
+		return
+	}
+	for _, sub := range s.AllOf {
+		v.validate(sub, value, field)
+	}
+
+	switch s.Type {
+	case "object":
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			v.fail(field, "must be an object")
+			return
+		}
+		for _, name := range s.Required {
+			if _, ok := obj[name]; !ok {
+				v.fail(join(field, name), "is required")
+			}
+		}
+		// Ключи обходятся по порядку, чтобы список ошибок был стабильным
+		names := make([]string, 0, len(obj))
+		for name := range obj {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			val := obj[name]
+			prop, ok := s.Properties[name]
+			if !ok {
+				if s.AdditionalProperties != nil && !*s.AdditionalProperties {
+					v.fail(join(field, name), "unknown field")
+				}
+				continue
+			}
+			v.validate(prop, val, join(field, name))
+		}
+	case "array":
+		arr, ok := value.([]interface{})
+		if !ok {
+			v.fail(field, "must be an array")
+			return
+		}
+		for i, item := range arr {
+			v.validate(s.Items, item, fmt.Sprintf("%s[%d]", field, i))
+		}
+	case "string":
+		str, ok := value.(string)
+		if !ok {
+			v.fail(field, "must be a string")
+			return
+		}
+		v.validateString(s, str, field)
+	case "integer", "number":
+		num, ok := value.(json.Number)
+		if !ok {
+			v.fail(field, "must be a %s", s.Type)
+			return
+		}
+		v.validateNumber(s, num, field)
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			v.fail(field, "must be a boolean")
+		}
+	}
+}
+
+func (v *schemaValidator) validateString(s *Schema, str, field string) {
+	if len(s.Enum) > 0 && !contains(s.Enum, str) {
+		v.fail(field, "must be one of [%s]", strings.Join(s.Enum, ", "))
+		return
+	}
+	n := utf8.RuneCountInString(str)
+	if s.MinLength != nil && n < *s.MinLength {
+		v.fail(field, "must be at least %d characters long", *s.MinLength)
+	}
+	if s.MaxLength != nil && n > *s.MaxLength {
+		v.fail(field, "must be at most %d characters long", *s.MaxLength)
+	}
+
+	switch s.Format {
+	case "uuid":
+		if _, err := uuid.Parse(str); err != nil {
+			v.fail(field, "must be a UUID")
+		}
+	case "date-time":
+		if _, err := time.Parse(time.RFC3339, str); err != nil {
+			v.fail(field, "must be an RFC 3339 date-time")
+		}
+	case "date":
+		if _, err := time.Parse("2006-01-02", str); err != nil {
+			v.fail(field, "must be a date (YYYY-MM-DD)")
+		}
+	case "email":
+		if addr, err := mail.ParseAddress(str); err != nil || addr.Address != str {
+			v.fail(field, "must be an email address")
+		}
+	}
+}
+
+func (v *schemaValidator) validateNumber(s *Schema, num json.Number, field string) {
+	f, err := num.Float64()
+	if err != nil {
+		v.fail(field, "must be a %s", s.Type)
+		return
+	}
+	if s.Type == "integer" {
+		if _, err := num.Int64(); err != nil {
+			v.fail(field, "must be an integer")
+			return
+		}
+	}
+	if s.Minimum != nil && f < *s.Minimum {
+		v.fail(field, "must be >= %s", strconv.FormatFloat(*s.Minimum, 'f', -1, 64))
+	}
+	if s.Maximum != nil && f > *s.Maximum {
+		v.fail(field, "must be <= %s", strconv.FormatFloat(*s.Maximum, 'f', -1, 64))
+	}
+}
+
+// paramValue переводит строковое значение параметра в тип, ожидаемый схемой
+func paramValue(s *Schema, raw string) (interface{}, bool) {
+	switch s.Type {
+	case "integer", "number":
+		if _, err := strconv.ParseFloat(raw, 64); err != nil {
+			return nil, false
+		}
+		return json.Number(raw), true
+	case "boolean":
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, false
+		}
+		return b, true
+	}
+	return raw, true
+}
+
+func join(field, name string) string {
+	if field == "" {
+		return name
+	}
+	return field + "." + name
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
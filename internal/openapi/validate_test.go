@@ -0,0 +1,257 @@
+package openapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/TrueSmartcomm/backend/api"
+	"github.com/TrueSmartcomm/backend/internal/apperror"
+	"github.com/gin-gonic/gin"
+)
+
+// examples собирает схемы с примерами, доступные из s; name — путь к схеме в документе
+func examples(s *Schema, name string, seen map[*Schema]bool, out map[string]*Schema) {
+	if s == nil || seen[s] {
+		return
+	}
+	seen[s] = true
+	if s.Example != nil {
+		out[name] = s
+	}
+	for prop, sub := range s.Properties {
+		examples(sub, name+"."+prop, seen, out)
+	}
+	for i, sub := range s.AllOf {
+		examples(sub, fmt.Sprintf("%s.allOf[%d]", name, i), seen, out)
+	}
+	examples(s.Items, name+"[]", seen, out)
+}
+
+func TestSpecExamples(t *testing.T) {
+	doc, err := Load(api.Spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	schemas := map[string]*Schema{}
+	seen := map[*Schema]bool{}
+	for name, s := range doc.Components.Schemas {
+		examples(s, name, seen, schemas)
+	}
+	for path, item := range doc.Paths {
+		for method, op := range item.Operations() {
+			for _, p := range op.Parameters {
+				examples(p.Schema, method+" "+path+" "+p.Name, seen, schemas)
+			}
+			if op.RequestBody != nil {
+				examples(JSONSchema(op.RequestBody.Content), method+" "+path+" body", seen, schemas)
+			}
+			for status, resp := range op.Responses {
+				examples(JSONSchema(resp.Content), method+" "+path+" "+status, seen, schemas)
+			}
+		}
+	}
+	if len(schemas) == 0 {
+		t.Fatal("spec has no examples")
+	}
+
+	names := make([]string, 0, len(schemas))
+	for name := range schemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		t.Run(name, func(t *testing.T) {
+			s := schemas[name]
+			// Пример приводится к виду, который даёт decodeJSON для тела запроса
+			raw, err := json.Marshal(s.Example)
+			if err != nil {
+				t.Fatal(err)
+			}
+			val, err := decodeJSON(raw)
+			if err != nil {
+				t.Fatal(err)
+			}
+			sv := &schemaValidator{doc: doc, in: "example"}
+			sv.validate(s, val, "")
+			if len(sv.errs) > 0 {
+				t.Errorf("example %s does not match its schema: %v", raw, sv.errs)
+			}
+		})
+	}
+}
+
+const testSpec = `
+openapi: 3.0.3
+paths:
+  /items/{id}:
+    put:
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema:
+            type: string
+            format: uuid
+        - name: limit
+          in: query
+          schema:
+            type: integer
+            minimum: 1
+            maximum: 100
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/Item'
+      responses:
+        "200":
+          description: OK
+components:
+  schemas:
+    Kind:
+      type: string
+      enum: [a, b]
+    Item:
+      type: object
+      additionalProperties: false
+      required: [title]
+      properties:
+        title:
+          type: string
+          minLength: 1
+          maxLength: 5
+        kind:
+          $ref: '#/components/schemas/Kind'
+        due:
+          type: string
+          format: date-time
+          nullable: true
+        day:
+          type: string
+          format: date
+        email:
+          type: string
+          format: email
+        hours:
+          type: number
+          minimum: 0
+        tags:
+          type: array
+          items:
+            type: integer
+        done:
+          type: boolean
+`
+
+func TestValidatorMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	doc, err := Load([]byte(testSpec))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const id = "b39f8904-4b30-4ae2-b3e0-425c3382e928"
+	tests := []struct {
+		name   string
+		target string
+		body   string
+		want   []apperror.FieldError
+	}{
+		{
+			name:   "valid",
+			target: "/items/" + id + "?limit=10",
+			body:   `{"title":"abc","kind":"a","due":null,"day":"2025-04-15","email":"a@b.c","hours":1.5,"tags":[1,2],"done":true}`,
+		},
+		{
+			name:   "bad path and query parameters",
+			target: "/items/42?limit=0",
+			body:   `{"title":"abc"}`,
+			want: []apperror.FieldError{
+				{Name: "id", In: "path", Reason: "must be a UUID"},
+				{Name: "limit", In: "query", Reason: "must be >= 1"},
+			},
+		},
+		{
+			name:   "query parameter of wrong type",
+			target: "/items/" + id + "?limit=ten",
+			body:   `{"title":"abc"}`,
+			want:   []apperror.FieldError{{Name: "limit", In: "query", Reason: "must be a integer"}},
+		},
+		{
+			name:   "missing body",
+			target: "/items/" + id,
+			want:   []apperror.FieldError{{In: "body", Reason: "request body is required"}},
+		},
+		{
+			name:   "malformed body",
+			target: "/items/" + id,
+			body:   `{"title":`,
+			want:   []apperror.FieldError{{In: "body", Reason: "malformed JSON: unexpected EOF"}},
+		},
+		{
+			name:   "schema violations",
+			target: "/items/" + id,
+			body:   `{"kind":"c","due":"tomorrow","day":"15.04.2025","email":"John <a@b.c>","hours":-1,"tags":[1,"2"],"done":"yes","extra":1}`,
+			want: []apperror.FieldError{
+				{Name: "title", In: "body", Reason: "is required"},
+				{Name: "day", In: "body", Reason: "must be a date (YYYY-MM-DD)"},
+				{Name: "done", In: "body", Reason: "must be a boolean"},
+				{Name: "due", In: "body", Reason: "must be an RFC 3339 date-time"},
+				{Name: "email", In: "body", Reason: "must be an email address"},
+				{Name: "extra", In: "body", Reason: "unknown field"},
+				{Name: "hours", In: "body", Reason: "must be >= 0"},
+				{Name: "kind", In: "body", Reason: "must be one of [a, b]"},
+				{Name: "tags[1]", In: "body", Reason: "must be a integer"},
+			},
+		},
+		{
+			name:   "string length and null",
+			target: "/items/" + id,
+			body:   `{"title":"abcdef","kind":null}`,
+			want: []apperror.FieldError{
+				{Name: "kind", In: "body", Reason: "must not be null"},
+				{Name: "title", In: "body", Reason: "must be at most 5 characters long"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []apperror.FieldError
+			called := false
+			r := gin.New()
+			r.Use(func(c *gin.Context) {
+				c.Next()
+				for _, e := range c.Errors {
+					var appErr *apperror.Error
+					if !errors.As(e.Err, &appErr) {
+						t.Fatalf("unexpected error %v", e.Err)
+					}
+					got = append(got, appErr.Fields...)
+				}
+			})
+			r.Use(NewValidator(doc).Middleware())
+			r.PUT("/items/:id", func(c *gin.Context) {
+				called = true
+				c.Status(http.StatusOK)
+			})
+			req := httptest.NewRequest(http.MethodPut, tt.target, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			r.ServeHTTP(httptest.NewRecorder(), req)
+
+			if called != (len(tt.want) == 0) {
+				t.Errorf("handler called = %v, want %v", called, len(tt.want) == 0)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("errors = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
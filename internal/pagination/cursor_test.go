@@ -0,0 +1,66 @@
+package pagination
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestCodecRoundTrip(t *testing.T) {
+	codec := NewCodec([]byte("secret"))
+	tests := []Cursor{
+		{SortBy: "created_at", Value: "2024-01-02T03:04:05Z", ID: uuid.New()},
+		{SortBy: "priority", Desc: true, Value: "3", ID: uuid.New(), Backward: true},
+		{SortBy: "title", Value: "", ID: uuid.Nil},
+	}
+	for _, cur := range tests {
+		token, err := codec.Encode(cur)
+		if err != nil {
+			t.Fatalf("Encode(%+v): %v", cur, err)
+		}
+		got, err := codec.Decode(token)
+		if err != nil {
+			t.Fatalf("Decode(%q): %v", token, err)
+		}
+		if *got != cur {
+			t.Errorf("Decode(Encode(%+v)) = %+v", cur, *got)
+		}
+	}
+}
+
+func TestCodecRejectsInvalidTokens(t *testing.T) {
+	codec := NewCodec([]byte("secret"))
+	token, err := codec.Encode(Cursor{SortBy: "created_at", Value: "v", ID: uuid.New()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload, sig, _ := strings.Cut(token, ".")
+	other, err := NewCodec([]byte("other secret")).Encode(Cursor{SortBy: "created_at", Value: "v"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherPayload, _, _ := strings.Cut(other, ".")
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"empty", ""},
+		{"no signature", payload},
+		{"payload is not base64", "!!!." + sig},
+		{"signature is not base64", payload + ".!!!"},
+		{"foreign payload", otherPayload + "." + sig},
+		{"signed with another secret", other},
+		{"truncated signature", payload + "." + sig[:len(sig)-2]},
+		{"payload is not json", "bm90IGpzb24." + sig},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := codec.Decode(tt.token); !errors.Is(err, ErrInvalidCursor) {
+				t.Errorf("Decode(%q) error = %v, want ErrInvalidCursor", tt.token, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+// Package rank строит ключи ручного порядка карточек в колонке (fractional indexing).
+// Ключ — дробная часть числа в системе счисления по основанию 62, записанная цифрами
+// 0-9A-Za-z; ключи сравниваются побайтно (в БД — COLLATE "C"). Между любыми двумя
+// ключами есть новый ключ, поэтому перестановка карточки меняет только её собственный ранг.
+package rank
+
+import (
+	"errors"
+	"strings"
+)
+
+// Digits цифры ключа в порядке возрастания
+const Digits = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// MaxLength длина ключа, после которой ранги колонки стоит выровнять (Spread)
+const MaxLength = 16
+
+var (
+	// ErrInvalidKey ключ содержит посторонние символы или оканчивается на 0
+	ErrInvalidKey = errors.New("rank: invalid key")
+	// ErrOrder нижняя граница не меньше верхней
+	ErrOrder = errors.New("rank: lower bound is not below upper bound")
+)
+
+// Between возвращает ключ строго между a и b. Пустой a — начало колонки,
+// пустой b — её конец.
+func Between(a, b string) (string, error) {
+	if !valid(a) || !valid(b) {
+		return "", ErrInvalidKey
+	}
+	if b != "" && a >= b {
+		return "", ErrOrder
+	}
+	return midpoint(a, b), nil
+}
+
+// Spread n равномерно распределённых ключей одинаковой длины в порядке возрастания
+func Spread(n int) []string {
+	// ширина с запасом: между соседними ключами остаётся не меньше 62 значений
+	width, space := 1, int64(len(Digits))
+	for space < int64(n+1)*int64(len(Digits)) {
+		width++
+		space *= int64(len(Digits))
+	}
+	keys := make([]string, n)
+	for i := range keys {
+		keys[i] = encode(int64(i+1)*(space/int64(n+1)), width)
+	}
+	return keys
+}
+
+func valid(key string) bool {
+	if strings.HasSuffix(key, "0") {
+		return false
+	}
+	for i := 0; i < len(key); i++ {
+		if strings.IndexByte(Digits, key[i]) < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// midpoint середина между a и b; пустой b — верхняя граница 1
+func midpoint(a, b string) string {
+	if b != "" {
+		// общий префикс (a дополняется нулями) переносится в результат как есть
+		n := 0
+		for n < len(b) && digitAt(a, n) == b[n] {
+			n++
+		}
+		if n > 0 {
+			return b[:n] + midpoint(tail(a, n), b[n:])
+		}
+	}
+
+	da, db := 0, len(Digits)
+	if a != "" {
+		da = strings.IndexByte(Digits, a[0])
+	}
+	if b != "" {
+		db = strings.IndexByte(Digits, b[0])
+	}
+	if db-da > 1 {
+		return string(Digits[(da+db+1)/2])
+	}
+	// первые цифры соседние: берём первую цифру b, если за ней что-то есть,
+	// иначе первую цифру a и ищем середину после неё
+	if len(b) > 1 {
+		return b[:1]
+	}
+	return string(Digits[da]) + midpoint(tail(a, 1), "")
+}
+
+func digitAt(key string, i int) byte {
+	if i < len(key) {
+		return key[i]
+	}
+	return Digits[0]
+}
+
+func tail(key string, n int) string {
+	if n >= len(key) {
+		return ""
+	}
+	return key[n:]
+}
+
+// encode записывает v цифрами Digits ровно в width разрядов без завершающих нулей
+func encode(v int64, width int) string {
+	buf := make([]byte, width)
+	for i := width - 1; i >= 0; i-- {
+		buf[i] = Digits[v%int64(len(Digits))]
+		v /= int64(len(Digits))
+	}
+	return strings.TrimRight(string(buf), "0")
+}
@@ -0,0 +1,96 @@
+package rank
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestBetween(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want string
+		err  error
+	}{
+		{name: "empty column", a: "", b: "", want: "V"},
+		{name: "before first", a: "", b: "V", want: "G"},
+		{name: "after last", a: "V", b: "", want: "l"},
+		{name: "adjacent digits", a: "1", b: "2", want: "1V"},
+		{name: "common prefix", a: "a1", b: "a3", want: "a2"},
+		{name: "prefix of upper bound", a: "a", b: "a1", want: "a0V"},
+		{name: "trailing zero", a: "a0", b: "", err: ErrInvalidKey},
+		{name: "foreign character", a: "a-", b: "", err: ErrInvalidKey},
+		{name: "equal bounds", a: "a", b: "a", err: ErrOrder},
+		{name: "reversed bounds", a: "b", b: "a", err: ErrOrder},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Between(tt.a, tt.b)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("Between(%q, %q) error = %v, want %v", tt.a, tt.b, err, tt.err)
+			}
+			if err != nil {
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Between(%q, %q) = %q, want %q", tt.a, tt.b, got, tt.want)
+			}
+			if got <= tt.a || (tt.b != "" && got >= tt.b) || !valid(got) {
+				t.Errorf("Between(%q, %q) = %q is not a valid key strictly between the bounds", tt.a, tt.b, got)
+			}
+		})
+	}
+}
+
+// Вставки в начало и между соседями сохраняют порядок ключей
+func TestBetweenRepeated(t *testing.T) {
+	keys := []string{}
+	for i := 0; i < 200; i++ {
+		first := ""
+		if len(keys) > 0 {
+			first = keys[0]
+		}
+		key, err := Between("", first)
+		if err != nil {
+			t.Fatalf("insert at top #%d: %v", i, err)
+		}
+		keys = append([]string{key}, keys...)
+	}
+	for i := 0; i < 200; i++ {
+		key, err := Between(keys[0], keys[1])
+		if err != nil {
+			t.Fatalf("insert between #%d: %v", i, err)
+		}
+		keys = slices.Insert(keys, 1, key)
+	}
+	if !slices.IsSorted(keys) {
+		t.Fatal("keys are not sorted")
+	}
+	if len(slices.Compact(slices.Clone(keys))) != len(keys) {
+		t.Fatal("keys are not unique")
+	}
+}
+
+func TestSpread(t *testing.T) {
+	for _, n := range []int{0, 1, 2, 61, 62, 1000, 5000} {
+		keys := Spread(n)
+		if len(keys) != n {
+			t.Fatalf("Spread(%d) returned %d keys", n, len(keys))
+		}
+		if !slices.IsSorted(keys) || len(slices.Compact(slices.Clone(keys))) != n {
+			t.Fatalf("Spread(%d) keys are not strictly increasing", n)
+		}
+		for _, k := range keys {
+			if !valid(k) || k == "" || len(k) > MaxLength {
+				t.Fatalf("Spread(%d) produced invalid key %q", n, k)
+			}
+		}
+		// между соседними ключами остаётся место для новых
+		for i := 1; i < len(keys); i++ {
+			if _, err := Between(keys[i-1], keys[i]); err != nil {
+				t.Fatalf("Spread(%d): no key between %q and %q: %v", n, keys[i-1], keys[i], err)
+			}
+		}
+	}
+}
@@ -0,0 +1,578 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/TrueSmartcomm/backend/internal/access"
+	"github.com/TrueSmartcomm/backend/internal/apperror"
+	"github.com/TrueSmartcomm/backend/internal/models"
+	"github.com/TrueSmartcomm/backend/internal/requser"
+	"github.com/TrueSmartcomm/backend/internal/workflow"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var (
+	errBoardNotFound  = apperror.NotFound(apperror.CodeBoardNotFound, "board not found")
+	errColumnNotFound = apperror.NotFound(apperror.CodeColumnNotFound, "column not found")
+	errNoSuchStatus   = &models.ValidationError{Field: "status", Message: "no such status on the board"}
+)
+
+// querier общая часть пула и транзакции для запросов, выполняемых и там, и там
+type querier interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// BoardRepository доски и их колонки
+type BoardRepository struct {
+	DB *pgxpool.Pool
+	// Access права пользователя из контекста на доски; nil — права не проверяются
+	Access *access.Checker
+}
+
+func NewBoardRepository(db *pgxpool.Pool) *BoardRepository {
+	return &BoardRepository{DB: db}
+}
+
+const boardColumns = `id, name, description, is_default, created_at, updated_at`
+
+func scanBoard(row pgx.Row, b *models.Board) error {
+	return row.Scan(&b.ID, &b.Name, &b.Description, &b.IsDefault, &b.CreatedAt, &b.UpdatedAt)
+}
+
+const columnColumns = `id, board_id, key, name, position, status, wip_limit, wip_limit_per_assignee, wip_mode`
+
+func scanColumn(row pgx.Row, c *models.Column) error {
+	return row.Scan(&c.ID, &c.BoardID, &c.Key, &c.Name, &c.Position, &c.Status, &c.WIPLimit, &c.WIPLimitPerAssignee, &c.WIPMode)
+}
+
+// ListBoards возвращает доски, доступные пользователю, с колонками; доска по умолчанию первая
+func (r *BoardRepository) ListBoards(ctx context.Context) ([]models.Board, error) {
+	readable, _, err := r.Access.ReadableBoards(ctx, r.DB)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := r.DB.Query(ctx, `SELECT `+boardColumns+` FROM boards WHERE $1::uuid[] IS NULL OR id = ANY($1)
+                                ORDER BY is_default DESC, created_at, id`, readable)
+	if err != nil {
+		return nil, err
+	}
+	boards, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (models.Board, error) {
+		var b models.Board
+		err := scanBoard(row, &b)
+		b.Columns = []models.Column{}
+		return b, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	columns, err := r.columns(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	index := make(map[uuid.UUID]int, len(boards))
+	for i, b := range boards {
+		index[b.ID] = i
+	}
+	for _, c := range columns {
+		if i, ok := index[c.BoardID]; ok {
+			boards[i].Columns = append(boards[i].Columns, c)
+		}
+	}
+	return boards, nil
+}
+
+// GetBoard возвращает доску с колонками
+func (r *BoardRepository) GetBoard(ctx context.Context, id uuid.UUID) (*models.Board, error) {
+	if err := r.Access.Board(ctx, r.DB, id, access.BoardsRead); err != nil {
+		return nil, err
+	}
+	var b models.Board
+	err := scanBoard(r.DB.QueryRow(ctx, `SELECT `+boardColumns+` FROM boards WHERE id = $1`, id), &b)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errBoardNotFound
+		}
+		return nil, err
+	}
+	if b.Columns, err = r.columns(ctx, &id); err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+// ListColumns возвращает колонки доски в порядке position
+func (r *BoardRepository) ListColumns(ctx context.Context, boardID uuid.UUID) ([]models.Column, error) {
+	board, err := r.GetBoard(ctx, boardID)
+	if err != nil {
+		return nil, err
+	}
+	return board.Columns, nil
+}
+
+// columns колонки доски boardID (nil — всех досок) в порядке position
+func (r *BoardRepository) columns(ctx context.Context, boardID *uuid.UUID) ([]models.Column, error) {
+	rows, err := r.DB.Query(ctx, `SELECT `+columnColumns+` FROM board_columns
+                                WHERE $1::uuid IS NULL OR board_id = $1
+                                ORDER BY board_id, position`, boardID)
+	if err != nil {
+		return nil, err
+	}
+	columns, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (models.Column, error) {
+		var c models.Column
+		err := scanColumn(row, &c)
+		return c, err
+	})
+	if columns == nil {
+		columns = []models.Column{}
+	}
+	return columns, err
+}
+
+// CreateBoard создаёт доску вместе с колонками и статусами models.DefaultWorkflow.
+// Без колонок доска получает набор models.DefaultColumns. Создатель становится владельцем доски.
+func (r *BoardRepository) CreateBoard(ctx context.Context, b *models.Board) error {
+	if len(b.Columns) == 0 {
+		b.Columns = models.DefaultColumns()
+	}
+	for i := range b.Columns {
+		b.Columns[i].Position = i
+	}
+	if err := b.Validate(); err != nil {
+		return err
+	}
+
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := r.Access.Workspace(ctx, tx, access.BoardsCreate); err != nil {
+		return err
+	}
+	err = tx.QueryRow(ctx, `INSERT INTO boards (name, description) VALUES ($1, $2)
+                            RETURNING id, is_default, created_at, updated_at`, b.Name, b.Description).
+		Scan(&b.ID, &b.IsDefault, &b.CreatedAt, &b.UpdatedAt)
+	if err != nil {
+		return err
+	}
+	if userID, ok := requser.FromContext(ctx); ok {
+		_, err := tx.Exec(ctx, `INSERT INTO board_members (board_id, user_id, role) VALUES ($1, $2, $3)`,
+			b.ID, userID, access.RoleOwner)
+		if err != nil {
+			return err
+		}
+	}
+	w := models.DefaultWorkflow()
+	if err := insertStatuses(ctx, tx, b.ID, w.Statuses); err != nil {
+		return err
+	}
+	for i := range b.Columns {
+		c := &b.Columns[i]
+		c.BoardID = b.ID
+		err := tx.QueryRow(ctx, `INSERT INTO board_columns (board_id, key, name, position, status, wip_limit, wip_limit_per_assignee, wip_mode)
+                                 VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id`,
+			c.BoardID, c.Key, c.Name, c.Position, c.Status, c.WIPLimit, c.WIPLimitPerAssignee, c.WIPMode).Scan(&c.ID)
+		if pgError(err, pgForeignKeyViolation) != nil {
+			return &models.ValidationError{Field: fmt.Sprintf("columns[%d].status", i), Message: "no such status on the board"}
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit(ctx)
+}
+
+// UpdateBoard меняет название и описание доски; колонки меняются отдельными методами
+func (r *BoardRepository) UpdateBoard(ctx context.Context, b *models.Board) error {
+	if err := b.Validate(); err != nil {
+		return err
+	}
+	if err := r.Access.Board(ctx, r.DB, b.ID, access.BoardsManage); err != nil {
+		return err
+	}
+	err := scanBoard(r.DB.QueryRow(ctx, `UPDATE boards SET name = $1, description = $2, updated_at = now()
+                                         WHERE id = $3 RETURNING `+boardColumns, b.Name, b.Description, b.ID), b)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return errBoardNotFound
+		}
+		return err
+	}
+	b.Columns, err = r.columns(ctx, &b.ID)
+	return err
+}
+
+// DeleteBoard удаляет пустую доску вместе с колонками. Доску по умолчанию удалить нельзя.
+func (r *BoardRepository) DeleteBoard(ctx context.Context, id uuid.UUID) error {
+	if err := r.Access.Board(ctx, r.DB, id, access.BoardsDelete); err != nil {
+		return err
+	}
+	var isDefault bool
+	err := r.DB.QueryRow(ctx, `SELECT is_default FROM boards WHERE id = $1`, id).Scan(&isDefault)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return errBoardNotFound
+		}
+		return err
+	}
+	if isDefault {
+		return apperror.Conflict(apperror.CodeDefaultBoard, "default board cannot be deleted")
+	}
+
+	_, err = r.DB.Exec(ctx, `DELETE FROM boards WHERE id = $1`, id)
+	if pgError(err, pgForeignKeyViolation) != nil {
+		return apperror.Conflict(apperror.CodeBoardNotEmpty, "board has tasks")
+	}
+	return err
+}
+
+// lockBoard блокирует доску на время изменения её колонок и обновляет updated_at.
+// Возвращает число колонок доски.
+func lockBoard(ctx context.Context, tx pgx.Tx, boardID uuid.UUID) (int, error) {
+	var n int
+	err := tx.QueryRow(ctx, `UPDATE boards SET updated_at = now() WHERE id = $1
+                             RETURNING (SELECT count(*) FROM board_columns WHERE board_id = $1)`, boardID).Scan(&n)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, errBoardNotFound
+	}
+	if err != nil {
+		return 0, err
+	}
+	// позиции сдвигаются одним UPDATE, уникальность проверяется при фиксации
+	_, err = tx.Exec(ctx, `SET CONSTRAINTS board_columns_board_id_position_key DEFERRED`)
+	return n, err
+}
+
+// getColumn колонка доски под блокировкой строки
+func getColumn(ctx context.Context, tx pgx.Tx, boardID, id uuid.UUID) (*models.Column, error) {
+	var c models.Column
+	err := scanColumn(tx.QueryRow(ctx, `SELECT `+columnColumns+` FROM board_columns
+                                        WHERE id = $1 AND board_id = $2 FOR UPDATE`, id, boardID), &c)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, errColumnNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// clampPosition приводит желаемую позицию к диапазону [0, max]; nil — в конец
+func clampPosition(position *int, max int) int {
+	if position == nil || *position > max {
+		return max
+	}
+	if *position < 0 {
+		return 0
+	}
+	return *position
+}
+
+// CreateColumn добавляет колонку на позицию position (nil — в конец),
+// сдвигая последующие колонки
+func (r *BoardRepository) CreateColumn(ctx context.Context, c *models.Column, position *int) error {
+	if err := c.Validate(); err != nil {
+		return err
+	}
+
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := r.Access.Board(ctx, tx, c.BoardID, access.BoardsManage); err != nil {
+		return err
+	}
+	n, err := lockBoard(ctx, tx, c.BoardID)
+	if err != nil {
+		return err
+	}
+	c.Position = clampPosition(position, n)
+
+	_, err = tx.Exec(ctx, `UPDATE board_columns SET position = position + 1
+                           WHERE board_id = $1 AND position >= $2`, c.BoardID, c.Position)
+	if err != nil {
+		return err
+	}
+	err = tx.QueryRow(ctx, `INSERT INTO board_columns (board_id, key, name, position, status, wip_limit, wip_limit_per_assignee, wip_mode)
+                            VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id`,
+		c.BoardID, c.Key, c.Name, c.Position, c.Status, c.WIPLimit, c.WIPLimitPerAssignee, c.WIPMode).Scan(&c.ID)
+	if pgErr := pgError(err, pgUniqueViolation); pgErr != nil && pgErr.ConstraintName == "board_columns_board_id_key_key" {
+		return apperror.Conflict(apperror.CodeColumnKeyTaken, "column with this key already exists on the board")
+	}
+	if pgError(err, pgForeignKeyViolation) != nil {
+		return errNoSuchStatus
+	}
+	if err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// UpdateColumn меняет название, статус и WIP-лимиты колонки и, если задана position, её место
+// на доске. Ключ колонки не меняется: он хранится в задачах как kanban_space. Привязать колонку
+// к статусу можно, только если все её задачи уже в этом статусе. Новый лимит действует
+// на следующие перемещения: задачи, уже превышающие его, остаются в колонке.
+func (r *BoardRepository) UpdateColumn(ctx context.Context, c *models.Column, position *int) error {
+	if c.Name == "" {
+		return &models.ValidationError{Field: "name", Message: "name is required"}
+	}
+	if err := c.ValidateWIP(); err != nil {
+		return err
+	}
+
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := r.Access.Board(ctx, tx, c.BoardID, access.BoardsManage); err != nil {
+		return err
+	}
+	n, err := lockBoard(ctx, tx, c.BoardID)
+	if err != nil {
+		return err
+	}
+	current, err := getColumn(ctx, tx, c.BoardID, c.ID)
+	if err != nil {
+		return err
+	}
+	if c.Status != nil {
+		var mismatched bool
+		err := tx.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM tasks WHERE column_id = $1 AND status <> $2)`,
+			c.ID, *c.Status).Scan(&mismatched)
+		if err != nil {
+			return err
+		}
+		if mismatched {
+			return &models.ValidationError{Field: "status", Message: "column has tasks in other statuses"}
+		}
+	}
+
+	from, to := current.Position, current.Position
+	if position != nil {
+		to = clampPosition(position, n-1)
+	}
+	switch {
+	case to < from:
+		_, err = tx.Exec(ctx, `UPDATE board_columns SET position = position + 1
+                               WHERE board_id = $1 AND position >= $2 AND position < $3`, c.BoardID, to, from)
+	case to > from:
+		_, err = tx.Exec(ctx, `UPDATE board_columns SET position = position - 1
+                               WHERE board_id = $1 AND position > $2 AND position <= $3`, c.BoardID, from, to)
+	}
+	if err != nil {
+		return err
+	}
+
+	err = scanColumn(tx.QueryRow(ctx, `UPDATE board_columns SET name = $1, position = $2, status = $3,
+                                       wip_limit = $4, wip_limit_per_assignee = $5, wip_mode = $6
+                                       WHERE id = $7 RETURNING `+columnColumns,
+		c.Name, to, c.Status, c.WIPLimit, c.WIPLimitPerAssignee, c.WIPMode, c.ID), c)
+	if pgError(err, pgForeignKeyViolation) != nil {
+		return errNoSuchStatus
+	}
+	if err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// DeleteColumn удаляет пустую колонку. Последнюю колонку доски удалить нельзя:
+// в неё попадают задачи, созданные без колонки.
+func (r *BoardRepository) DeleteColumn(ctx context.Context, boardID, id uuid.UUID) error {
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := r.Access.Board(ctx, tx, boardID, access.BoardsManage); err != nil {
+		return err
+	}
+	n, err := lockBoard(ctx, tx, boardID)
+	if err != nil {
+		return err
+	}
+	c, err := getColumn(ctx, tx, boardID, id)
+	if err != nil {
+		return err
+	}
+	if n == 1 {
+		return apperror.Conflict(apperror.CodeLastColumn, "board must have at least one column")
+	}
+
+	_, err = tx.Exec(ctx, `DELETE FROM board_columns WHERE id = $1`, id)
+	if pgError(err, pgForeignKeyViolation) != nil {
+		return apperror.Conflict(apperror.CodeColumnNotEmpty, "column has tasks")
+	}
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(ctx, `UPDATE board_columns SET position = position - 1
+                           WHERE board_id = $1 AND position > $2`, boardID, c.Position)
+	if err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// GetWorkflow возвращает статусы и граф переходов доски
+func (r *BoardRepository) GetWorkflow(ctx context.Context, boardID uuid.UUID) (*models.Workflow, error) {
+	if err := r.Access.Board(ctx, r.DB, boardID, access.BoardsRead); err != nil {
+		return nil, err
+	}
+	var exists bool
+	if err := r.DB.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM boards WHERE id = $1)`, boardID).Scan(&exists); err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errBoardNotFound
+	}
+	return workflow.LoadWorkflow(ctx, r.DB, boardID)
+}
+
+// UpdateWorkflow заменяет статусы и переходы доски целиком. Статусы с прежними ключами
+// обновляются на месте; удалить статус, в котором есть задачи или к которому привязана
+// колонка, нельзя.
+func (r *BoardRepository) UpdateWorkflow(ctx context.Context, boardID uuid.UUID, w *models.Workflow) error {
+	if err := w.Validate(); err != nil {
+		return err
+	}
+
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := r.Access.Board(ctx, tx, boardID, access.BoardsManage); err != nil {
+		return err
+	}
+	if _, err := lockBoard(ctx, tx, boardID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, `DELETE FROM board_transitions WHERE board_id = $1`, boardID); err != nil {
+		return err
+	}
+
+	keys := make([]string, len(w.Statuses))
+	for i, s := range w.Statuses {
+		keys[i] = s.Key
+	}
+	_, err = tx.Exec(ctx, `DELETE FROM board_statuses WHERE board_id = $1 AND NOT key = ANY($2)`, boardID, keys)
+	if pgError(err, pgForeignKeyViolation) != nil {
+		return apperror.Conflict(apperror.CodeStatusInUse, "removed status is used by tasks or columns")
+	}
+	if err != nil {
+		return err
+	}
+	if err := insertStatuses(ctx, tx, boardID, w.Statuses); err != nil {
+		return err
+	}
+
+	for _, tr := range w.Transitions {
+		_, err := tx.Exec(ctx, `INSERT INTO board_transitions (board_id, from_status, to_status, required_fields, require_assignee, roles)
+                                VALUES ($1, $2, $3, $4, $5, $6)`,
+			boardID, tr.From, tr.To, tr.RequiredFields, tr.RequireAssignee, tr.Roles)
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit(ctx)
+}
+
+// insertStatuses добавляет статусы доски; существующие статусы с теми же ключами обновляются
+func insertStatuses(ctx context.Context, tx pgx.Tx, boardID uuid.UUID, statuses []models.BoardStatus) error {
+	for _, s := range statuses {
+		_, err := tx.Exec(ctx, `INSERT INTO board_statuses (board_id, key, name, position, final)
+                                VALUES ($1, $2, $3, $4, $5)
+                                ON CONFLICT (board_id, key) DO UPDATE
+                                SET name = EXCLUDED.name, position = EXCLUDED.position, final = EXCLUDED.final`,
+			boardID, s.Key, s.Name, s.Position, s.Final)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// defaultBoard ставит задачу без доски на доску по умолчанию
+func defaultBoard(ctx context.Context, q querier, task *models.Task) error {
+	return q.QueryRow(ctx, `SELECT id FROM boards WHERE is_default`).Scan(&task.BoardID)
+}
+
+// placeTask определяет доску, колонку и статус задачи: без доски — доска по умолчанию;
+// колонка берётся по ColumnID, иначе по ключу KanbanSpace, иначе первая колонка доски.
+// Колонка должна принадлежать доске задачи.
+//
+// Пустой Status заменяется статусом, к которому привязана колонка, иначе fallbackStatus
+// (если он есть на доске), иначе первым статусом доски. Явный статус должен существовать
+// на доске и совпадать с привязкой колонки.
+func placeTask(ctx context.Context, q querier, task *models.Task, fallbackStatus string) error {
+	if task.BoardID == uuid.Nil {
+		if err := defaultBoard(ctx, q, task); err != nil {
+			return err
+		}
+	}
+
+	query := `SELECT id, key, status FROM board_columns WHERE board_id = $1 ORDER BY position LIMIT 1`
+	args := []any{task.BoardID}
+	field := "board_id"
+	switch {
+	case task.ColumnID != uuid.Nil:
+		query = `SELECT id, key, status FROM board_columns WHERE board_id = $1 AND id = $2`
+		args, field = append(args, task.ColumnID), "column_id"
+	case task.KanbanSpace != "":
+		query = `SELECT id, key, status FROM board_columns WHERE board_id = $1 AND key = $2`
+		args, field = append(args, task.KanbanSpace), "kanban_space"
+	}
+
+	var bound *string
+	err := q.QueryRow(ctx, query, args...).Scan(&task.ColumnID, &task.KanbanSpace, &bound)
+	if errors.Is(err, pgx.ErrNoRows) {
+		var exists bool
+		if err := q.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM boards WHERE id = $1)`, task.BoardID).Scan(&exists); err != nil {
+			return err
+		}
+		if !exists {
+			return &models.ValidationError{Field: "board_id", Message: "board does not exist"}
+		}
+		return &models.ValidationError{Field: field, Message: "no such column on the board"}
+	}
+	if err != nil {
+		return err
+	}
+
+	if task.Status == "" {
+		if bound != nil {
+			task.Status = *bound
+			return nil
+		}
+		err := q.QueryRow(ctx, `SELECT key FROM board_statuses WHERE board_id = $1
+                                ORDER BY key = $2 DESC, position LIMIT 1`, task.BoardID, fallbackStatus).Scan(&task.Status)
+		return err
+	}
+	if bound != nil && *bound != task.Status {
+		return &models.ValidationError{Field: "status",
+			Message: fmt.Sprintf("column %s accepts only status %s", task.KanbanSpace, *bound)}
+	}
+	var exists bool
+	err = q.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM board_statuses WHERE board_id = $1 AND key = $2)`,
+		task.BoardID, task.Status).Scan(&exists)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return errNoSuchStatus
+	}
+	return nil
+}
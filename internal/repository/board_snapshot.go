@@ -0,0 +1,148 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/TrueSmartcomm/backend/internal/access"
+	"github.com/TrueSmartcomm/backend/internal/models"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// GetBoardSnapshot возвращает доску со всеми колонками, первыми perColumn карточками каждой
+// колонки в порядке rank и сводками по задачам доски, подходящим под фильтр f (BoardID,
+// сортировка и пагинация фильтра не используются). Запросы уходят одним пакетом (pgx.Batch),
+// поэтому снимок строится за один обмен с базой.
+func (r *BoardRepository) GetBoardSnapshot(ctx context.Context, id uuid.UUID, f models.TaskFilter, perColumn int) (*models.BoardSnapshot, error) {
+	if err := r.Access.Board(ctx, r.DB, id, access.BoardsRead); err != nil {
+		return nil, err
+	}
+	f.BoardID = &id
+	if perColumn <= 0 || perColumn > models.MaxSnapshotCards {
+		perColumn = models.DefaultSnapshotCards
+	}
+
+	batch := &pgx.Batch{}
+	batch.Queue(`SELECT `+boardColumns+` FROM boards WHERE id = $1`, id)
+	batch.Queue(`SELECT `+columnColumns+` FROM board_columns WHERE board_id = $1 ORDER BY position`, id)
+	cards := taskPredicates(f)
+	batch.Queue(`SELECT `+taskColumns+` FROM (
+                     SELECT *, row_number() OVER (PARTITION BY column_id ORDER BY rank, id) AS n FROM tasks`+cards.where()+`
+                 ) tasks WHERE n <= `+cards.arg(perColumn)+` ORDER BY column_id, rank, id`, cards.args...)
+	// одна группировка на все сводки: GROUPING(...) показывает, к какому набору относится строка.
+	// Задача с несколькими исполнителями даёт несколько строк соединения, поэтому задачи
+	// считаются по id без повторов.
+	counts := taskPredicates(f)
+	batch.Queue(`SELECT GROUPING(t.column_id, a.user_id, t.priority), t.column_id,
+                        (SELECT jsonb_build_object('id', u.id, 'login', u.login, 'email', u.email)
+                         FROM users u WHERE u.id = a.user_id),
+                        t.priority, count(DISTINCT t.id)
+                 FROM (SELECT id, column_id, priority FROM tasks`+counts.where()+`) t
+                 LEFT JOIN task_assignees a ON a.task_id = t.id
+                 GROUP BY GROUPING SETS ((t.column_id), (a.user_id), (t.priority))`, counts.args...)
+
+	results := r.DB.SendBatch(ctx, batch)
+	defer results.Close()
+
+	var board models.Board
+	if err := scanBoard(results.QueryRow(), &board); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errBoardNotFound
+		}
+		return nil, err
+	}
+	snapshot := &models.BoardSnapshot{
+		ID: board.ID, Name: board.Name, Description: board.Description, IsDefault: board.IsDefault,
+		Columns: []models.ColumnSnapshot{}, Assignees: []models.AssigneeCount{}, Priorities: []models.PriorityCount{},
+	}
+
+	rows, err := results.Query()
+	if err != nil {
+		return nil, err
+	}
+	byID := map[uuid.UUID]*models.ColumnSnapshot{}
+	columns, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (models.ColumnSnapshot, error) {
+		c := models.ColumnSnapshot{Tasks: []models.Task{}}
+		err := scanColumn(row, &c.Column)
+		return c, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if columns != nil {
+		snapshot.Columns = columns
+	}
+	for i := range snapshot.Columns {
+		byID[snapshot.Columns[i].ID] = &snapshot.Columns[i]
+	}
+
+	rows, err = results.Query()
+	if err != nil {
+		return nil, err
+	}
+	tasks, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (models.Task, error) {
+		var t models.Task
+		err := scanTask(row, &t)
+		return t, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range tasks {
+		if c, ok := byID[t.ColumnID]; ok {
+			c.Tasks = append(c.Tasks, t)
+		}
+	}
+
+	rows, err = results.Query()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var grouping, count int
+		var columnID *uuid.UUID
+		var assignee *models.UserSummary
+		var priority *string
+		if err := rows.Scan(&grouping, &columnID, &assignee, &priority, &count); err != nil {
+			return nil, err
+		}
+		// бит аргумента GROUPING равен 1, если столбец не входит в набор группировки
+		switch grouping {
+		case 0b011:
+			if c, ok := byID[*columnID]; ok {
+				c.Count = count
+			}
+			snapshot.Total += count
+		case 0b101:
+			snapshot.Assignees = append(snapshot.Assignees, models.AssigneeCount{Assignee: assignee, Count: count})
+		case 0b110:
+			if priority != nil {
+				snapshot.Priorities = append(snapshot.Priorities, models.PriorityCount{Priority: *priority, Count: count})
+			}
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(snapshot.Assignees, func(a, b models.AssigneeCount) int {
+		if (a.Assignee == nil) != (b.Assignee == nil) {
+			if a.Assignee == nil {
+				return 1
+			}
+			return -1
+		}
+		if c := cmp.Compare(b.Count, a.Count); c != 0 || a.Assignee == nil {
+			return c
+		}
+		return cmp.Compare(a.Assignee.Login, b.Assignee.Login)
+	})
+	slices.SortFunc(snapshot.Priorities, func(a, b models.PriorityCount) int {
+		return cmp.Compare(priorityRank(b.Priority), priorityRank(a.Priority))
+	})
+	return snapshot, nil
+}
@@ -0,0 +1,457 @@
+package repository
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/TrueSmartcomm/backend/internal/access"
+	"github.com/TrueSmartcomm/backend/internal/apperror"
+	"github.com/TrueSmartcomm/backend/internal/models"
+	"github.com/TrueSmartcomm/backend/internal/schedule"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// AddDependency добавляет связь relation от taskID к dependentTaskID.
+// Обе задачи должны существовать и быть не в корзине. Для связей blocks и subtask_of
+// проверяется, что связь не замыкает цикл в общем графе предшествования, по которому
+// строится расписание (в том числе цикл из связей разных типов). Проверка и вставка выполняются в одной транзакции под общим advisory lock,
+// поэтому две параллельные вставки, замыкающие цикл, не пройдут обе.
+func (r *TaskRepository) AddDependency(ctx context.Context, taskID, dependentTaskID uuid.UUID, relation string) error {
+	// Проверяем, что задачи не одинаковые
+	if taskID == dependentTaskID {
+		return apperror.Validation("task cannot depend on itself",
+			apperror.FieldError{Name: "dependent_task_id", Reason: "must differ from task id"})
+	}
+
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := r.canWriteTasks(ctx, tx, []uuid.UUID{taskID, dependentTaskID}); err != nil {
+		return err
+	}
+	// обе задачи должны быть не в корзине; FOR SHARE не даёт удалить их до конца транзакции
+	rows, err := tx.Query(ctx, `SELECT id FROM tasks WHERE id = ANY($1) AND deleted_at IS NULL
+                                ORDER BY id FOR SHARE`, []uuid.UUID{taskID, dependentTaskID})
+	if err != nil {
+		return err
+	}
+	live, err := pgx.CollectRows(rows, pgx.RowTo[uuid.UUID])
+	if err != nil {
+		return err
+	}
+	switch {
+	case !slices.Contains(live, taskID):
+		return errTaskNotFound
+	case !slices.Contains(live, dependentTaskID):
+		return errDependentTaskNotFound
+	}
+	if models.RelationAcyclic(relation) {
+		if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock(hashtext('task_dependencies:precedence'))`); err != nil {
+			return err
+		}
+		// Новая связь даёт ребро before → after; цикл появится, если before уже
+		// достижима из after
+		before, after := taskID, dependentTaskID
+		if relation == models.RelationSubtaskOf {
+			before, after = dependentTaskID, taskID
+		}
+		path, err := findPath(ctx, tx, after, before)
+		if err != nil {
+			return err
+		}
+		if path != nil {
+			return &models.DependencyCycleError{Relation: relation, Path: append([]uuid.UUID{before}, path...)}
+		}
+	}
+
+	query := `INSERT INTO task_dependencies (task_id, dependent_task_id, relation)
+              VALUES ($1, $2, $3)
+              ON CONFLICT (task_id, dependent_task_id, relation) DO NOTHING`
+
+	tag, err := tx.Exec(ctx, query, taskID, dependentTaskID, relation)
+	if err != nil {
+		return mapTaskError(err)
+	}
+	if tag.RowsAffected() > 0 {
+		if err := recordRelation(ctx, tx, models.HistoryDependencyAdded, taskID, dependentTaskID, relation); err != nil {
+			return err
+		}
+	}
+	return tx.Commit(ctx)
+}
+
+// findPath ищет путь from → … → to в графе предшествования: blocks — блокирующая →
+// заблокированная, subtask_of — подзадача → родитель (как в schedule.Compute).
+// Возвращает nil, если пути нет. Задачи в корзине учитываются: после восстановления
+// они снова попадают в расписание.
+func findPath(ctx context.Context, tx pgx.Tx, from, to uuid.UUID) ([]uuid.UUID, error) {
+	// UNION (а не UNION ALL) отбрасывает уже пройденные рёбра, поэтому обход конечен
+	// даже при наличии циклов в уже сохранённых связях
+	query := `WITH RECURSIVE precedence(before_id, after_id) AS (
+                  SELECT task_id, dependent_task_id FROM task_dependencies WHERE relation = $2
+                  UNION ALL
+                  SELECT dependent_task_id, task_id FROM task_dependencies WHERE relation = $3
+              ), reach(before_id, after_id) AS (
+                  SELECT before_id, after_id FROM precedence WHERE before_id = $1
+                  UNION
+                  SELECT p.before_id, p.after_id FROM precedence p
+                  JOIN reach ON p.before_id = reach.after_id
+              )
+              SELECT before_id, after_id FROM reach`
+
+	rows, err := tx.Query(ctx, query, from, models.RelationBlocks, models.RelationSubtaskOf)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	edges := map[uuid.UUID][]uuid.UUID{}
+	for rows.Next() {
+		var parent, child uuid.UUID
+		if err := rows.Scan(&parent, &child); err != nil {
+			return nil, err
+		}
+		edges[parent] = append(edges[parent], child)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// Поиск в ширину даёт кратчайший путь — его и показываем клиенту
+	prev := map[uuid.UUID]uuid.UUID{from: from}
+	queue := []uuid.UUID{from}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		if cur == to {
+			var path []uuid.UUID
+			for id := to; id != from; id = prev[id] {
+				path = append(path, id)
+			}
+			path = append(path, from)
+			for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+				path[i], path[j] = path[j], path[i]
+			}
+			return path, nil
+		}
+		for _, next := range edges[cur] {
+			if _, seen := prev[next]; !seen {
+				prev[next] = cur
+				queue = append(queue, next)
+			}
+		}
+	}
+	return nil, nil
+}
+
+// RemoveDependency удаляет связь между задачами. relation == nil удаляет связи всех типов.
+// Удалённые связи записываются в историю обеих задач.
+func (r *TaskRepository) RemoveDependency(ctx context.Context, taskID, dependentTaskID uuid.UUID, relation *string) error {
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := r.canWriteTasks(ctx, tx, []uuid.UUID{taskID, dependentTaskID}); err != nil {
+		return err
+	}
+	query := `DELETE FROM task_dependencies
+              WHERE task_id = $1 AND dependent_task_id = $2 AND ($3::text IS NULL OR relation = $3)
+              RETURNING relation`
+	rows, err := tx.Query(ctx, query, taskID, dependentTaskID, relation)
+	if err != nil {
+		return err
+	}
+	removed, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return err
+	}
+	for _, rel := range removed {
+		if err := recordRelation(ctx, tx, models.HistoryDependencyRemoved, taskID, dependentTaskID, rel); err != nil {
+			return err
+		}
+	}
+	return tx.Commit(ctx)
+}
+
+// loadRelations заполняет связи задачи по всем типам; связи с задачами в корзине пропускаются
+func (r *TaskRepository) loadRelations(ctx context.Context, task *models.Task) error {
+	query := `SELECT d.task_id, d.dependent_task_id, d.relation FROM task_dependencies d
+              JOIN tasks o ON o.id = CASE WHEN d.task_id = $1 THEN d.dependent_task_id ELSE d.task_id END
+              WHERE (d.task_id = $1 OR d.dependent_task_id = $1) AND o.deleted_at IS NULL
+              ORDER BY d.created_at, d.task_id, d.dependent_task_id`
+	rows, err := r.DB.Query(ctx, query, task.ID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var from, to uuid.UUID
+		var relation string
+		if err := rows.Scan(&from, &to, &relation); err != nil {
+			return err
+		}
+		outgoing := from == task.ID
+		other := to
+		if !outgoing {
+			other = from
+		}
+
+		switch {
+		case relation == models.RelationSubtaskOf && outgoing:
+			task.SubTasks = append(task.SubTasks, other)
+		case relation == models.RelationSubtaskOf:
+			task.ParentTasks = append(task.ParentTasks, other)
+		case relation == models.RelationBlocks && outgoing:
+			task.Blocks = append(task.Blocks, other)
+		case relation == models.RelationBlocks:
+			task.BlockedBy = append(task.BlockedBy, other)
+		case relation == models.RelationRelatesTo:
+			task.RelatesTo = append(task.RelatesTo, other)
+		case relation == models.RelationDuplicates && outgoing:
+			task.DuplicatedBy = append(task.DuplicatedBy, other)
+		case relation == models.RelationDuplicates:
+			task.Duplicates = append(task.Duplicates, other)
+		}
+	}
+
+	return rows.Err()
+}
+
+// GetTaskByIDWithDependencies получает задачу со связями
+func (r *TaskRepository) GetTaskByIDWithDependencies(ctx context.Context, id uuid.UUID) (*models.Task, error) {
+	task, err := r.GetTaskByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.loadRelations(ctx, task); err != nil {
+		return nil, err
+	}
+
+	return task, nil
+}
+
+// GetTaskGraph возвращает задачи, транзитивно связанные с q.Root, и связи между ними.
+// Обход выполняется одним рекурсивным запросом на глубину q.Depth+1: лишний уровень
+// нужен только для признака Truncated.
+func (r *TaskRepository) GetTaskGraph(ctx context.Context, q models.TaskGraphQuery) (*models.TaskGraph, error) {
+	root, err := r.GetTaskByID(ctx, q.Root)
+	if err != nil {
+		return nil, err
+	}
+
+	down := q.Direction == models.GraphDown || q.Direction == models.GraphBoth
+	up := q.Direction == models.GraphUp || q.Direction == models.GraphBoth
+	var relation *string
+	if q.Relation != "" {
+		relation = &q.Relation
+	}
+	// задачи досок, которые пользователь не может читать, в граф не попадают (nil — все доски)
+	boards, _, err := r.Access.ReadableBoards(ctx, r.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	// node — задача, в которую пришёл обход по связи; up — обход против направления связей.
+	// UNION отбрасывает повторы, а глубина ограничивает обход при циклах relates_to/duplicates.
+	// Задачи в корзине и на недоступных досках в обход не попадают и не продолжают его:
+	// иначе через скрытую задачу в граф попали бы задачи за ней и её id в связях.
+	query := `WITH RECURSIVE walk(task_id, dependent_task_id, relation, node, up, depth) AS (
+                  SELECT d.task_id, d.dependent_task_id, d.relation, d.dependent_task_id, false, 1
+                  FROM task_dependencies d
+                  JOIN tasks n ON n.id = d.dependent_task_id AND n.deleted_at IS NULL
+                              AND ($6::uuid[] IS NULL OR n.board_id = ANY($6))
+                  WHERE $2 AND d.task_id = $1 AND ($4::text IS NULL OR d.relation = $4)
+                  UNION
+                  SELECT d.task_id, d.dependent_task_id, d.relation, d.task_id, true, 1
+                  FROM task_dependencies d
+                  JOIN tasks n ON n.id = d.task_id AND n.deleted_at IS NULL
+                              AND ($6::uuid[] IS NULL OR n.board_id = ANY($6))
+                  WHERE $3 AND d.dependent_task_id = $1 AND ($4::text IS NULL OR d.relation = $4)
+                  UNION
+                  SELECT d.task_id, d.dependent_task_id, d.relation,
+                         CASE WHEN w.up THEN d.task_id ELSE d.dependent_task_id END, w.up, w.depth + 1
+                  FROM walk w
+                  JOIN task_dependencies d
+                    ON (NOT w.up AND d.task_id = w.node) OR (w.up AND d.dependent_task_id = w.node)
+                  JOIN tasks n ON n.id = CASE WHEN w.up THEN d.task_id ELSE d.dependent_task_id END
+                              AND n.deleted_at IS NULL AND ($6::uuid[] IS NULL OR n.board_id = ANY($6))
+                  WHERE w.depth <= $5 AND ($4::text IS NULL OR d.relation = $4)
+              )
+              SELECT w.task_id, w.dependent_task_id, w.relation, w.depth,
+                     t.id, t.title, t.status, t.kanban_space, COALESCE(t.priority, 'medium')
+              FROM walk w
+              JOIN tasks t ON t.id = w.node
+              ORDER BY w.depth, t.created_at, t.id`
+
+	rows, err := r.DB.Query(ctx, query, q.Root, down, up, relation, q.Depth, boards)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	graph := &models.TaskGraph{
+		Root: root.ID,
+		Nodes: []models.TaskGraphNode{{
+			ID: root.ID, Title: root.Title, Status: root.Status,
+			KanbanSpace: root.KanbanSpace, Priority: root.Priority,
+		}},
+		Edges: []models.TaskGraphEdge{},
+	}
+	nodes := map[uuid.UUID]bool{root.ID: true}
+	edges := map[models.TaskGraphEdge]bool{}
+
+	// Строки упорядочены по глубине, поэтому к строкам глубже q.Depth
+	// набор задач уже окончательный
+	for rows.Next() {
+		var (
+			edge  models.TaskGraphEdge
+			node  models.TaskGraphNode
+			depth int
+		)
+		if err := rows.Scan(&edge.From, &edge.To, &edge.Relation, &depth,
+			&node.ID, &node.Title, &node.Status, &node.KanbanSpace, &node.Priority); err != nil {
+			return nil, err
+		}
+
+		if depth > q.Depth && !nodes[node.ID] {
+			graph.Truncated = true
+			continue
+		}
+		if !nodes[node.ID] {
+			nodes[node.ID] = true
+			node.Depth = depth
+			graph.Nodes = append(graph.Nodes, node)
+		}
+		if !edges[edge] {
+			edges[edge] = true
+			graph.Edges = append(graph.Edges, edge)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// связь попадает в граф, только если в нём есть оба её конца
+	graph.Edges = slices.DeleteFunc(graph.Edges, func(e models.TaskGraphEdge) bool {
+		return !nodes[e.From] || !nodes[e.To]
+	})
+	return graph, nil
+}
+
+// GetSchedule строит расписание по методу критического пути от момента start.
+// Если root задан, в расписание входят root и все задачи, которые должны завершиться
+// раньше неё: блокирующие задачи и подзадачи, транзитивно. Иначе — все задачи.
+// Задачи досок, которые пользователь не может читать, в расписание не входят. Если задан
+// boardID, в расписание входят только задачи этой доски и связи между ними.
+func (r *TaskRepository) GetSchedule(ctx context.Context, root, boardID *uuid.UUID, start time.Time) (*schedule.Schedule, error) {
+	if root != nil {
+		if _, err := r.GetTaskByID(ctx, *root); err != nil {
+			return nil, err
+		}
+	}
+	var boards []uuid.UUID
+	if boardID != nil {
+		if err := r.Access.Board(ctx, r.DB, *boardID, access.TasksRead); err != nil {
+			return nil, err
+		}
+		boards = []uuid.UUID{*boardID}
+	} else {
+		var err error
+		if boards, _, err = r.Access.ReadableBoards(ctx, r.DB); err != nil {
+			return nil, err
+		}
+	}
+
+	// Предшественники задачи x: task_id связей blocks с dependent_task_id = x
+	// и dependent_task_id связей subtask_of с task_id = x.
+	// UNION по id задачи завершает обход и при цикле в связях. Задачи в корзине
+	// в расписание не входят, и обход через них не продолжается.
+	query := `WITH RECURSIVE scope(id) AS (
+                  SELECT $1::uuid WHERE $1::uuid IS NOT NULL
+                  UNION
+                  SELECT t.id
+                  FROM scope s
+                  JOIN task_dependencies d
+                    ON (d.relation = $2 AND d.dependent_task_id = s.id) OR (d.relation = $3 AND d.task_id = s.id)
+                  JOIN tasks t ON t.id = CASE WHEN d.relation = $2 THEN d.task_id ELSE d.dependent_task_id END
+                              AND t.deleted_at IS NULL
+              )
+              SELECT ` + taskColumns + ` FROM tasks
+              WHERE deleted_at IS NULL AND ($1::uuid IS NULL OR id IN (SELECT id FROM scope))
+                AND ($4::uuid[] IS NULL OR board_id = ANY($4))
+              ORDER BY created_at, id`
+
+	rows, err := r.DB.Query(ctx, query, root, models.RelationBlocks, models.RelationSubtaskOf, boards)
+	if err != nil {
+		return nil, err
+	}
+	tasks, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (models.Task, error) {
+		var task models.Task
+		err := scanTask(row, &task)
+		return task, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]uuid.UUID, len(tasks))
+	for i, t := range tasks {
+		ids[i] = t.ID
+	}
+	rows, err = r.DB.Query(ctx, `SELECT task_id, dependent_task_id, relation FROM task_dependencies
+                                 WHERE relation IN ($2, $3) AND task_id = ANY($1) AND dependent_task_id = ANY($1)`,
+		ids, models.RelationBlocks, models.RelationSubtaskOf)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var edges []schedule.Edge
+	for rows.Next() {
+		var from, to uuid.UUID
+		var relation string
+		if err := rows.Scan(&from, &to, &relation); err != nil {
+			return nil, err
+		}
+		if relation == models.RelationBlocks {
+			edges = append(edges, schedule.Edge{Before: from, After: to})
+		} else {
+			// подзадача должна завершиться раньше родителя
+			edges = append(edges, schedule.Edge{Before: to, After: from})
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	rows, err = r.DB.Query(ctx, `SELECT t.id FROM tasks t
+                                 JOIN board_statuses s ON s.board_id = t.board_id AND s.key = t.status
+                                 WHERE s.final AND t.id = ANY($1)`, ids)
+	if err != nil {
+		return nil, err
+	}
+	finished, err := pgx.CollectRows(rows, pgx.RowTo[uuid.UUID])
+	if err != nil {
+		return nil, err
+	}
+	done := make(map[uuid.UUID]bool, len(finished))
+	for _, id := range finished {
+		done[id] = true
+	}
+
+	s, err := schedule.Compute(start, tasks, edges, done)
+	if err != nil {
+		return nil, err
+	}
+	s.Root = root
+	return s, nil
+}
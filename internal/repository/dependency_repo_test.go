@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/TrueSmartcomm/backend/internal/access"
+	"github.com/TrueSmartcomm/backend/internal/models"
+	"github.com/TrueSmartcomm/backend/internal/requser"
+	"github.com/google/uuid"
+)
+
+// Задача на недоступной доске не попадает в граф и не продолжает обход:
+// visible → hidden → behind даёт граф из одной корневой задачи без связей.
+func TestGetTaskGraphHiddenMiddleNode(t *testing.T) {
+	db := testDB(t)
+	ctx := context.Background()
+
+	userID := testUser(t, db, "viewer")
+	visible := testBoard(t, db, "visible")
+	hidden := testBoard(t, db, "hidden")
+	if _, err := NewMemberRepository(db).SetMember(ctx, &visible.ID, userID, access.RoleViewer); err != nil {
+		t.Fatal(err)
+	}
+
+	r := NewTaskRepository(db)
+	owner := requser.WithUser(ctx, userID)
+	root := testTask(t, owner, r, visible.ID, "root")
+	middle := testTask(t, owner, r, hidden.ID, "middle")
+	behind := testTask(t, owner, r, visible.ID, "behind")
+	for _, d := range [][2]uuid.UUID{{root.ID, middle.ID}, {middle.ID, behind.ID}} {
+		if err := r.AddDependency(ctx, d[0], d[1], models.RelationBlocks); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	r.Access = access.New()
+	for _, dir := range []string{models.GraphDown, models.GraphBoth} {
+		t.Run(dir, func(t *testing.T) {
+			graph, err := r.GetTaskGraph(owner, models.TaskGraphQuery{Root: root.ID, Direction: dir, Depth: 5})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(graph.Nodes) != 1 || graph.Nodes[0].ID != root.ID {
+				t.Errorf("nodes = %v, want only the root", graph.Nodes)
+			}
+			if len(graph.Edges) != 0 {
+				t.Errorf("edges = %v, want none", graph.Edges)
+			}
+		})
+	}
+
+	// Без ограничений видна вся цепочка
+	r.Access = nil
+	graph, err := r.GetTaskGraph(owner, models.TaskGraphQuery{Root: root.ID, Direction: models.GraphDown, Depth: 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(graph.Nodes) != 3 || len(graph.Edges) != 2 {
+		t.Errorf("unrestricted graph has %d nodes and %d edges, want 3 and 2", len(graph.Nodes), len(graph.Edges))
+	}
+}
+
+// Связь с задачей в корзине или несуществующей задачей отклоняется в самой транзакции:
+// ошибка говорит, какая из двух задач отсутствует
+func TestAddDependencyMissingTask(t *testing.T) {
+	db := testDB(t)
+	ctx := context.Background()
+
+	board := testBoard(t, db, "board")
+	r := NewTaskRepository(db)
+	user := requser.WithUser(ctx, testUser(t, db, "owner"))
+	live := testTask(t, user, r, board.ID, "live")
+	deleted := testTask(t, user, r, board.ID, "deleted")
+	if err := r.DeleteTask(ctx, deleted.ID, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		task, dep uuid.UUID
+		want      error
+	}{
+		{"deleted task", deleted.ID, live.ID, errTaskNotFound},
+		{"deleted dependent", live.ID, deleted.ID, errDependentTaskNotFound},
+		{"unknown dependent", live.ID, uuid.New(), errDependentTaskNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := r.AddDependency(ctx, tt.task, tt.dep, models.RelationBlocks); !errors.Is(err, tt.want) {
+				t.Errorf("err = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
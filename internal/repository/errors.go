@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/TrueSmartcomm/backend/internal/apperror"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// errTaskNotFound задача с указанным id отсутствует
+var errTaskNotFound = apperror.NotFound(apperror.CodeTaskNotFound, "task not found")
+
+// errDependentTaskNotFound задача, на которую ссылается новая связь, отсутствует
+var errDependentTaskNotFound = apperror.NotFound(apperror.CodeTaskNotFound, "dependent task not found")
+
+// errTaskArchived архивная задача только для чтения; её нужно сначала вернуть из архива
+var errTaskArchived = apperror.Conflict(apperror.CodeTaskArchived, "task is archived")
+
+// Коды ошибок PostgreSQL, которые переводятся в ошибки предметной области
+const (
+	pgUniqueViolation     = "23505"
+	pgForeignKeyViolation = "23503"
+	pgCheckViolation      = "23514"
+)
+
+// checkConstraintFields поля, которые проверяют CHECK-ограничения таблиц tasks и task_dependencies
+var checkConstraintFields = map[string]string{
+	"chk_status":             "status",
+	"chk_kanban_space":       "kanban_space",
+	"chk_priority":           "priority",
+	"chk_no_self_dependency": "dependent_task_id",
+	"chk_relation":           "relation",
+	"chk_estimate_hours":     "estimate_hours",
+}
+
+// foreignKeyFields поля задачи, которые ссылаются на другие таблицы. Колонку или статус могут
+// удалить между выбором и записью задачи.
+var foreignKeyFields = map[string]string{
+	"tasks_board_id_fkey":  "board_id",
+	"tasks_column_id_fkey": "column_id",
+	"tasks_status_fkey":    "status",
+	// исполнителя могут удалить между проверкой и записью, а несуществующий id ловит только ссылка
+	"task_assignees_user_id_fkey": "assignee_ids",
+	"tasks_owner_id_fkey":         "owner",
+}
+
+// pgError возвращает ошибку PostgreSQL с кодом code или nil
+func pgError(err error, code string) *pgconn.PgError {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == code {
+		return pgErr
+	}
+	return nil
+}
+
+// mapTaskError переводит нарушение CHECK-ограничений и ссылок на доску и колонку
+// в ошибку валидации. Остальные ошибки возвращаются как есть и считаются внутренними.
+func mapTaskError(err error) error {
+	if pgErr := pgError(err, pgCheckViolation); pgErr != nil {
+		if field, ok := checkConstraintFields[pgErr.ConstraintName]; ok {
+			return apperror.Validation(field+": invalid value",
+				apperror.FieldError{Name: field, Reason: "invalid value"})
+		}
+	}
+	if pgErr := pgError(err, pgForeignKeyViolation); pgErr != nil {
+		if field, ok := foreignKeyFields[pgErr.ConstraintName]; ok {
+			return apperror.Validation(field+": no longer exists",
+				apperror.FieldError{Name: field, Reason: "no longer exists"})
+		}
+	}
+	return err
+}
@@ -0,0 +1,182 @@
+package repository
+
+import (
+	"context"
+	"slices"
+
+	"github.com/TrueSmartcomm/backend/internal/access"
+	"github.com/TrueSmartcomm/backend/internal/apperror"
+	"github.com/TrueSmartcomm/backend/internal/models"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// errLastOwner у доски или рабочего пространства должен остаться хотя бы один владелец
+var errLastOwner = apperror.Conflict(apperror.CodeLastOwner, "the last owner cannot be removed or demoted")
+
+// MemberRepository роли пользователей на досках и в рабочем пространстве
+type MemberRepository struct {
+	DB *pgxpool.Pool
+	// Access права пользователя из контекста; nil — права не проверяются
+	Access *access.Checker
+}
+
+func NewMemberRepository(db *pgxpool.Pool) *MemberRepository {
+	return &MemberRepository{DB: db}
+}
+
+// memberTable таблица ролей доски boardID (nil — рабочего пространства); условие
+// на доску добавляется в p. Таблица в запросе называется m.
+func memberTable(boardID *uuid.UUID, p *predicates) string {
+	if boardID == nil {
+		return "workspace_members"
+	}
+	p.add("m.board_id = ?", *boardID)
+	return "board_members"
+}
+
+// check проверяет право perm на доске boardID (nil — в рабочем пространстве)
+// и существование доски
+func (r *MemberRepository) check(ctx context.Context, q access.Querier, boardID *uuid.UUID, perm access.Permission) error {
+	if boardID == nil {
+		return r.Access.Workspace(ctx, q, perm)
+	}
+	if err := r.Access.Board(ctx, q, *boardID, perm); err != nil {
+		return err
+	}
+	var exists bool
+	if err := q.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM boards WHERE id = $1)`, *boardID).Scan(&exists); err != nil {
+		return err
+	}
+	if !exists {
+		return errBoardNotFound
+	}
+	return nil
+}
+
+// ListMembers возвращает участников доски boardID (nil — рабочего пространства) в порядке id.
+// На доске перечисляются только назначенные на неё роли.
+func (r *MemberRepository) ListMembers(ctx context.Context, boardID *uuid.UUID) ([]models.Member, error) {
+	if err := r.check(ctx, r.DB, boardID, access.BoardsRead); err != nil {
+		return nil, err
+	}
+	p := &predicates{}
+	table := memberTable(boardID, p)
+	rows, err := r.DB.Query(ctx, `SELECT u.id, u.login, u.email, m.role FROM `+table+` m
+                                  JOIN users u ON u.id = m.user_id`+p.where()+` ORDER BY u.id`, p.args...)
+	if err != nil {
+		return nil, err
+	}
+	members, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (models.Member, error) {
+		var m models.Member
+		err := row.Scan(&m.User.ID, &m.User.Login, &m.User.Email, &m.Role)
+		return m, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if members == nil {
+		members = []models.Member{}
+	}
+	return members, nil
+}
+
+// SetMember назначает пользователю userID роль role на доске boardID (nil — в рабочем
+// пространстве) и возвращает участника
+func (r *MemberRepository) SetMember(ctx context.Context, boardID *uuid.UUID, userID int, role string) (*models.Member, error) {
+	if !access.ValidRole(role) {
+		return nil, &models.ValidationError{Field: "role", Message: "unknown role"}
+	}
+
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := r.prepareChange(ctx, tx, boardID, userID, role); err != nil {
+		return nil, err
+	}
+	if boardID == nil {
+		_, err = tx.Exec(ctx, `INSERT INTO workspace_members (user_id, role) VALUES ($1, $2)
+                               ON CONFLICT (user_id) DO UPDATE SET role = EXCLUDED.role`, userID, role)
+	} else {
+		_, err = tx.Exec(ctx, `INSERT INTO board_members (board_id, user_id, role) VALUES ($1, $2, $3)
+                               ON CONFLICT (board_id, user_id) DO UPDATE SET role = EXCLUDED.role`, *boardID, userID, role)
+	}
+	if pgErr := pgError(err, pgForeignKeyViolation); pgErr != nil {
+		if pgErr.ConstraintName == "board_members_board_id_fkey" {
+			return nil, errBoardNotFound
+		}
+		return nil, errUserNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	member := &models.Member{Role: role}
+	err = tx.QueryRow(ctx, `SELECT id, login, email FROM users WHERE id = $1`, userID).
+		Scan(&member.User.ID, &member.User.Login, &member.User.Email)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return member, nil
+}
+
+// RemoveMember снимает роль пользователя userID на доске boardID (nil — в рабочем
+// пространстве); пользователь без роли пропускается
+func (r *MemberRepository) RemoveMember(ctx context.Context, boardID *uuid.UUID, userID int) error {
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := r.prepareChange(ctx, tx, boardID, userID, ""); err != nil {
+		return err
+	}
+	p := &predicates{}
+	table := memberTable(boardID, p)
+	p.add("m.user_id = ?", userID)
+	if _, err := tx.Exec(ctx, `DELETE FROM `+table+` m`+p.where(), p.args...); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// prepareChange проверяет смену роли userID на role ("" — снятие роли): роли ниже owner
+// меняет обладатель members:manage, а роль owner назначает и снимает только владелец
+// (owners:manage). Владельцы блокируются до конца транзакции, поэтому параллельные запросы
+// не могут понизить двух последних владельцев одновременно.
+func (r *MemberRepository) prepareChange(ctx context.Context, tx pgx.Tx, boardID *uuid.UUID, userID int, role string) error {
+	if err := r.check(ctx, tx, boardID, access.MembersManage); err != nil {
+		return err
+	}
+
+	p := &predicates{}
+	table := memberTable(boardID, p)
+	p.add("m.role = ?", access.RoleOwner)
+	rows, err := tx.Query(ctx, `SELECT m.user_id FROM `+table+` m`+p.where()+` ORDER BY m.user_id FOR UPDATE`, p.args...)
+	if err != nil {
+		return err
+	}
+	owners, err := pgx.CollectRows(rows, pgx.RowTo[int])
+	if err != nil {
+		return err
+	}
+
+	owner := slices.Contains(owners, userID)
+	if owner || role == access.RoleOwner {
+		if err := r.check(ctx, tx, boardID, access.OwnersManage); err != nil {
+			return err
+		}
+	}
+	if owner && role != access.RoleOwner && len(owners) == 1 {
+		return errLastOwner
+	}
+	return nil
+}
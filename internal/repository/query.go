@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+)
+
+// predicates собирает условия WHERE и их аргументы.
+// Плейсхолдеры нумеруются автоматически в порядке добавления.
+type predicates struct {
+	conds []string
+	args  []interface{}
+}
+
+// add добавляет условие; каждое "?" в expr заменяется на очередной $N
+func (p *predicates) add(expr string, args ...interface{}) {
+	for _, arg := range args {
+		p.args = append(p.args, arg)
+		expr = strings.Replace(expr, "?", fmt.Sprintf("$%d", len(p.args)), 1)
+	}
+	p.conds = append(p.conds, expr)
+}
+
+// where возвращает готовый фрагмент " WHERE ..." или пустую строку
+func (p *predicates) where() string {
+	if len(p.conds) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(p.conds, " AND ")
+}
+
+// arg добавляет аргумент без условия и возвращает его плейсхолдер (для LIMIT/OFFSET)
+func (p *predicates) arg(v interface{}) string {
+	p.args = append(p.args, v)
+	return fmt.Sprintf("$%d", len(p.args))
+}
@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/TrueSmartcomm/backend/internal/migrate"
+	"github.com/TrueSmartcomm/backend/internal/models"
+	"github.com/TrueSmartcomm/backend/migrations"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// testDB пул к TEST_DATABASE_URL со свежей схемой, в которой применены все миграции.
+// Схема удаляется после теста. Без TEST_DATABASE_URL тест пропускается:
+// TEST_DATABASE_URL=postgres://... go test ./internal/repository
+func testDB(t *testing.T) *pgxpool.Pool {
+	t.Helper()
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+	ctx := context.Background()
+
+	admin, err := pgx.Connect(ctx, dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	schema := fmt.Sprintf("test_%d", time.Now().UnixNano())
+	if _, err := admin.Exec(ctx, `CREATE SCHEMA `+schema); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		admin.Exec(ctx, `DROP SCHEMA `+schema+` CASCADE`)
+		admin.Close(ctx)
+	})
+
+	cfg, err := pgxpool.ParseConfig(dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.ConnConfig.RuntimeParams["search_path"] = schema + ",public"
+	pool, err := pgxpool.NewWithConfig(ctx, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(pool.Close)
+
+	m, err := migrate.New(pool, migrations.FS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.Close()
+	if err := m.Up(ctx); err != nil {
+		t.Fatalf("migrate up: %v", err)
+	}
+	return pool
+}
+
+// testUser регистрирует пользователя login без роли в рабочем пространстве
+func testUser(t *testing.T, db *pgxpool.Pool, login string) int {
+	t.Helper()
+	u := &models.User{Login: login, Email: login + "@example.com", PasswordHash: "x"}
+	if err := NewUserRepository(db).CreateUser(context.Background(), u); err != nil {
+		t.Fatal(err)
+	}
+	return u.ID
+}
+
+// testBoard создаёт доску с колонками по умолчанию
+func testBoard(t *testing.T, db *pgxpool.Pool, name string) *models.Board {
+	t.Helper()
+	b := &models.Board{Name: name}
+	if err := NewBoardRepository(db).CreateBoard(context.Background(), b); err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+// testTask создаёт задачу title на доске boardID от имени пользователя из ctx
+func testTask(t *testing.T, ctx context.Context, r *TaskRepository, boardID uuid.UUID, title string) *models.Task {
+	t.Helper()
+	task := &models.Task{Title: title, BoardID: boardID}
+	if err := r.CreateTask(ctx, task); err != nil {
+		t.Fatalf("create %s: %v", title, err)
+	}
+	return task
+}
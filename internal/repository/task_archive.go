@@ -0,0 +1,182 @@
+package repository
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/TrueSmartcomm/backend/internal/models"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// archiveInterval как часто TaskArchiver ищет задачи для архивации
+const archiveInterval = time.Hour
+
+// recordArchived записывает в историю архивацию задач из строк (id, archived_at)
+// и возвращает их id
+func recordArchived(ctx context.Context, tx pgx.Tx, rows pgx.Rows) ([]uuid.UUID, error) {
+	type archived struct {
+		id uuid.UUID
+		at time.Time
+	}
+	items, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (archived, error) {
+		var a archived
+		err := row.Scan(&a.id, &a.at)
+		return a, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]uuid.UUID, 0, len(items))
+	for _, a := range items {
+		change := models.FieldChange{Field: "archived_at", New: a.at}
+		if err := recordHistory(ctx, tx, a.id, models.HistoryArchived, []models.FieldChange{change}); err != nil {
+			return nil, err
+		}
+		ids = append(ids, a.id)
+	}
+	return ids, nil
+}
+
+// ArchiveTasks переносит задачи в архив и возвращает id архивированных. Задачи, уже
+// находящиеся в архиве, отсутствующие или в корзине, пропускаются.
+func (r *TaskRepository) ArchiveTasks(ctx context.Context, ids []uuid.UUID) ([]uuid.UUID, error) {
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := r.canWriteTasks(ctx, tx, ids); err != nil {
+		return nil, err
+	}
+	rows, err := tx.Query(ctx, `UPDATE tasks SET archived_at = now(), version = version + 1
+                                WHERE id = ANY($1) AND archived_at IS NULL AND deleted_at IS NULL
+                                RETURNING id, archived_at`, ids)
+	if err != nil {
+		return nil, err
+	}
+	archived, err := recordArchived(ctx, tx, rows)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return archived, nil
+}
+
+// UnarchiveTasks возвращает задачи из архива на прежние места в колонках. Каждая задача
+// проверяется WIP-лимитами своей колонки с учётом уже возвращённых: превышение hard-лимита
+// отменяет весь возврат, soft-лимиты попадают в Warnings возвращённой задачи.
+// Задачи не из архива пропускаются.
+func (r *TaskRepository) UnarchiveTasks(ctx context.Context, ids []uuid.UUID) ([]models.Task, error) {
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := r.canWriteTasks(ctx, tx, ids); err != nil {
+		return nil, err
+	}
+	// блокируем в порядке id, чтобы параллельные запросы не взаимоблокировались
+	rows, err := tx.Query(ctx, `SELECT `+taskColumns+` FROM tasks
+                                WHERE id = ANY($1) AND archived_at IS NOT NULL AND deleted_at IS NULL
+                                ORDER BY id FOR UPDATE`, ids)
+	if err != nil {
+		return nil, err
+	}
+	tasks, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (models.Task, error) {
+		var task models.Task
+		err := scanTask(row, &task)
+		return task, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range tasks {
+		task := &tasks[i]
+		archivedAt := *task.ArchivedAt
+		if err := checkWIP(ctx, tx, task, nil); err != nil {
+			return nil, err
+		}
+		warnings := task.Warnings
+		query := `UPDATE tasks SET archived_at = NULL, updated_at = now(), version = version + 1
+                  WHERE id = $1 RETURNING ` + taskColumns
+		if err := scanTask(tx.QueryRow(ctx, query, task.ID), task); err != nil {
+			return nil, err
+		}
+		task.Warnings = warnings
+
+		change := models.FieldChange{Field: "archived_at", Old: archivedAt}
+		if err := recordHistory(ctx, tx, task.ID, models.HistoryUnarchived, []models.FieldChange{change}); err != nil {
+			return nil, err
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+// TaskArchiver архивирует задачи, которые находятся в завершающем статусе доски дольше After.
+// Задачи без времени завершения (статус стал завершающим после изменения workflow доски)
+// отсчитываются от последнего изменения.
+type TaskArchiver struct {
+	DB    *pgxpool.Pool
+	After time.Duration
+}
+
+func NewTaskArchiver(db *pgxpool.Pool, after time.Duration) *TaskArchiver {
+	return &TaskArchiver{DB: db, After: after}
+}
+
+// Run архивирует задачи при запуске и затем раз в archiveInterval до отмены ctx
+func (a *TaskArchiver) Run(ctx context.Context) {
+	ticker := time.NewTicker(archiveInterval)
+	defer ticker.Stop()
+	for {
+		if n, err := a.Archive(ctx); err != nil {
+			log.Printf("[WARN] archive: %v", err)
+		} else if n > 0 {
+			log.Printf("archive: archived %d completed tasks", n)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Archive архивирует задачи, завершённые раньше чем After назад, и возвращает их количество
+func (a *TaskArchiver) Archive(ctx context.Context) (int, error) {
+	tx, err := a.DB.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx, `UPDATE tasks t SET archived_at = now(), version = t.version + 1
+                                FROM board_statuses s
+                                WHERE s.board_id = t.board_id AND s.key = t.status AND s.final
+                                  AND t.archived_at IS NULL AND t.deleted_at IS NULL
+                                  AND COALESCE(t.completed_at, t.updated_at) < now() - $1 * interval '1 second'
+                                RETURNING t.id, t.archived_at`, a.After.Seconds())
+	if err != nil {
+		return 0, err
+	}
+	archived, err := recordArchived(ctx, tx, rows)
+	if err != nil {
+		return 0, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+	return len(archived), nil
+}
@@ -0,0 +1,180 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/TrueSmartcomm/backend/internal/access"
+	"github.com/TrueSmartcomm/backend/internal/models"
+	"github.com/TrueSmartcomm/backend/internal/requser"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// errUnknownUser пользователь, которого назначают исполнителем или наблюдателем, не существует
+var errUnknownUser = &models.ValidationError{Field: "user_id", Message: "user does not exist"}
+
+// replaceAssignees делает исполнителями задачи ровно ids; новые исполнители становятся
+// наблюдателями. Несуществующий пользователь — нарушение ссылки task_assignees_user_id_fkey.
+func replaceAssignees(ctx context.Context, tx pgx.Tx, taskID uuid.UUID, ids []int) error {
+	if ids == nil {
+		ids = []int{} // NULL в ANY не совпал бы ни с одной строкой
+	}
+	if _, err := tx.Exec(ctx, `DELETE FROM task_assignees WHERE task_id = $1 AND NOT user_id = ANY($2::int[])`, taskID, ids); err != nil {
+		return err
+	}
+	_, err := tx.Exec(ctx, `INSERT INTO task_assignees (task_id, user_id) SELECT $1, unnest($2::int[])
+                            ON CONFLICT DO NOTHING`, taskID, ids)
+	if err != nil {
+		return err
+	}
+	return watch(ctx, tx, taskID, ids)
+}
+
+// watch подписывает пользователей на задачу; уже подписанные пропускаются
+func watch(ctx context.Context, tx pgx.Tx, taskID uuid.UUID, userIDs []int) error {
+	_, err := tx.Exec(ctx, `INSERT INTO task_watchers (task_id, user_id) SELECT $1, unnest($2::int[])
+                            ON CONFLICT DO NOTHING`, taskID, userIDs)
+	return err
+}
+
+// AddAssignee добавляет исполнителя задачи и возвращает её новое состояние.
+// Новый исполнитель проверяется WIP-лимитом колонки на исполнителя.
+func (r *TaskRepository) AddAssignee(ctx context.Context, id uuid.UUID, userID int) (*models.Task, error) {
+	return r.changeAssignees(ctx, id, func(ids []int) []int { return append(ids, userID) })
+}
+
+// RemoveAssignee снимает исполнителя задачи и возвращает её новое состояние;
+// наблюдателем он остаётся
+func (r *TaskRepository) RemoveAssignee(ctx context.Context, id uuid.UUID, userID int) (*models.Task, error) {
+	return r.changeAssignees(ctx, id, func(ids []int) []int {
+		return slices.DeleteFunc(ids, func(v int) bool { return v == userID })
+	})
+}
+
+// changeAssignees меняет исполнителей задачи функцией change под блокировкой строки.
+// Изменение увеличивает версию задачи и записывается в историю.
+func (r *TaskRepository) changeAssignees(ctx context.Context, id uuid.UUID, change func([]int) []int) (*models.Task, error) {
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	before, err := r.lockWritable(ctx, tx, id, nil)
+	if err != nil {
+		return nil, err
+	}
+	if before.ArchivedAt != nil {
+		return nil, errTaskArchived
+	}
+	task := *before
+	task.SetAssignees(change(slices.Clone(before.AssigneeIDs)))
+	if slices.Equal(task.AssigneeIDs, before.AssigneeIDs) {
+		return &task, tx.Commit(ctx)
+	}
+	if err := checkWIP(ctx, tx, &task, before); err != nil {
+		return nil, err
+	}
+	if err := replaceAssignees(ctx, tx, id, task.AssigneeIDs); err != nil {
+		if pgError(err, pgForeignKeyViolation) != nil {
+			return nil, errUnknownUser
+		}
+		return nil, err
+	}
+
+	query := `UPDATE tasks SET updated_at = now(), version = version + 1 WHERE id = $1 RETURNING ` + taskColumns
+	if err := scanTask(tx.QueryRow(ctx, query, id), &task); err != nil {
+		return nil, err
+	}
+	if err := recordHistory(ctx, tx, id, models.HistoryUpdated, models.DiffTasks(before, &task)); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+// ListWatchers возвращает наблюдателей задачи в порядке id
+func (r *TaskRepository) ListWatchers(ctx context.Context, id uuid.UUID) ([]models.UserSummary, error) {
+	if _, err := r.GetTaskByID(ctx, id); err != nil {
+		return nil, err
+	}
+	rows, err := r.DB.Query(ctx, `SELECT u.id, u.login, u.email FROM task_watchers w JOIN users u ON u.id = w.user_id
+                                  WHERE w.task_id = $1 ORDER BY u.id`, id)
+	if err != nil {
+		return nil, err
+	}
+	users, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (models.UserSummary, error) {
+		var u models.UserSummary
+		err := row.Scan(&u.ID, &u.Login, &u.Email)
+		return u, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if users == nil {
+		users = []models.UserSummary{}
+	}
+	return users, nil
+}
+
+// AddWatcher подписывает пользователя на задачу и возвращает её наблюдателей
+func (r *TaskRepository) AddWatcher(ctx context.Context, id uuid.UUID, userID int) ([]models.UserSummary, error) {
+	err := r.updateWatchers(ctx, id, userID, func(tx pgx.Tx) error {
+		err := watch(ctx, tx, id, []int{userID})
+		if pgError(err, pgForeignKeyViolation) != nil {
+			return errUnknownUser
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r.ListWatchers(ctx, id)
+}
+
+// RemoveWatcher отписывает пользователя от задачи и возвращает её наблюдателей
+func (r *TaskRepository) RemoveWatcher(ctx context.Context, id uuid.UUID, userID int) ([]models.UserSummary, error) {
+	err := r.updateWatchers(ctx, id, userID, func(tx pgx.Tx) error {
+		_, err := tx.Exec(ctx, `DELETE FROM task_watchers WHERE task_id = $1 AND user_id = $2`, id, userID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r.ListWatchers(ctx, id)
+}
+
+// updateWatchers выполняет update подписки userID для задачи не из корзины; FOR KEY SHARE
+// не даёт удалить её строку до конца транзакции. Подписка не меняет задачу: версия и история
+// остаются прежними. Подписать себя может читающий задачу, других — изменяющий её.
+func (r *TaskRepository) updateWatchers(ctx context.Context, id uuid.UUID, userID int, update func(tx pgx.Tx) error) error {
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	var boardID uuid.UUID
+	err = tx.QueryRow(ctx, `SELECT board_id FROM tasks WHERE id = $1 AND deleted_at IS NULL FOR KEY SHARE`, id).Scan(&boardID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return r.hideMissing(ctx, tx, id, errTaskNotFound)
+	}
+	if err != nil {
+		return err
+	}
+	perm := access.TasksWrite
+	if me, ok := requser.FromContext(ctx); ok && me == userID {
+		perm = access.TasksRead
+	}
+	if err := r.Access.Board(ctx, tx, boardID, perm); err != nil {
+		return err
+	}
+	if err := update(tx); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
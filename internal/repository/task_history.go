@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/TrueSmartcomm/backend/internal/access"
+	"github.com/TrueSmartcomm/backend/internal/models"
+	"github.com/TrueSmartcomm/backend/internal/requser"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// recordHistory записывает событие истории задачи в транзакции самого изменения.
+// Автор — пользователь из контекста (requser.WithUser). Событие без изменений не записывается.
+func recordHistory(ctx context.Context, tx pgx.Tx, taskID uuid.UUID, action string, changes []models.FieldChange) error {
+	if len(changes) == 0 {
+		return nil
+	}
+	var actorID *int
+	if userID, ok := requser.FromContext(ctx); ok {
+		actorID = &userID
+	}
+	_, err := tx.Exec(ctx, `INSERT INTO task_history (task_id, action, actor_id, changes) VALUES ($1, $2, $3, $4)`,
+		taskID, action, actorID, changes)
+	return err
+}
+
+// recordRelation записывает добавление или удаление связи relation в историю обеих задач
+func recordRelation(ctx context.Context, tx pgx.Tx, action string, taskID, dependentTaskID uuid.UUID, relation string) error {
+	from, to := models.RelationFields(relation)
+	change := func(field string, other uuid.UUID) []models.FieldChange {
+		if action == models.HistoryDependencyAdded {
+			return []models.FieldChange{{Field: field, New: other}}
+		}
+		return []models.FieldChange{{Field: field, Old: other}}
+	}
+	if err := recordHistory(ctx, tx, taskID, action, change(from, dependentTaskID)); err != nil {
+		return err
+	}
+	return recordHistory(ctx, tx, dependentTaskID, action, change(to, taskID))
+}
+
+// GetTaskHistory возвращает страницу истории задачи, новые события первыми. История удалённой
+// задачи сохраняется; задача, которой нет и не было, — errTaskNotFound.
+func (r *TaskRepository) GetTaskHistory(ctx context.Context, id uuid.UUID, limit, offset int) (*models.TaskHistoryPage, error) {
+	if limit <= 0 || limit > models.MaxHistoryLimit {
+		limit = models.DefaultHistoryLimit
+	}
+	// история окончательно удалённой задачи доступна только читающим все доски
+	var boardID uuid.UUID
+	err := r.DB.QueryRow(ctx, `SELECT board_id FROM tasks WHERE id = $1`, id).Scan(&boardID)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		err = r.Access.Workspace(ctx, r.DB, access.TasksRead)
+	case err == nil:
+		err = r.Access.Board(ctx, r.DB, boardID, access.TasksRead)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	page := &models.TaskHistoryPage{Items: []models.TaskHistoryEntry{}, Limit: limit, Offset: offset}
+	if err := r.DB.QueryRow(ctx, `SELECT count(*) FROM task_history WHERE task_id = $1`, id).Scan(&page.Total); err != nil {
+		return nil, err
+	}
+	if page.Total == 0 {
+		// задача без истории: созданная до её появления или несуществующая
+		if _, err := r.GetTaskByID(ctx, id); err != nil {
+			return nil, err
+		}
+		return page, nil
+	}
+
+	rows, err := r.DB.Query(ctx, `SELECT id, task_id, action, actor_id, changes, created_at FROM task_history
+                                  WHERE task_id = $1 ORDER BY id DESC LIMIT $2 OFFSET $3`, id, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	items, err := pgx.CollectRows(rows, pgx.RowToStructByName[models.TaskHistoryEntry])
+	if err != nil {
+		return nil, err
+	}
+	if items != nil {
+		page.Items = items
+	}
+	return page, nil
+}
@@ -0,0 +1,285 @@
+package repository
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/TrueSmartcomm/backend/internal/access"
+	"github.com/TrueSmartcomm/backend/internal/models"
+	"github.com/TrueSmartcomm/backend/internal/pagination"
+	"github.com/google/uuid"
+)
+
+// sortKey описывает поле сортировки: SQL-выражение, тип для приведения значения курсора
+// и способ получить значение ключа из задачи. Выражение не должно возвращать NULL,
+// иначе сравнение строк в keyset-условии перестанет работать.
+type sortKey struct {
+	expr  func(desc bool) string
+	cast  string
+	value func(t *models.Task, desc bool) string
+}
+
+func column(name string) func(bool) string {
+	return func(bool) string { return name }
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+// taskSortKeys белый список полей сортировки
+var taskSortKeys = map[string]sortKey{
+	models.SortByCreatedAt: {
+		expr:  column("created_at"),
+		cast:  "timestamp",
+		value: func(t *models.Task, _ bool) string { return formatTime(t.CreatedAt) },
+	},
+	models.SortByUpdatedAt: {
+		expr:  column("updated_at"),
+		cast:  "timestamp",
+		value: func(t *models.Task, _ bool) string { return formatTime(t.UpdatedAt) },
+	},
+	models.SortByDueDate: {
+		// задачи без срока всегда в конце списка, в любом направлении
+		expr: func(desc bool) string {
+			if desc {
+				return "COALESCE(due_date, '-infinity'::timestamp)"
+			}
+			return "COALESCE(due_date, 'infinity'::timestamp)"
+		},
+		cast: "timestamp",
+		value: func(t *models.Task, desc bool) string {
+			switch {
+			case t.DueDate != nil:
+				return formatTime(*t.DueDate)
+			case desc:
+				return "-infinity"
+			default:
+				return "infinity"
+			}
+		},
+	},
+	models.SortByTitle: {
+		expr:  column("title"),
+		cast:  "text",
+		value: func(t *models.Task, _ bool) string { return t.Title },
+	},
+	models.SortByStatus: {
+		expr:  column("status"),
+		cast:  "text",
+		value: func(t *models.Task, _ bool) string { return t.Status },
+	},
+	models.SortByRank: {
+		expr:  column("rank"),
+		cast:  "text",
+		value: func(t *models.Task, _ bool) string { return t.Rank },
+	},
+	models.SortByPriority: {
+		expr: column(`COALESCE(CASE priority WHEN 'low' THEN 1 WHEN 'medium' THEN 2
+			WHEN 'high' THEN 3 WHEN 'urgent' THEN 4 END, 0)`),
+		cast:  "int",
+		value: func(t *models.Task, _ bool) string { return strconv.Itoa(priorityRank(t.Priority)) },
+	},
+}
+
+func priorityRank(priority string) int {
+	switch priority {
+	case models.PriorityLow:
+		return 1
+	case models.PriorityMedium:
+		return 2
+	case models.PriorityHigh:
+		return 3
+	case models.PriorityUrgent:
+		return 4
+	}
+	return 0
+}
+
+// TaskPage страница задач с курсорами на соседние страницы
+type TaskPage struct {
+	Tasks []models.Task
+	Total int
+	Next  *pagination.Cursor
+	Prev  *pagination.Cursor
+}
+
+// taskPredicates строит условия выборки по фильтру; задачи в корзине не выбираются,
+// архивные — только по f.Archived. Условия ссылаются на tasks.id, поэтому таблица
+// в запросе не переименовывается.
+func taskPredicates(f models.TaskFilter) *predicates {
+	p := &predicates{}
+	p.add("deleted_at IS NULL")
+	switch f.Archived {
+	case models.ArchivedInclude:
+	case models.ArchivedOnly:
+		p.add("archived_at IS NOT NULL")
+	default:
+		p.add("archived_at IS NULL")
+	}
+	if f.BoardID != nil {
+		p.add("board_id = ?", *f.BoardID)
+	}
+	if f.ColumnID != nil {
+		p.add("column_id = ?", *f.ColumnID)
+	}
+	if f.KanbanSpace != "" {
+		p.add("kanban_space = ?", f.KanbanSpace)
+	}
+	if f.Status != "" {
+		p.add("status = ?", f.Status)
+	}
+	if f.Priority != "" {
+		p.add("priority = ?", f.Priority)
+	}
+	if f.OwnerID != nil {
+		p.add("owner_id = ?", *f.OwnerID)
+	}
+	if f.AssigneeID != nil {
+		p.add("EXISTS (SELECT 1 FROM task_assignees a WHERE a.task_id = tasks.id AND a.user_id = ?)", *f.AssigneeID)
+	}
+	if f.Mine != nil {
+		me := p.arg(*f.Mine)
+		p.add("(owner_id = " + me +
+			" OR EXISTS (SELECT 1 FROM task_assignees a WHERE a.task_id = tasks.id AND a.user_id = " + me + ")" +
+			" OR EXISTS (SELECT 1 FROM task_watchers w WHERE w.task_id = tasks.id AND w.user_id = " + me + "))")
+	}
+	if f.DueFrom != nil {
+		p.add("due_date >= ?", *f.DueFrom)
+	}
+	if f.DueTo != nil {
+		p.add("due_date <= ?", *f.DueTo)
+	}
+	if f.CreatedFrom != nil {
+		p.add("created_at >= ?", *f.CreatedFrom)
+	}
+	if f.CreatedTo != nil {
+		p.add("created_at <= ?", *f.CreatedTo)
+	}
+	if f.UpdatedFrom != nil {
+		p.add("updated_at >= ?", *f.UpdatedFrom)
+	}
+	if f.UpdatedTo != nil {
+		p.add("updated_at <= ?", *f.UpdatedTo)
+	}
+	return p
+}
+
+// readableBoards ограничивает выборку p задачами досок, которые пользователь может читать,
+// а при заданном boardID проверяет право чтения этой доски
+func readableBoards(ctx context.Context, c *access.Checker, q access.Querier, p *predicates, boardID *uuid.UUID) error {
+	if boardID != nil {
+		return c.Board(ctx, q, *boardID, access.TasksRead)
+	}
+	boards, all, err := c.ReadableBoards(ctx, q)
+	if err != nil || all {
+		return err
+	}
+	p.add("board_id = ANY(?)", boards)
+	return nil
+}
+
+// GetAllTasks получает страницу задач по фильтрам и общее количество подходящих задач.
+// Если в фильтре передан курсор, страница выбирается по ключу (keyset) вместо OFFSET:
+// такая выборка не зависит от глубины страницы и не «съезжает» при вставке новых задач.
+func (r *TaskRepository) GetAllTasks(ctx context.Context, f models.TaskFilter) (*TaskPage, error) {
+	p := taskPredicates(f)
+	if err := readableBoards(ctx, r.Access, r.DB, p, f.BoardID); err != nil {
+		return nil, err
+	}
+
+	page := &TaskPage{Tasks: []models.Task{}}
+	if err := r.DB.QueryRow(ctx, `SELECT count(*) FROM tasks`+p.where(), p.args...).Scan(&page.Total); err != nil {
+		return nil, err
+	}
+
+	key, ok := taskSortKeys[f.SortBy]
+	if !ok {
+		f.SortBy = models.SortByCreatedAt
+		key = taskSortKeys[f.SortBy]
+	}
+	limit := f.Limit
+	if limit <= 0 || limit > models.MaxTaskListLimit {
+		limit = models.DefaultTaskListLimit
+	}
+
+	// При движении назад выбираем в обратном порядке и переворачиваем результат
+	backward := f.Cursor != nil && f.Cursor.Backward
+	desc := f.Desc != backward
+	expr := key.expr(f.Desc)
+
+	offset := f.Offset
+	if f.Cursor != nil {
+		cmp := ">"
+		if desc {
+			cmp = "<"
+		}
+		p.add("("+expr+", id) "+cmp+" (?::"+key.cast+", ?)", f.Cursor.Value, f.Cursor.ID)
+		offset = 0
+	}
+
+	dir := "ASC"
+	if desc {
+		dir = "DESC"
+	}
+
+	// id добавляется вторым ключом, чтобы порядок был детерминированным при равных значениях.
+	// Выбираем на одну строку больше, чтобы узнать, есть ли следующая страница.
+	query := `SELECT ` + taskColumns + ` FROM tasks` + p.where() +
+		` ORDER BY ` + expr + ` ` + dir + `, id ` + dir +
+		` LIMIT ` + p.arg(limit+1) + ` OFFSET ` + p.arg(offset)
+
+	rows, err := r.DB.Query(ctx, query, p.args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var task models.Task
+		if err := scanTask(rows, &task); err != nil {
+			return nil, err
+		}
+		page.Tasks = append(page.Tasks, task)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	more := len(page.Tasks) > limit
+	if more {
+		page.Tasks = page.Tasks[:limit]
+	}
+	if backward {
+		for i, j := 0, len(page.Tasks)-1; i < j; i, j = i+1, j-1 {
+			page.Tasks[i], page.Tasks[j] = page.Tasks[j], page.Tasks[i]
+		}
+	}
+	if len(page.Tasks) == 0 {
+		return page, nil
+	}
+
+	hasNext, hasPrev := more, f.Cursor != nil || offset > 0
+	if backward {
+		hasNext, hasPrev = true, more
+	}
+
+	cursorAt := func(t *models.Task, backward bool) *pagination.Cursor {
+		return &pagination.Cursor{
+			SortBy:   f.SortBy,
+			Desc:     f.Desc,
+			Value:    key.value(t, f.Desc),
+			ID:       t.ID,
+			Backward: backward,
+		}
+	}
+	if hasNext {
+		page.Next = cursorAt(&page.Tasks[len(page.Tasks)-1], false)
+	}
+	if hasPrev {
+		page.Prev = cursorAt(&page.Tasks[0], true)
+	}
+
+	return page, nil
+}
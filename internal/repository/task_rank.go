@@ -0,0 +1,160 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/TrueSmartcomm/backend/internal/models"
+	"github.com/TrueSmartcomm/backend/internal/rank"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// rankedCards условие на карточки, которые участвуют в порядке колонки. Карточки в корзине
+// и архиве в нём не участвуют: ранги считаются и выравниваются только среди видимых,
+// а скрытая карточка сохраняет свой ключ и после возврата встаёт по нему между видимыми.
+const rankedCards = `deleted_at IS NULL AND archived_at IS NULL`
+
+// topRank ранг для карточки в начале колонки
+func topRank(ctx context.Context, q querier, columnID uuid.UUID) (string, error) {
+	var first string
+	err := q.QueryRow(ctx, `SELECT COALESCE(min(rank), '') FROM tasks
+                            WHERE column_id = $1 AND `+rankedCards, columnID).Scan(&first)
+	if err != nil {
+		return "", err
+	}
+	return rank.Between("", first)
+}
+
+// lockColumn блокирует колонку на время изменения порядка её карточек. FOR NO KEY UPDATE
+// не мешает вставке задач, ссылающихся на колонку.
+func lockColumn(ctx context.Context, tx pgx.Tx, columnID uuid.UUID) error {
+	_, err := tx.Exec(ctx, `SELECT 1 FROM board_columns WHERE id = $1 FOR NO KEY UPDATE`, columnID)
+	return err
+}
+
+// rankTask выбирает ранг карточки в её колонке: сразу после afterID и/или перед beforeID.
+// Без соседей карточка, сменившая колонку (fromColumn — прежняя), встаёт в начало колонки,
+// а оставшаяся в своей колонке сохраняет ранг. Колонка должна быть заблокирована (lockColumn).
+func rankTask(ctx context.Context, tx pgx.Tx, task *models.Task, fromColumn uuid.UUID, afterID, beforeID *uuid.UUID) error {
+	var err error
+	if afterID == nil && beforeID == nil {
+		if task.ColumnID != fromColumn {
+			task.Rank, err = topRank(ctx, tx, task.ColumnID)
+		}
+		return err
+	}
+
+	var lower, upper string
+	if afterID != nil {
+		if lower, err = neighborRank(ctx, tx, task, *afterID, "after_id"); err != nil {
+			return err
+		}
+	}
+	if beforeID != nil {
+		if upper, err = neighborRank(ctx, tx, task, *beforeID, "before_id"); err != nil {
+			return err
+		}
+	}
+
+	switch {
+	case beforeID == nil:
+		err = tx.QueryRow(ctx, `SELECT COALESCE(min(rank), '') FROM tasks
+                                WHERE column_id = $1 AND rank > $2 AND id <> $3 AND `+rankedCards,
+			task.ColumnID, lower, task.ID).Scan(&upper)
+	case afterID == nil:
+		err = tx.QueryRow(ctx, `SELECT COALESCE(max(rank), '') FROM tasks
+                                WHERE column_id = $1 AND rank < $2 AND id <> $3 AND `+rankedCards,
+			task.ColumnID, upper, task.ID).Scan(&lower)
+	case lower >= upper:
+		return &models.ValidationError{Field: "before_id", Message: "before_id must be below after_id in the column"}
+	}
+	if err != nil {
+		return err
+	}
+	task.Rank, err = rank.Between(lower, upper)
+	return err
+}
+
+// neighborRank ранг соседней карточки id; она должна быть в колонке задачи
+func neighborRank(ctx context.Context, tx pgx.Tx, task *models.Task, id uuid.UUID, field string) (string, error) {
+	if id == task.ID {
+		return "", &models.ValidationError{Field: field, Message: "task cannot be placed next to itself"}
+	}
+	var key string
+	var columnID uuid.UUID
+	err := tx.QueryRow(ctx, `SELECT rank, column_id FROM tasks WHERE id = $1 AND `+rankedCards, id).Scan(&key, &columnID)
+	if errors.Is(err, pgx.ErrNoRows) || (err == nil && columnID != task.ColumnID) {
+		return "", &models.ValidationError{Field: field, Message: "task is not in the target column"}
+	}
+	return key, err
+}
+
+// Rebalancer выравнивает ранги карточек колонки в фоне, когда ключи становятся слишком
+// длинными (частые вставки в одно место). Порядок карточек при этом не меняется, но rank
+// входит в представление задачи, поэтому версии переписанных задач увеличиваются.
+type Rebalancer struct {
+	DB      *pgxpool.Pool
+	pending chan uuid.UUID
+}
+
+func NewRebalancer(db *pgxpool.Pool) *Rebalancer {
+	return &Rebalancer{DB: db, pending: make(chan uuid.UUID, 64)}
+}
+
+// Schedule ставит колонку в очередь, если ключ key слишком длинный. При переполненной
+// очереди запрос отбрасывается: колонка вернётся в очередь при следующей длинной вставке.
+func (b *Rebalancer) Schedule(columnID uuid.UUID, key string) {
+	if b == nil || len(key) <= rank.MaxLength {
+		return
+	}
+	select {
+	case b.pending <- columnID:
+	default:
+	}
+}
+
+// Run обрабатывает очередь до отмены ctx
+func (b *Rebalancer) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case columnID := <-b.pending:
+			if err := b.Rebalance(ctx, columnID); err != nil {
+				log.Printf("[WARN] rank: rebalance column %s: %v", columnID, err)
+			}
+		}
+	}
+}
+
+// Rebalance переписывает ранги видимых карточек колонки равномерно распределёнными ключами
+func (b *Rebalancer) Rebalance(ctx context.Context, columnID uuid.UUID) error {
+	tx, err := b.DB.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := lockColumn(ctx, tx, columnID); err != nil {
+		return err
+	}
+	rows, err := tx.Query(ctx, `SELECT id FROM tasks WHERE column_id = $1 AND `+rankedCards+` ORDER BY rank, id`, columnID)
+	if err != nil {
+		return err
+	}
+	ids, err := pgx.CollectRows(rows, pgx.RowTo[uuid.UUID])
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, `UPDATE tasks t SET rank = v.rank, version = t.version + 1
+                           FROM unnest($1::uuid[], $2::text[]) AS v(id, rank) WHERE t.id = v.id`,
+		ids, rank.Spread(len(ids)))
+	if err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
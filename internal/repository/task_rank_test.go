@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/TrueSmartcomm/backend/internal/models"
+	"github.com/TrueSmartcomm/backend/internal/rank"
+	"github.com/TrueSmartcomm/backend/internal/requser"
+	"github.com/google/uuid"
+)
+
+// Ребалансировка меняет rank в теле задачи, поэтому увеличивает её версию (ETag)
+func TestRebalanceBumpsVersion(t *testing.T) {
+	db := testDB(t)
+	ctx := context.Background()
+
+	board := testBoard(t, db, "board")
+	r := NewTaskRepository(db)
+	user := requser.WithUser(ctx, testUser(t, db, "owner"))
+	var tasks []*models.Task
+	for _, title := range []string{"a", "b"} {
+		task, err := r.GetTaskByID(ctx, testTask(t, user, r, board.ID, title).ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tasks = append(tasks, task)
+	}
+
+	if err := NewRebalancer(db).Rebalance(ctx, tasks[0].ColumnID); err != nil {
+		t.Fatal(err)
+	}
+	for _, before := range tasks {
+		after, err := r.GetTaskByID(ctx, before.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if after.Version != before.Version+1 {
+			t.Errorf("%s: version = %d, want %d", before.Title, after.Version, before.Version+1)
+		}
+	}
+}
+
+// Карточки в архиве и корзине не участвуют в порядке колонки: соседний ключ и выравнивание
+// считаются только по видимым карточкам, а скрытые сохраняют свои ключи и версии
+func TestRankIgnoresHiddenCards(t *testing.T) {
+	db := testDB(t)
+	ctx := context.Background()
+
+	board := testBoard(t, db, "board")
+	r := NewTaskRepository(db)
+	user := requser.WithUser(ctx, testUser(t, db, "owner"))
+	// новая карточка встаёт в начало: порядок top, archived, deleted, bottom
+	bottom := testTask(t, user, r, board.ID, "bottom")
+	deleted := testTask(t, user, r, board.ID, "deleted")
+	archived := testTask(t, user, r, board.ID, "archived")
+	top := testTask(t, user, r, board.ID, "top")
+	if _, err := r.ArchiveTasks(ctx, []uuid.UUID{archived.ID}); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.DeleteTask(ctx, deleted.ID, nil); err != nil {
+		t.Fatal(err)
+	}
+	hidden := map[uuid.UUID]*models.Task{}
+	for _, id := range []uuid.UUID{archived.ID, deleted.ID} {
+		var task models.Task
+		if err := scanTask(db.QueryRow(ctx, `SELECT `+taskColumns+` FROM tasks WHERE id = $1`, id), &task); err != nil {
+			t.Fatal(err)
+		}
+		hidden[id] = &task
+	}
+
+	tests := []struct {
+		name string
+		move models.TaskMove
+		want func() (string, error)
+	}{
+		{
+			name: "before the bottom card",
+			move: models.TaskMove{BeforeID: &bottom.ID},
+			want: func() (string, error) { return rank.Between("", bottom.Rank) },
+		},
+		{
+			name: "after the bottom card",
+			move: models.TaskMove{AfterID: &bottom.ID},
+			want: func() (string, error) { return rank.Between(bottom.Rank, "") },
+		},
+		{
+			name: "next to an archived card",
+			move: models.TaskMove{AfterID: &archived.ID},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			moved, err := r.MoveTaskToSpace(ctx, top.ID, tt.move, nil)
+			if tt.want == nil {
+				var validation *models.ValidationError
+				if !errors.As(err, &validation) {
+					t.Errorf("err = %v, want validation error", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			want, err := tt.want()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if moved.Rank != want {
+				t.Errorf("rank = %q, want %q", moved.Rank, want)
+			}
+		})
+	}
+
+	if err := NewRebalancer(db).Rebalance(ctx, bottom.ColumnID); err != nil {
+		t.Fatal(err)
+	}
+	for id, before := range hidden {
+		var key string
+		var version int64
+		if err := db.QueryRow(ctx, `SELECT rank, version FROM tasks WHERE id = $1`, id).Scan(&key, &version); err != nil {
+			t.Fatal(err)
+		}
+		if key != before.Rank || version != before.Version {
+			t.Errorf("%s: rank %q, version %d after rebalance, want %q, %d", before.Title, key, version, before.Rank, before.Version)
+		}
+	}
+}
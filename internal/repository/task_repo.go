@@ -3,254 +3,646 @@ package repository
 import (
 	"context"
 	"errors"
+	"slices"
+	"strings"
+	"time"
 
+	"github.com/TrueSmartcomm/backend/internal/access"
 	"github.com/TrueSmartcomm/backend/internal/models"
+	"github.com/TrueSmartcomm/backend/internal/requser"
+	"github.com/TrueSmartcomm/backend/internal/workflow"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+// taskColumns столбцы задачи в порядке, ожидаемом scanTask; владелец и исполнители
+// выбираются вместе с логином и email. Исполнители ищутся по tasks.id, поэтому таблица
+// в запросе не переименовывается.
+const taskColumns = `id, title, description, status, board_id, column_id, kanban_space, rank, owner_id, priority, due_date, estimate_hours, created_at, updated_at, version, deleted_at, completed_at, archived_at, ` +
+	`(SELECT jsonb_build_object('id', u.id, 'login', u.login, 'email', u.email) FROM users u WHERE u.id = owner_id), ` +
+	`(SELECT COALESCE(jsonb_agg(jsonb_build_object('id', u.id, 'login', u.login, 'email', u.email) ORDER BY u.id), '[]')
+	  FROM task_assignees a JOIN users u ON u.id = a.user_id WHERE a.task_id = tasks.id)`
+
+// scanTask читает строку, выбранную по taskColumns
+func scanTask(row pgx.Row, task *models.Task) error {
+	err := row.Scan(&task.ID, &task.Title, &task.Description, &task.Status, &task.BoardID, &task.ColumnID, &task.KanbanSpace, &task.Rank,
+		&task.OwnerID, &task.Priority, &task.DueDate, &task.EstimateHours, &task.CreatedAt, &task.UpdatedAt, &task.Version, &task.DeletedAt,
+		&task.CompletedAt, &task.ArchivedAt, &task.Owner, &task.Assignees)
+	if err != nil {
+		return err
+	}
+	task.AssigneeIDs = make([]int, 0, len(task.Assignees))
+	for _, u := range task.Assignees {
+		task.AssigneeIDs = append(task.AssigneeIDs, u.ID)
+	}
+	return nil
+}
+
+// canWrite проверяет право изменять задачу, которая была на доске before и оказывается
+// на доске after
+func (r *TaskRepository) canWrite(ctx context.Context, q access.Querier, before, after uuid.UUID) error {
+	if err := r.Access.Board(ctx, q, before, access.TasksWrite); err != nil {
+		return err
+	}
+	if after == before {
+		return nil
+	}
+	return r.Access.Board(ctx, q, after, access.TasksWrite)
+}
+
+// canWriteTasks проверяет право изменять задачи ids на каждой из их досок. Задачи, которых
+// нет, пропускаются, если пользователь читает все доски, иначе — отказ в доступе: так
+// по ответу нельзя узнать, существует ли чужая задача.
+func (r *TaskRepository) canWriteTasks(ctx context.Context, q access.Querier, ids []uuid.UUID) error {
+	if r.Access == nil {
+		return nil
+	}
+	rows, err := q.Query(ctx, `SELECT id, board_id FROM tasks WHERE id = ANY($1)`, ids)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	found := map[uuid.UUID]uuid.UUID{}
+	for rows.Next() {
+		var id, boardID uuid.UUID
+		if err := rows.Scan(&id, &boardID); err != nil {
+			return err
+		}
+		found[id] = boardID
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	checked := map[uuid.UUID]bool{}
+	for _, id := range ids {
+		boardID, ok := found[id]
+		if !ok {
+			if err := r.Access.Workspace(ctx, q, access.TasksRead); err != nil {
+				return err
+			}
+			continue
+		}
+		if checked[boardID] {
+			continue
+		}
+		checked[boardID] = true
+		if err := r.Access.Board(ctx, q, boardID, access.TasksWrite); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// hideMissing возвращает notFound только тому, кто может читать задачу id: её доску, если
+// задача есть (в том числе в корзине), иначе все доски рабочего пространства. Остальные
+// получают отказ в доступе, как для чужой задачи.
+func (r *TaskRepository) hideMissing(ctx context.Context, q access.Querier, id uuid.UUID, notFound error) error {
+	var boardID uuid.UUID
+	err := q.QueryRow(ctx, `SELECT board_id FROM tasks WHERE id = $1`, id).Scan(&boardID)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		err = r.Access.Workspace(ctx, q, access.TasksRead)
+	case err == nil:
+		err = r.Access.Board(ctx, q, boardID, access.TasksRead)
+	}
+	if err != nil {
+		return err
+	}
+	return notFound
+}
+
+// versionMatches проверяет версию задачи по списку из If-Match
+func versionMatches(ifMatch []int64, version int64) bool {
+	if ifMatch == nil {
+		return true
+	}
+	for _, v := range ifMatch {
+		if v == version {
+			return true
+		}
+	}
+	return false
+}
+
+// lockTask читает задачу не из корзины под блокировкой строки
+func lockTask(ctx context.Context, tx pgx.Tx, id uuid.UUID) (*models.Task, error) {
+	var task models.Task
+	err := scanTask(tx.QueryRow(ctx, `SELECT `+taskColumns+` FROM tasks WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`, id), &task)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errTaskNotFound
+		}
+		return nil, err
+	}
+	return &task, nil
+}
+
+// lockWritable блокирует задачу, проверяет право tasks:write на её доске и только потом
+// версию по If-Match: текущее состояние в *models.VersionConflictError получает лишь тот,
+// кому можно изменять задачу.
+func (r *TaskRepository) lockWritable(ctx context.Context, tx pgx.Tx, id uuid.UUID, ifMatch []int64) (*models.Task, error) {
+	task, err := lockTask(ctx, tx, id)
+	if errors.Is(err, errTaskNotFound) {
+		return nil, r.hideMissing(ctx, tx, id, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := r.Access.Board(ctx, tx, task.BoardID, access.TasksWrite); err != nil {
+		return nil, err
+	}
+	if !versionMatches(ifMatch, task.Version) {
+		return nil, &models.VersionConflictError{Current: task}
+	}
+	return task, nil
+}
+
+// commitTransition выполняет последствия перехода по правилам workflow, фиксирует
+// транзакцию и только после этого рассылает события
+func (r *TaskRepository) commitTransition(ctx context.Context, tx pgx.Tx, t workflow.Transition) error {
+	events, err := r.Workflow.After(ctx, tx, t)
+	if err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+	r.Workflow.Notify(ctx, events)
+	return nil
+}
+
 type TaskRepository struct {
 	DB *pgxpool.Pool
+	// Workflow правила переходов задач; nil — без ограничений
+	Workflow *workflow.Engine
+	// Rebalancer выравнивает ранги карточек в фоне; nil — не выравнивать
+	Rebalancer *Rebalancer
+	// Access права пользователя из контекста на доски задач; nil — права не проверяются
+	Access *access.Checker
 }
 
 func NewTaskRepository(db *pgxpool.Pool) *TaskRepository {
 	return &TaskRepository{DB: db}
 }
 
-// CreateTask создает новую задачу
+// CreateTask создает новую задачу; владелец — пользователь из контекста (requser.WithUser).
+// Владелец и исполнители становятся наблюдателями задачи. Без доски задача попадает на доску по умолчанию,
+// без колонки — в первую колонку доски, без статуса — в статус колонки или первый статус доски.
+// Карточка встаёт в начало колонки, если это допускают WIP-лимиты колонки.
 func (r *TaskRepository) CreateTask(ctx context.Context, task *models.Task) error {
 	if task.ID == uuid.Nil {
 		task.ID = uuid.New()
 	}
 
 	// Устанавливаем дефолтные значения
-	if task.Status == "" {
-		task.Status = models.StatusTodo
-	}
-	if task.KanbanSpace == "" {
-		task.KanbanSpace = models.SpaceBacklog
-	}
 	if task.Priority == "" {
 		task.Priority = models.PriorityMedium
 	}
+	if userID, ok := requser.FromContext(ctx); ok {
+		task.OwnerID = &userID
+	}
+	if task.OwnerID == nil {
+		return &models.ValidationError{Field: "owner", Message: "owner is required"}
+	}
+	task.SetAssignees(task.AssigneeIDs)
+
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if task.BoardID == uuid.Nil {
+		if err := defaultBoard(ctx, tx, task); err != nil {
+			return err
+		}
+	}
+	// права проверяются до поиска колонки и статуса: ошибка валидации не должна
+	// сообщать, есть ли недоступная доска или колонка на ней
+	if err := r.Access.Board(ctx, tx, task.BoardID, access.TasksWrite); err != nil {
+		return err
+	}
+	if err := placeTask(ctx, tx, task, ""); err != nil {
+		return err
+	}
+	if err := task.Validate(); err != nil {
+		return err
+	}
+	if err := checkWIP(ctx, tx, task, nil); err != nil {
+		return err
+	}
+	if task.Rank, err = topRank(ctx, tx, task.ColumnID); err != nil {
+		return err
+	}
 
-	query := `INSERT INTO tasks (id, title, description, status, kanban_space, owner, assigned_to, priority, due_date, created_at, updated_at) 
-              VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, now(), now()) RETURNING created_at, updated_at`
+	query := `INSERT INTO tasks (id, title, description, status, board_id, column_id, kanban_space, rank, owner_id, priority, due_date, estimate_hours, created_at, updated_at) 
+              VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, now(), now())`
 
-	err := r.DB.QueryRow(ctx, query,
-		task.ID, task.Title, task.Description, task.Status, task.KanbanSpace,
-		task.Owner, task.AssignedTo, task.Priority, task.DueDate).
-		Scan(&task.CreatedAt, &task.UpdatedAt)
+	_, err = tx.Exec(ctx, query,
+		task.ID, task.Title, task.Description, task.Status, task.BoardID, task.ColumnID, task.KanbanSpace, task.Rank,
+		task.OwnerID, task.Priority, task.DueDate, task.EstimateHours)
 
 	if err != nil {
+		return mapTaskError(err)
+	}
+	if err := watch(ctx, tx, task.ID, []int{*task.OwnerID}); err != nil {
+		return mapTaskError(err)
+	}
+	if err := replaceAssignees(ctx, tx, task.ID, task.AssigneeIDs); err != nil {
+		return mapTaskError(err)
+	}
+	// исполнители видны в taskColumns только после вставки в task_assignees
+	if err := scanTask(tx.QueryRow(ctx, `SELECT `+taskColumns+` FROM tasks WHERE id = $1`, task.ID), task); err != nil {
+		return err
+	}
+	if err := workflow.TrackCompletion(ctx, tx, task); err != nil {
+		return err
+	}
+	if err := recordHistory(ctx, tx, task.ID, models.HistoryCreated, models.DiffTasks(nil, task)); err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
 		return err
 	}
 
+	r.Rebalancer.Schedule(task.ColumnID, task.Rank)
 	return nil
 }
 
-// GetTaskByID получает задачу по ID
+// GetTaskByID получает задачу по ID; задача в корзине не находится. Отсутствие задачи
+// видит только тот, кто мог бы её читать (hideMissing).
 func (r *TaskRepository) GetTaskByID(ctx context.Context, id uuid.UUID) (*models.Task, error) {
 	var task models.Task
-	query := `SELECT id, title, description, status, kanban_space, owner, assigned_to, priority, due_date, created_at, updated_at 
-              FROM tasks WHERE id = $1`
+	query := `SELECT ` + taskColumns + ` FROM tasks WHERE id = $1 AND deleted_at IS NULL`
 
-	err := r.DB.QueryRow(ctx, query, id).
-		Scan(&task.ID, &task.Title, &task.Description, &task.Status, &task.KanbanSpace,
-			&task.Owner, &task.AssignedTo, &task.Priority, &task.DueDate, &task.CreatedAt, &task.UpdatedAt)
+	err := scanTask(r.DB.QueryRow(ctx, query, id), &task)
 
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, errors.New("task not found")
+			return nil, r.hideMissing(ctx, r.DB, id, errTaskNotFound)
 		}
 		return nil, err
 	}
+	if err := r.Access.Board(ctx, r.DB, task.BoardID, access.TasksRead); err != nil {
+		return nil, err
+	}
 
 	return &task, nil
 }
 
-// UpdateTask обновляет задачу
-func (r *TaskRepository) UpdateTask(ctx context.Context, task *models.Task) error {
-	query := `UPDATE tasks SET title=$1, description=$2, status=$3, kanban_space=$4, owner=$5, 
-              assigned_to=$6, priority=$7, due_date=$8, updated_at=now() 
-              WHERE id=$9 RETURNING updated_at`
+// UpdateTask обновляет задачу целиком. Если задан ifMatch, обновление выполняется
+// только при совпадении версии, иначе возвращается *models.VersionConflictError.
+// Без board_id задача остаётся на своей доске, а без колонки — в своей колонке
+// (или в начале первой колонки новой доски), а без статуса — в своём статусе, если колонка
+// не требует другого. Смена статуса проверяется правилами Workflow, а смена колонки
+// или исполнителей — WIP-лимитами колонки. Исполнители заменяются task.AssigneeIDs.
+func (r *TaskRepository) UpdateTask(ctx context.Context, task *models.Task, ifMatch []int64) error {
+	if task.Priority == "" {
+		task.Priority = models.PriorityMedium
+	}
+	task.SetAssignees(task.AssigneeIDs)
 
-	err := r.DB.QueryRow(ctx, query,
-		task.Title, task.Description, task.Status, task.KanbanSpace,
-		task.Owner, task.AssignedTo, task.Priority, task.DueDate, task.ID).
-		Scan(&task.UpdatedAt)
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
 
+	before, err := r.lockWritable(ctx, tx, task.ID, ifMatch)
 	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return errors.New("task not found")
-		}
+		return err
+	}
+	if before.ArchivedAt != nil {
+		return errTaskArchived
+	}
+	if task.BoardID == uuid.Nil {
+		task.BoardID = before.BoardID
+	}
+	if task.BoardID == before.BoardID && task.ColumnID == uuid.Nil && task.KanbanSpace == "" {
+		task.ColumnID = before.ColumnID
+	}
+	// права на новую доску проверяются до поиска колонки и статуса на ней
+	if err := r.canWrite(ctx, tx, before.BoardID, task.BoardID); err != nil {
+		return err
+	}
+	if err := placeTask(ctx, tx, task, before.Status); err != nil {
+		return err
+	}
+	if err := task.Validate(); err != nil {
+		return err
+	}
+	if err := lockColumn(ctx, tx, task.ColumnID); err != nil {
+		return err
+	}
+	if err := checkWIP(ctx, tx, task, before); err != nil {
+		return err
+	}
+	task.Rank = before.Rank
+	if err := rankTask(ctx, tx, task, before.ColumnID, nil, nil); err != nil {
+		return err
+	}
+	t := workflow.Transition{Before: before, After: task}
+	if err := r.Workflow.Check(ctx, tx, t); err != nil {
 		return err
 	}
 
-	return nil
-}
+	if err := replaceAssignees(ctx, tx, task.ID, task.AssigneeIDs); err != nil {
+		return mapTaskError(err)
+	}
+	query := `UPDATE tasks SET title=$1, description=$2, status=$3, board_id=$4, column_id=$5, kanban_space=$6, rank=$7, 
+              priority=$8, due_date=$9, estimate_hours=$10, updated_at=now(), version=version+1 
+              WHERE id=$11 RETURNING ` + taskColumns
 
-// DeleteTask удаляет задачу
-func (r *TaskRepository) DeleteTask(ctx context.Context, id uuid.UUID) error {
-	result, err := r.DB.Exec(ctx, `DELETE FROM tasks WHERE id=$1`, id)
+	err = scanTask(tx.QueryRow(ctx, query,
+		task.Title, task.Description, task.Status, task.BoardID, task.ColumnID, task.KanbanSpace, task.Rank,
+		task.Priority, task.DueDate, task.EstimateHours, task.ID), task)
 	if err != nil {
+		return mapTaskError(err)
+	}
+	if err := workflow.TrackCompletion(ctx, tx, task); err != nil {
 		return err
 	}
-
-	if result.RowsAffected() == 0 {
-		return errors.New("task not found")
+	if err := recordHistory(ctx, tx, task.ID, models.HistoryUpdated, models.DiffTasks(before, task)); err != nil {
+		return err
 	}
 
+	if err := r.commitTransition(ctx, tx, t); err != nil {
+		return err
+	}
+	r.Rebalancer.Schedule(task.ColumnID, task.Rank)
 	return nil
 }
 
-// GetAllTasks получает все задачи (по фильтрам)
-func (r *TaskRepository) GetAllTasks(ctx context.Context, space string, status string) ([]models.Task, error) {
-	var query string
-	var args []interface{}
-
-	if space != "" && status != "" {
-		query = `SELECT id, title, description, status, kanban_space, owner, assigned_to, priority, due_date, created_at, updated_at 
-                 FROM tasks WHERE kanban_space=$1 AND status=$2 ORDER BY created_at DESC`
-		args = []interface{}{space, status}
-	} else if space != "" {
-		query = `SELECT id, title, description, status, kanban_space, owner, assigned_to, priority, due_date, created_at, updated_at 
-                 FROM tasks WHERE kanban_space=$1 ORDER BY created_at DESC`
-		args = []interface{}{space}
-	} else if status != "" {
-		query = `SELECT id, title, description, status, kanban_space, owner, assigned_to, priority, due_date, created_at, updated_at 
-                 FROM tasks WHERE status=$1 ORDER BY created_at DESC`
-		args = []interface{}{status}
-	} else {
-		query = `SELECT id, title, description, status, kanban_space, owner, assigned_to, priority, due_date, created_at, updated_at 
-                 FROM tasks ORDER BY created_at DESC`
-	}
-
-	rows, err := r.DB.Query(ctx, query, args...)
+// PatchTask частично обновляет задачу: меняются только переданные в патче поля.
+// Патч применяется к текущему состоянию под блокировкой строки, результат проверяется
+// через Validate, а UPDATE затрагивает только действительно изменённые столбцы.
+// ifMatch проверяется по версии заблокированной строки, смена колонки или исполнителей —
+// WIP-лимитами колонки.
+func (r *TaskRepository) PatchTask(ctx context.Context, id uuid.UUID, patch *models.TaskPatch, ifMatch []int64) (*models.Task, error) {
+	tx, err := r.DB.Begin(ctx)
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
+	defer tx.Rollback(ctx)
 
-	var tasks []models.Task
-	for rows.Next() {
-		var task models.Task
-		err := rows.Scan(&task.ID, &task.Title, &task.Description, &task.Status, &task.KanbanSpace,
-			&task.Owner, &task.AssignedTo, &task.Priority, &task.DueDate, &task.CreatedAt, &task.UpdatedAt)
-		if err != nil {
+	before, err := r.lockWritable(ctx, tx, id, ifMatch)
+	if err != nil {
+		return nil, err
+	}
+	if before.ArchivedAt != nil {
+		return nil, errTaskArchived
+	}
+
+	task := *before
+	changed := patch.Apply(&task)
+	if slices.Contains(changed, "column_id") || slices.Contains(changed, "status") {
+		// без status в патче задача сохраняет статус, если новая колонка не требует другого
+		fallback := ""
+		if !patch.Status.Set {
+			task.Status, fallback = "", before.Status
+		}
+		if err := r.canWrite(ctx, tx, before.BoardID, task.BoardID); err != nil {
+			return nil, err
+		}
+		if err := placeTask(ctx, tx, &task, fallback); err != nil {
+			return nil, err
+		}
+		if task.Status != before.Status && !slices.Contains(changed, "status") {
+			changed = append(changed, "status")
+		}
+		// карточка, сменившая колонку, встаёт в её начало
+		if err := lockColumn(ctx, tx, task.ColumnID); err != nil {
+			return nil, err
+		}
+		if err := rankTask(ctx, tx, &task, before.ColumnID, nil, nil); err != nil {
 			return nil, err
 		}
-		tasks = append(tasks, task)
+		if task.Rank != before.Rank {
+			changed = append(changed, "rank")
+		}
+	}
+	if err := task.Validate(); err != nil {
+		return nil, err
+	}
+	if err := checkWIP(ctx, tx, &task, before); err != nil {
+		return nil, err
+	}
+	if len(changed) == 0 {
+		return &task, tx.Commit(ctx)
+	}
+	t := workflow.Transition{Before: before, After: &task}
+	if err := r.Workflow.Check(ctx, tx, t); err != nil {
+		return nil, err
+	}
+
+	values := map[string]interface{}{
+		"title":          task.Title,
+		"description":    task.Description,
+		"status":         task.Status,
+		"board_id":       task.BoardID,
+		"column_id":      task.ColumnID,
+		"kanban_space":   task.KanbanSpace,
+		"rank":           task.Rank,
+		"priority":       task.Priority,
+		"due_date":       task.DueDate,
+		"estimate_hours": task.EstimateHours,
+	}
+	p := &predicates{}
+	sets := make([]string, 0, len(changed)+1)
+	for _, col := range changed {
+		if col == "assignees" {
+			if err := replaceAssignees(ctx, tx, id, task.AssigneeIDs); err != nil {
+				return nil, mapTaskError(err)
+			}
+			continue
+		}
+		sets = append(sets, col+" = "+p.arg(values[col]))
 	}
+	sets = append(sets, "updated_at = now()", "version = version + 1")
 
-	if err = rows.Err(); err != nil {
+	query := `UPDATE tasks SET ` + strings.Join(sets, ", ") + ` WHERE id = ` + p.arg(id) + ` RETURNING ` + taskColumns
+	if err := scanTask(tx.QueryRow(ctx, query, p.args...), &task); err != nil {
+		return nil, mapTaskError(err)
+	}
+	if err := workflow.TrackCompletion(ctx, tx, &task); err != nil {
+		return nil, err
+	}
+	if err := recordHistory(ctx, tx, id, models.HistoryUpdated, models.DiffTasks(before, &task)); err != nil {
 		return nil, err
 	}
 
-	return tasks, nil
+	if err := r.commitTransition(ctx, tx, t); err != nil {
+		return nil, err
+	}
+	r.Rebalancer.Schedule(task.ColumnID, task.Rank)
+	return &task, nil
 }
 
-// MoveTaskToSpace перемещает задачу в другое Kanban-пространство
-func (r *TaskRepository) MoveTaskToSpace(ctx context.Context, id uuid.UUID, space string, status string) error {
-	query := `UPDATE tasks SET kanban_space=$1, status=$2, updated_at=now() WHERE id=$3`
-
-	result, err := r.DB.Exec(ctx, query, space, status, id)
+// DeleteTask переносит задачу в корзину (с проверкой версии, если задан ifMatch).
+// Связи задачи сохраняются, но не видны, пока задача не восстановлена (RestoreTask).
+func (r *TaskRepository) DeleteTask(ctx context.Context, id uuid.UUID, ifMatch []int64) error {
+	tx, err := r.DB.Begin(ctx)
 	if err != nil {
 		return err
 	}
+	defer tx.Rollback(ctx)
 
-	if result.RowsAffected() == 0 {
-		return errors.New("task not found")
+	if _, err := r.lockWritable(ctx, tx, id, ifMatch); err != nil {
+		return err
 	}
-
-	return nil
-}
-
-// AddDependency добавляет связь между задачами
-func (r *TaskRepository) AddDependency(ctx context.Context, taskID, dependentTaskID uuid.UUID) error {
-	// Проверяем, что задачи не одинаковые
-	if taskID == dependentTaskID {
-		return errors.New("task cannot depend on itself")
+	var deletedAt time.Time
+	err = tx.QueryRow(ctx, `UPDATE tasks SET deleted_at = now(), version = version + 1 WHERE id = $1 RETURNING deleted_at`, id).
+		Scan(&deletedAt)
+	if err != nil {
+		return err
 	}
 
-	query := `INSERT INTO task_dependencies (task_id, dependent_task_id) 
-              VALUES ($1, $2) 
-              ON CONFLICT (task_id, dependent_task_id) DO NOTHING`
-
-	_, err := r.DB.Exec(ctx, query, taskID, dependentTaskID)
-	return err
-}
-
-// RemoveDependency удаляет связь между задачами
-func (r *TaskRepository) RemoveDependency(ctx context.Context, taskID, dependentTaskID uuid.UUID) error {
-	query := `DELETE FROM task_dependencies WHERE task_id = $1 AND dependent_task_id = $2`
-	_, err := r.DB.Exec(ctx, query, taskID, dependentTaskID)
-	return err
+	change := models.FieldChange{Field: "deleted_at", New: deletedAt}
+	if err := recordHistory(ctx, tx, id, models.HistoryDeleted, []models.FieldChange{change}); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
 }
 
-// GetSubTasks получает все подзадачи для задачи
-func (r *TaskRepository) GetSubTasks(ctx context.Context, taskID uuid.UUID) ([]uuid.UUID, error) {
-	query := `SELECT dependent_task_id FROM task_dependencies WHERE task_id = $1`
-	rows, err := r.DB.Query(ctx, query, taskID)
+// MoveTaskToSpace перемещает карточку в колонку её доски и на место в колонке
+// и возвращает новое состояние задачи. Колонка задаётся move.ColumnID или ключом move.Space,
+// а без них — колонкой соседней карточки. Пустой move.Status — статус, к которому привязана
+// колонка, иначе текущий. Переход проверяется правилами Workflow, а вход в новую колонку —
+// её WIP-лимитами.
+func (r *TaskRepository) MoveTaskToSpace(ctx context.Context, id uuid.UUID, move models.TaskMove, ifMatch []int64) (*models.Task, error) {
+	tx, err := r.DB.Begin(ctx)
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
+	defer tx.Rollback(ctx)
 
-	var subTasks []uuid.UUID
-	for rows.Next() {
-		var subTaskID uuid.UUID
-		if err := rows.Scan(&subTaskID); err != nil {
+	before, err := r.lockWritable(ctx, tx, id, ifMatch)
+	if err != nil {
+		return nil, err
+	}
+	if before.ArchivedAt != nil {
+		return nil, errTaskArchived
+	}
+	task := *before
+	task.ColumnID, task.KanbanSpace, task.Status = uuid.Nil, move.Space, move.Status
+	switch {
+	case move.ColumnID != nil:
+		task.ColumnID = *move.ColumnID
+	case move.Space == "":
+		ref, field := move.AfterID, "after_id"
+		if ref == nil {
+			ref, field = move.BeforeID, "before_id"
+		}
+		err := tx.QueryRow(ctx, `SELECT column_id FROM tasks WHERE id = $1 AND deleted_at IS NULL AND archived_at IS NULL`, *ref).Scan(&task.ColumnID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, &models.ValidationError{Field: field, Message: "task does not exist"}
+		}
+		if err != nil {
 			return nil, err
 		}
-		subTasks = append(subTasks, subTaskID)
 	}
-
-	return subTasks, rows.Err()
-}
-
-// GetParentTasks получает все родительские задачи
-func (r *TaskRepository) GetParentTasks(ctx context.Context, taskID uuid.UUID) ([]uuid.UUID, error) {
-	query := `SELECT task_id FROM task_dependencies WHERE dependent_task_id = $1`
-	rows, err := r.DB.Query(ctx, query, taskID)
-	if err != nil {
+	if err := placeTask(ctx, tx, &task, before.Status); err != nil {
+		return nil, err
+	}
+	if err := lockColumn(ctx, tx, task.ColumnID); err != nil {
+		return nil, err
+	}
+	if err := checkWIP(ctx, tx, &task, before); err != nil {
+		return nil, err
+	}
+	if err := rankTask(ctx, tx, &task, before.ColumnID, move.AfterID, move.BeforeID); err != nil {
+		return nil, err
+	}
+	t := workflow.Transition{Before: before, After: &task}
+	if err := r.Workflow.Check(ctx, tx, t); err != nil {
 		return nil, err
 	}
-	defer rows.Close()
 
-	var parentTasks []uuid.UUID
-	for rows.Next() {
-		var parentTaskID uuid.UUID
-		if err := rows.Scan(&parentTaskID); err != nil {
-			return nil, err
-		}
-		parentTasks = append(parentTasks, parentTaskID)
+	query := `UPDATE tasks SET column_id=$1, kanban_space=$2, status=$3, rank=$4, updated_at=now(), version=version+1
+              WHERE id=$5 RETURNING ` + taskColumns
+	if err := scanTask(tx.QueryRow(ctx, query, task.ColumnID, task.KanbanSpace, task.Status, task.Rank, id), &task); err != nil {
+		return nil, mapTaskError(err)
+	}
+	if err := workflow.TrackCompletion(ctx, tx, &task); err != nil {
+		return nil, err
+	}
+	if err := recordHistory(ctx, tx, id, models.HistoryMoved, models.DiffTasks(before, &task)); err != nil {
+		return nil, err
 	}
 
-	return parentTasks, rows.Err()
+	if err := r.commitTransition(ctx, tx, t); err != nil {
+		return nil, err
+	}
+	r.Rebalancer.Schedule(task.ColumnID, task.Rank)
+	return &task, nil
 }
 
-// GetTaskByIDWithDependencies получает задачу с зависимостями
-func (r *TaskRepository) GetTaskByIDWithDependencies(ctx context.Context, id uuid.UUID) (*models.Task, error) {
-	task, err := r.GetTaskByID(ctx, id)
+// AdvanceTask переводит задачу id в первый завершающий статус её доски (workflow.Advancer):
+// родитель, у которого завершились все подзадачи, проверяется правилами Workflow
+// и WIP-лимитами колонки и попадает в историю так же, как при ручном перемещении. Колонка — привязанная к статусу,
+// иначе колонка с ключом статуса, иначе текущая, если она не привязана к другому статусу;
+// в новой колонке карточка встаёт в начало. false — подходящей колонки на доске нет.
+func (r *TaskRepository) AdvanceTask(ctx context.Context, tx pgx.Tx, id uuid.UUID) (bool, error) {
+	before, err := lockTask(ctx, tx, id)
 	if err != nil {
-		return nil, err
+		return false, err
+	}
+	task := *before
+	err = tx.QueryRow(ctx, `SELECT key FROM board_statuses WHERE board_id = $1 AND final
+                            ORDER BY position LIMIT 1`, task.BoardID).Scan(&task.Status)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
 	}
-
-	// Получаем подзадачи
-	subTasks, err := r.GetSubTasks(ctx, id)
 	if err != nil {
-		return nil, err
+		return false, err
+	}
+	err = tx.QueryRow(ctx, `SELECT id, key FROM board_columns
+                            WHERE board_id = $1 AND (status = $2 OR (status IS NULL AND (key = $2 OR id = $3)))
+                            ORDER BY status IS NULL, key = $2 DESC, position LIMIT 1`,
+		task.BoardID, task.Status, task.ColumnID).Scan(&task.ColumnID, &task.KanbanSpace)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
 	}
-	task.SubTasks = subTasks
+	if err != nil {
+		return false, err
+	}
+	if err := lockColumn(ctx, tx, task.ColumnID); err != nil {
+		return false, err
+	}
+	if err := checkWIP(ctx, tx, &task, before); err != nil {
+		return false, err
+	}
+	if err := rankTask(ctx, tx, &task, before.ColumnID, nil, nil); err != nil {
+		return false, err
+	}
+	if err := r.Workflow.Check(ctx, tx, workflow.Transition{Before: before, After: &task}); err != nil {
+		return false, err
+	}
+
+	query := `UPDATE tasks SET column_id=$1, kanban_space=$2, status=$3, rank=$4, updated_at=now(), version=version+1
+              WHERE id=$5 RETURNING ` + taskColumns
+	if err := scanTask(tx.QueryRow(ctx, query, task.ColumnID, task.KanbanSpace, task.Status, task.Rank, id), &task); err != nil {
+		return false, mapTaskError(err)
+	}
+	if err := workflow.TrackCompletion(ctx, tx, &task); err != nil {
+		return false, err
+	}
+	if err := recordHistory(ctx, tx, id, models.HistoryMoved, models.DiffTasks(before, &task)); err != nil {
+		return false, err
+	}
+	// Колонка заблокирована до фиксации транзакции, поэтому выравнивание увидит новый ранг
+	r.Rebalancer.Schedule(task.ColumnID, task.Rank)
+	return true, nil
+}
 
-	// Получаем родительские задачи
-	parentTasks, err := r.GetParentTasks(ctx, id)
+// GetTaskTransitions возвращает переходы, доступные задаче из её текущего статуса.
+// Роли проверяются для пользователя из контекста (requser.WithUser).
+func (r *TaskRepository) GetTaskTransitions(ctx context.Context, id uuid.UUID) ([]models.TaskTransition, error) {
+	task, err := r.GetTaskByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
-	task.ParentTasks = parentTasks
-
-	return task, nil
+	return workflow.Available(ctx, r.DB, task)
 }
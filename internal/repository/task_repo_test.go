@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/TrueSmartcomm/backend/internal/access"
+	"github.com/TrueSmartcomm/backend/internal/models"
+	"github.com/TrueSmartcomm/backend/internal/requser"
+	"github.com/google/uuid"
+)
+
+// Без права на доску создание и перенос задачи отвечают отказом в доступе,
+// а не ошибкой валидации, по которой видно, есть ли доска или колонка
+func TestCreateTaskChecksAccessBeforePlacement(t *testing.T) {
+	db := testDB(t)
+	ctx := context.Background()
+
+	userID := testUser(t, db, "member")
+	own := testBoard(t, db, "own")
+	foreign := testBoard(t, db, "foreign")
+	if _, err := NewMemberRepository(db).SetMember(ctx, &own.ID, userID, access.RoleMember); err != nil {
+		t.Fatal(err)
+	}
+	r := NewTaskRepository(db)
+	r.Access = access.New()
+	user := requser.WithUser(ctx, userID)
+	task := testTask(t, user, r, own.ID, "mine")
+
+	tests := []struct {
+		name    string
+		boardID uuid.UUID
+		space   string
+	}{
+		{name: "foreign board, missing column", boardID: foreign.ID, space: "nope"},
+		{name: "foreign board, existing column", boardID: foreign.ID, space: foreign.Columns[0].Key},
+		{name: "missing board", boardID: uuid.New()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var permErr *access.PermissionError
+			err := r.CreateTask(user, &models.Task{Title: "x", BoardID: tt.boardID, KanbanSpace: tt.space})
+			if !errors.As(err, &permErr) {
+				t.Errorf("create: err = %v, want permission error", err)
+			}
+			moved := *task
+			moved.BoardID, moved.ColumnID, moved.KanbanSpace = tt.boardID, uuid.Nil, tt.space
+			if err := r.UpdateTask(user, &moved, nil); !errors.As(err, &permErr) {
+				t.Errorf("update: err = %v, want permission error", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,171 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/TrueSmartcomm/backend/internal/access"
+	"github.com/TrueSmartcomm/backend/internal/apperror"
+	"github.com/TrueSmartcomm/backend/internal/models"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// errNotInTrash задачи с указанным id нет в корзине
+var errNotInTrash = apperror.NotFound(apperror.CodeTaskNotFound, "task not found in trash")
+
+// purgeInterval как часто TrashPurger проверяет корзину
+const purgeInterval = time.Hour
+
+// ListTrash возвращает страницу задач в корзине, последние удалённые первыми.
+// boardID ограничивает выборку задачами одной доски.
+func (r *TaskRepository) ListTrash(ctx context.Context, boardID *uuid.UUID, limit, offset int) (*TaskPage, error) {
+	if limit <= 0 || limit > models.MaxTaskListLimit {
+		limit = models.DefaultTaskListLimit
+	}
+	p := &predicates{}
+	p.add("deleted_at IS NOT NULL")
+	if boardID != nil {
+		p.add("board_id = ?", *boardID)
+	}
+	if err := readableBoards(ctx, r.Access, r.DB, p, boardID); err != nil {
+		return nil, err
+	}
+
+	page := &TaskPage{Tasks: []models.Task{}}
+	if err := r.DB.QueryRow(ctx, `SELECT count(*) FROM tasks`+p.where(), p.args...).Scan(&page.Total); err != nil {
+		return nil, err
+	}
+
+	query := `SELECT ` + taskColumns + ` FROM tasks` + p.where() +
+		` ORDER BY deleted_at DESC, id LIMIT ` + p.arg(limit) + ` OFFSET ` + p.arg(offset)
+	rows, err := r.DB.Query(ctx, query, p.args...)
+	if err != nil {
+		return nil, err
+	}
+	tasks, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (models.Task, error) {
+		var task models.Task
+		err := scanTask(row, &task)
+		return task, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if tasks != nil {
+		page.Tasks = tasks
+	}
+	return page, nil
+}
+
+// RestoreTask возвращает задачу из корзины на прежнее место в колонке. Связи задачи
+// не удалялись и снова становятся видимыми. Вход в колонку проверяется её WIP-лимитами.
+func (r *TaskRepository) RestoreTask(ctx context.Context, id uuid.UUID) (*models.Task, error) {
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	var task models.Task
+	err = scanTask(tx.QueryRow(ctx, `SELECT `+taskColumns+` FROM tasks WHERE id = $1 AND deleted_at IS NOT NULL FOR UPDATE`, id), &task)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, r.hideMissing(ctx, tx, id, errNotInTrash)
+		}
+		return nil, err
+	}
+	if err := r.Access.Board(ctx, tx, task.BoardID, access.TasksWrite); err != nil {
+		return nil, err
+	}
+	deletedAt := *task.DeletedAt
+
+	// архивная задача вернётся в архив и в WIP-лимитах колонки не участвует
+	if task.ArchivedAt == nil {
+		if err := checkWIP(ctx, tx, &task, nil); err != nil {
+			return nil, err
+		}
+	}
+	warnings := task.Warnings
+
+	query := `UPDATE tasks SET deleted_at = NULL, updated_at = now(), version = version + 1
+              WHERE id = $1 RETURNING ` + taskColumns
+	if err := scanTask(tx.QueryRow(ctx, query, id), &task); err != nil {
+		return nil, err
+	}
+	task.Warnings = warnings
+
+	change := models.FieldChange{Field: "deleted_at", Old: deletedAt}
+	if err := recordHistory(ctx, tx, id, models.HistoryRestored, []models.FieldChange{change}); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+// TrashPurger окончательно удаляет задачи, пролежавшие в корзине дольше Retention.
+// Связи удаляются каскадно, история задачи сохраняется.
+type TrashPurger struct {
+	DB        *pgxpool.Pool
+	Retention time.Duration
+}
+
+func NewTrashPurger(db *pgxpool.Pool, retention time.Duration) *TrashPurger {
+	return &TrashPurger{DB: db, Retention: retention}
+}
+
+// Run очищает корзину при запуске и затем раз в purgeInterval до отмены ctx
+func (p *TrashPurger) Run(ctx context.Context) {
+	ticker := time.NewTicker(purgeInterval)
+	defer ticker.Stop()
+	for {
+		if n, err := p.Purge(ctx); err != nil {
+			log.Printf("[WARN] trash: purge: %v", err)
+		} else if n > 0 {
+			log.Printf("trash: purged %d tasks", n)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Purge удаляет задачи с истёкшим сроком хранения и возвращает их количество.
+// В историю каждой задачи записываются её последние значения.
+func (p *TrashPurger) Purge(ctx context.Context) (int, error) {
+	tx, err := p.DB.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx, `DELETE FROM tasks WHERE deleted_at < now() - $1 * interval '1 second'
+                                RETURNING `+taskColumns, p.Retention.Seconds())
+	if err != nil {
+		return 0, err
+	}
+	tasks, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (models.Task, error) {
+		var task models.Task
+		err := scanTask(row, &task)
+		return task, err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	for i := range tasks {
+		if err := recordHistory(ctx, tx, tasks[i].ID, models.HistoryPurged, models.DiffTasks(&tasks[i], nil)); err != nil {
+			return 0, err
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+	return len(tasks), nil
+}
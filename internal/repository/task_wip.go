@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"slices"
+
+	"github.com/TrueSmartcomm/backend/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+// checkWIP проверяет WIP-лимиты колонки, в которую входит задача (before — её прежнее
+// состояние, nil для новой задачи); лимит на исполнителя проверяется для каждого исполнителя,
+// а внутри колонки — для добавленных исполнителей. Колонка блокируется до конца транзакции,
+// поэтому параллельные перемещения в неё считают задачи по очереди и не могут вместе
+// превысить лимит. Превышение hard-лимита — *models.WIPLimitError, soft-лимита —
+// предупреждение в task.Warnings.
+func checkWIP(ctx context.Context, tx pgx.Tx, task, before *models.Task) error {
+	enters := before == nil || before.ColumnID != task.ColumnID
+	assignees := task.AssigneeIDs
+	if !enters {
+		assignees = slices.DeleteFunc(slices.Clone(assignees), func(id int) bool {
+			return slices.Contains(before.AssigneeIDs, id)
+		})
+		if len(assignees) == 0 {
+			return nil
+		}
+	}
+
+	var c models.Column
+	err := tx.QueryRow(ctx, `SELECT key, wip_limit, wip_limit_per_assignee, wip_mode FROM board_columns
+                             WHERE id = $1 FOR NO KEY UPDATE`, task.ColumnID).
+		Scan(&c.Key, &c.WIPLimit, &c.WIPLimitPerAssignee, &c.WIPMode)
+	if err != nil {
+		return err
+	}
+
+	var exceeded []*models.WIPLimitError
+	if enters && c.WIPLimit != nil {
+		var n int
+		err := tx.QueryRow(ctx, `SELECT count(*) FROM tasks WHERE column_id = $1 AND id <> $2 AND deleted_at IS NULL AND archived_at IS NULL`,
+			task.ColumnID, task.ID).Scan(&n)
+		if err != nil {
+			return err
+		}
+		if n+1 > *c.WIPLimit {
+			exceeded = append(exceeded, &models.WIPLimitError{Column: c.Key, Limit: *c.WIPLimit, Count: n + 1})
+		}
+	}
+	if c.WIPLimitPerAssignee == nil {
+		assignees = nil
+	}
+	for _, assignee := range assignees {
+		var n int
+		err := tx.QueryRow(ctx, `SELECT count(*) FROM tasks t JOIN task_assignees a ON a.task_id = t.id
+                                 WHERE t.column_id = $1 AND a.user_id = $2 AND t.id <> $3 AND t.deleted_at IS NULL AND t.archived_at IS NULL`,
+			task.ColumnID, assignee, task.ID).Scan(&n)
+		if err != nil {
+			return err
+		}
+		if n+1 > *c.WIPLimitPerAssignee {
+			exceeded = append(exceeded, &models.WIPLimitError{Column: c.Key, Assignee: &assignee,
+				Limit: *c.WIPLimitPerAssignee, Count: n + 1})
+		}
+	}
+
+	for _, e := range exceeded {
+		if c.WIPMode != models.WIPModeSoft {
+			return e
+		}
+		task.Warnings = append(task.Warnings, e.Error())
+	}
+	return nil
+}
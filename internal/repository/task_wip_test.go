@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/TrueSmartcomm/backend/internal/apperror"
+	"github.com/TrueSmartcomm/backend/internal/models"
+	"github.com/TrueSmartcomm/backend/internal/requser"
+)
+
+func TestWIPLimit(t *testing.T) {
+	db := testDB(t)
+	ctx := context.Background()
+
+	two, one := 2, 1
+	board := &models.Board{Name: "wip", Columns: []models.Column{
+		{Key: "todo", Name: "To Do", WIPLimit: &two},
+		{Key: "doing", Name: "Doing", WIPLimit: &one},
+		{Key: "review", Name: "Review", WIPLimit: &one, WIPMode: models.WIPModeSoft},
+	}}
+	if err := NewBoardRepository(db).CreateBoard(ctx, board); err != nil {
+		t.Fatal(err)
+	}
+	r := NewTaskRepository(db)
+	user := requser.WithUser(ctx, testUser(t, db, "owner"))
+	create := func(title, space string) (*models.Task, error) {
+		task := &models.Task{Title: title, BoardID: board.ID, KanbanSpace: space}
+		return task, r.CreateTask(user, task)
+	}
+	move := func(task *models.Task, m models.TaskMove) error {
+		_, err := r.MoveTaskToSpace(user, task.ID, m, nil)
+		return err
+	}
+
+	// в колонке todo лимит 2: две задачи помещаются, третья — нет
+	a, err := create("a", "todo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := create("b", "todo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = create("c", "todo")
+	assertWIPError(t, "create over the limit", err, map[string]any{"column": "todo", "wip_limit": 2, "wip_count": 3})
+
+	// перестановка внутри заполненной колонки лимит не расходует
+	if err := move(a, models.TaskMove{AfterID: &b.ID}); err != nil {
+		t.Errorf("reorder within a full column: %v", err)
+	}
+	if err := move(b, models.TaskMove{Space: "todo"}); err != nil {
+		t.Errorf("move into its own full column: %v", err)
+	}
+
+	// в doing лимит 1: первая задача входит, вторая — нет
+	if err := move(a, models.TaskMove{Space: "doing"}); err != nil {
+		t.Fatalf("move into a column with room: %v", err)
+	}
+	err = move(b, models.TaskMove{Space: "doing"})
+	assertWIPError(t, "move over the limit", err, map[string]any{"column": "doing", "wip_limit": 1, "wip_count": 2})
+
+	// soft-лимит пропускает задачу с предупреждением
+	if _, err := create("d", "review"); err != nil {
+		t.Fatal(err)
+	}
+	e, err := create("e", "review")
+	if err != nil {
+		t.Fatalf("soft limit: %v", err)
+	}
+	if want := []string{"column review WIP limit exceeded: 2 of 1"}; !reflect.DeepEqual(e.Warnings, want) {
+		t.Errorf("soft limit warnings = %v, want %v", e.Warnings, want)
+	}
+}
+
+// assertWIPError проверяет, что err — превышение WIP-лимита с ответом 409 wip_limit_exceeded
+// и расширениями extensions
+func assertWIPError(t *testing.T, name string, err error, extensions map[string]any) {
+	t.Helper()
+	var wipErr *models.WIPLimitError
+	if !errors.As(err, &wipErr) {
+		t.Fatalf("%s: err = %v, want *models.WIPLimitError", name, err)
+	}
+	appErr := wipErr.AppError()
+	if appErr.Kind != apperror.KindConflict || appErr.Code != apperror.CodeWIPLimitExceeded {
+		t.Errorf("%s: error %v/%s, want conflict/%s", name, appErr.Kind, appErr.Code, apperror.CodeWIPLimitExceeded)
+	}
+	if !reflect.DeepEqual(appErr.Extensions, extensions) {
+		t.Errorf("%s: extensions = %v, want %v", name, appErr.Extensions, extensions)
+	}
+}
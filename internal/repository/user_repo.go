@@ -6,15 +6,22 @@ import (
 	"errors"
 	"time"
 
+	"github.com/TrueSmartcomm/backend/internal/apperror"
 	"github.com/TrueSmartcomm/backend/internal/models"
 	"github.com/jackc/pgx/v5"         // Для работы с PostgreSQL
-	"github.com/jackc/pgx/v5/pgconn"  // Для проверки кодов ошибок
 	"github.com/jackc/pgx/v5/pgxpool" // Пул соединений
 )
 
+var (
+	errUserNotFound         = apperror.NotFound(apperror.CodeUserNotFound, "user not found")
+	errRefreshTokenNotFound = apperror.Unauthorized(apperror.CodeInvalidRefresh, "refresh token not found")
+)
+
 // UserRepository структура для работы с пользователями в БД
 type UserRepository struct {
 	DB *pgxpool.Pool
+	// DefaultRole роль нового пользователя в рабочем пространстве; пусто — без роли
+	DefaultRole string
 }
 
 // NewUserRepository конструктор для UserRepository
@@ -22,30 +29,44 @@ func NewUserRepository(db *pgxpool.Pool) *UserRepository {
 	return &UserRepository{DB: db}
 }
 
-// CreateUser создает нового пользователя
+// CreateUser создает нового пользователя с ролью DefaultRole в рабочем пространстве.
+// Владельцем регистрация не делает: его назначает команда `app owner`.
 func (r *UserRepository) CreateUser(ctx context.Context, user *models.User) error {
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
 	query := `INSERT INTO users (login, email, password_hash, created_at, updated_at) 
               VALUES ($1, $2, $3, NOW(), NOW()) RETURNING id, created_at, updated_at`
 
 	// Используем QueryRow, так как RETURNING возвращает одну строку
-	err := r.DB.QueryRow(ctx, query, user.Login, user.Email, user.PasswordHash).
+	err = tx.QueryRow(ctx, query, user.Login, user.Email, user.PasswordHash).
 		Scan(&user.ID, &user.CreatedAt, &user.UpdatedAt)
 
 	if err != nil {
 		// Проверяем на нарушение уникального ограничения (дубликрующий логин или email)
 		// pgx возвращает ошибку с кодом через pgconn.PgError
-		if pgErr, ok := err.(*pgconn.PgError); ok {
-			if pgErr.Code == "23505" { // unique_violation
-				// Проверим, какое именно ограничение нарушено, по имени
-				if pgErr.ConstraintName == "users_login_key" || pgErr.ConstraintName == "users_email_key" {
-					// Возвращаем общую ошибку, чтобы не раскрывать, что именно дублируется
-					return errors.New("user with this login or email already exists")
-				}
+		if pgErr := pgError(err, pgUniqueViolation); pgErr != nil {
+			// Проверим, какое именно ограничение нарушено, по имени
+			switch pgErr.ConstraintName {
+			case "users_login_key":
+				return apperror.Conflict(apperror.CodeLoginTaken, "user with this login already exists")
+			case "users_email_key":
+				return apperror.Conflict(apperror.CodeEmailTaken, "user with this email already exists")
 			}
 		}
 		return err
 	}
-	return nil
+
+	if r.DefaultRole != "" {
+		_, err := tx.Exec(ctx, `INSERT INTO workspace_members (user_id, role) VALUES ($1, $2)`, user.ID, r.DefaultRole)
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit(ctx)
 }
 
 // GetUserByLogin находит пользователя по логину
@@ -59,7 +80,7 @@ func (r *UserRepository) GetUserByLogin(ctx context.Context, login string) (*mod
 
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, errors.New("user not found")
+			return nil, errUserNotFound
 		}
 		return nil, err
 	}
@@ -78,7 +99,7 @@ func (r *UserRepository) GetUserByEmail(ctx context.Context, email string) (*mod
 
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, errors.New("user not found")
+			return nil, errUserNotFound
 		}
 		return nil, err
 	}
@@ -103,7 +124,7 @@ func (r *UserRepository) GetRefreshToken(ctx context.Context, token string) (int
 	err := r.DB.QueryRow(ctx, query, token).Scan(&userID, &expiresAt)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return 0, time.Time{}, errors.New("refresh token not found")
+			return 0, time.Time{}, errRefreshTokenNotFound
 		}
 		return 0, time.Time{}, err
 	}
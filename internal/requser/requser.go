@@ -0,0 +1,19 @@
+// Package requser хранит в context.Context пользователя, от имени которого выполняется запрос.
+// Хендлеры кладут его из токена, а права, условия переходов workflow и автор в истории задачи
+// берут его отсюда.
+package requser
+
+import "context"
+
+type userKey struct{}
+
+// WithUser сохраняет в контексте пользователя userID
+func WithUser(ctx context.Context, userID int) context.Context {
+	return context.WithValue(ctx, userKey{}, userID)
+}
+
+// FromContext пользователь, сохранённый WithUser
+func FromContext(ctx context.Context) (int, bool) {
+	userID, ok := ctx.Value(userKey{}).(int)
+	return userID, ok
+}
@@ -0,0 +1,291 @@
+// Package schedule строит расписание задач методом критического пути (CPM).
+// Расчёт не обращается к БД: репозиторий собирает задачи и связи, а Compute
+// вычисляет ранние и поздние сроки, резерв времени и критический путь.
+package schedule
+
+import (
+	"container/heap"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/TrueSmartcomm/backend/internal/apperror"
+	"github.com/TrueSmartcomm/backend/internal/models"
+	"github.com/google/uuid"
+)
+
+// Edge задача Before должна завершиться до начала задачи After.
+// Связь blocks даёт ребро блокирующая → заблокированная, subtask_of — подзадача → родитель.
+type Edge struct {
+	Before uuid.UUID
+	After  uuid.UUID
+}
+
+// Task задача в расписании. Сроки отсчитываются от Schedule.Start в часах
+// непрерывного времени, без учёта рабочего календаря.
+type Task struct {
+	ID             uuid.UUID   `json:"id"`
+	Title          string      `json:"title"`
+	Status         string      `json:"status"`
+	EstimateHours  *float64    `json:"estimate_hours,omitempty"`
+	DurationHours  float64     `json:"duration_hours"`
+	DueDate        *time.Time  `json:"due_date,omitempty"`
+	Predecessors   []uuid.UUID `json:"predecessors,omitempty"`
+	EarliestStart  time.Time   `json:"earliest_start"`
+	EarliestFinish time.Time   `json:"earliest_finish"`
+	LatestStart    time.Time   `json:"latest_start"`
+	LatestFinish   time.Time   `json:"latest_finish"`
+	SlackHours     float64     `json:"slack_hours"`
+	Critical       bool        `json:"critical"`
+	DueDateAtRisk  bool        `json:"due_date_at_risk"` // срок не достижим даже при раннем завершении
+}
+
+// Schedule расписание набора задач
+type Schedule struct {
+	Root         *uuid.UUID  `json:"root,omitempty"`
+	Start        time.Time   `json:"start"`
+	Finish       time.Time   `json:"finish"`
+	CriticalPath []uuid.UUID `json:"critical_path"`
+	Tasks        []Task      `json:"tasks"` // в топологическом порядке
+}
+
+// CycleError связи blocks и subtask_of вместе образуют цикл, расписание построить нельзя.
+// Связи, замыкающие такой цикл, отклоняются при записи; ошибка возможна только для связей,
+// сохранённых до этой проверки. Path начинается и заканчивается одной и той же задачей.
+type CycleError struct {
+	Path []uuid.UUID
+}
+
+func (e *CycleError) Error() string {
+	ids := make([]string, len(e.Path))
+	for i, id := range e.Path {
+		ids[i] = id.String()
+	}
+	return "blocks and subtask_of dependencies form a cycle: " + strings.Join(ids, " -> ")
+}
+
+// AppError 409 Conflict
+func (e *CycleError) AppError() *apperror.Error {
+	return apperror.Conflict(apperror.CodeDependencyCycle, e.Error())
+}
+
+// eps допуск при сравнении сумм часов: оценки хранятся с точностью до сотых
+const eps = 1e-6
+
+// Compute строит расписание задач tasks, начиная с момента start. Рёбра с задачами
+// вне tasks игнорируются. Длительность задачи — её оценка; задачи без оценки и
+// завершённые задачи (done — задачи в завершающем статусе своей доски) имеют нулевую
+// длительность. Порядок tasks определяет порядок задач с одинаковым положением в графе.
+func Compute(start time.Time, tasks []models.Task, edges []Edge, done map[uuid.UUID]bool) (*Schedule, error) {
+	n := len(tasks)
+	index := make(map[uuid.UUID]int, n)
+	for i, t := range tasks {
+		index[t.ID] = i
+	}
+
+	preds := make([][]int, n)
+	succs := make([][]int, n)
+	seen := map[Edge]bool{}
+	for _, e := range edges {
+		b, okB := index[e.Before]
+		a, okA := index[e.After]
+		if !okB || !okA || seen[e] {
+			continue
+		}
+		seen[e] = true
+		preds[a] = append(preds[a], b)
+		succs[b] = append(succs[b], a)
+	}
+
+	order, err := topoSort(tasks, preds, succs)
+	if err != nil {
+		return nil, err
+	}
+
+	dur := make([]float64, n)
+	for i, t := range tasks {
+		if t.EstimateHours != nil && !done[t.ID] {
+			dur[i] = *t.EstimateHours
+		}
+	}
+
+	// Прямой проход: ранние сроки
+	es, ef := make([]float64, n), make([]float64, n)
+	finish := 0.0
+	for _, i := range order {
+		for _, p := range preds[i] {
+			es[i] = math.Max(es[i], ef[p])
+		}
+		ef[i] = es[i] + dur[i]
+		finish = math.Max(finish, ef[i])
+	}
+
+	// Обратный проход: поздние сроки без сдвига общего завершения
+	ls, lf := make([]float64, n), make([]float64, n)
+	for k := n - 1; k >= 0; k-- {
+		i := order[k]
+		lf[i] = finish
+		for _, s := range succs[i] {
+			lf[i] = math.Min(lf[i], ls[s])
+		}
+		ls[i] = lf[i] - dur[i]
+	}
+
+	at := func(hours float64) time.Time {
+		return start.Add(time.Duration(math.Round(hours * float64(time.Hour))))
+	}
+
+	s := &Schedule{
+		Start:        start,
+		Finish:       at(finish),
+		CriticalPath: []uuid.UUID{},
+		Tasks:        make([]Task, 0, n),
+	}
+	critical := make([]bool, n)
+	for _, i := range order {
+		t := tasks[i]
+		slack := math.Round((ls[i]-es[i])*100) / 100
+		critical[i] = slack < eps
+		st := Task{
+			ID:             t.ID,
+			Title:          t.Title,
+			Status:         t.Status,
+			EstimateHours:  t.EstimateHours,
+			DurationHours:  dur[i],
+			DueDate:        t.DueDate,
+			EarliestStart:  at(es[i]),
+			EarliestFinish: at(ef[i]),
+			LatestStart:    at(ls[i]),
+			LatestFinish:   at(lf[i]),
+			SlackHours:     slack,
+			Critical:       critical[i],
+		}
+		for _, p := range preds[i] {
+			st.Predecessors = append(st.Predecessors, tasks[p].ID)
+		}
+		if t.DueDate != nil && !done[t.ID] {
+			st.DueDateAtRisk = st.EarliestFinish.After(*t.DueDate)
+		}
+		s.Tasks = append(s.Tasks, st)
+	}
+
+	for _, i := range criticalPath(order, succs, critical, es, ef) {
+		s.CriticalPath = append(s.CriticalPath, tasks[i].ID)
+	}
+	return s, nil
+}
+
+// topoSort упорядочивает задачи так, что предшественники идут раньше (алгоритм Кана).
+// Из готовых к обработке задач первой берётся та, что раньше в tasks: очередь готовых —
+// куча по индексу, поэтому сортировка занимает O((n + e) log n).
+func topoSort(tasks []models.Task, preds, succs [][]int) ([]int, error) {
+	n := len(tasks)
+	indegree := make([]int, n)
+	ready := &indexHeap{}
+	for i := range tasks {
+		indegree[i] = len(preds[i])
+		if indegree[i] == 0 {
+			*ready = append(*ready, i)
+		}
+	}
+	heap.Init(ready)
+
+	order := make([]int, 0, n)
+	done := make([]bool, n)
+	for ready.Len() > 0 {
+		next := heap.Pop(ready).(int)
+		done[next] = true
+		order = append(order, next)
+		for _, s := range succs[next] {
+			indegree[s]--
+			if indegree[s] == 0 {
+				heap.Push(ready, s)
+			}
+		}
+	}
+	if len(order) < n {
+		return nil, &CycleError{Path: findCycle(tasks, preds, done)}
+	}
+	return order, nil
+}
+
+// indexHeap куча индексов задач, наименьший сверху
+type indexHeap []int
+
+func (h indexHeap) Len() int           { return len(h) }
+func (h indexHeap) Less(i, j int) bool { return h[i] < h[j] }
+func (h indexHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *indexHeap) Push(x any)        { *h = append(*h, x.(int)) }
+func (h *indexHeap) Pop() any {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
+
+// findCycle находит цикл среди необработанных задач. Каждая из них имеет
+// необработанного предшественника, поэтому движение по предшественникам
+// обязательно вернётся в уже пройденную задачу.
+func findCycle(tasks []models.Task, preds [][]int, done []bool) []uuid.UUID {
+	cur := -1
+	for i := range tasks {
+		if !done[i] {
+			cur = i
+			break
+		}
+	}
+	pos := map[int]int{}
+	var walk []int
+	for {
+		if k, ok := pos[cur]; ok {
+			walk = walk[k:]
+			break
+		}
+		pos[cur] = len(walk)
+		walk = append(walk, cur)
+		for _, p := range preds[cur] {
+			if !done[p] {
+				cur = p
+				break
+			}
+		}
+	}
+
+	// walk идёт против направления рёбер; разворачиваем и замыкаем путь
+	path := make([]uuid.UUID, 0, len(walk)+1)
+	for k := len(walk) - 1; k >= 0; k-- {
+		path = append(path, tasks[walk[k]].ID)
+	}
+	return append(path, path[0])
+}
+
+// criticalPath цепочка критических задач от начала до завершения расписания.
+// Если таких цепочек несколько, выбирается первая по порядку задач.
+func criticalPath(order []int, succs [][]int, critical []bool, es, ef []float64) []int {
+	cur := -1
+	for _, i := range order {
+		if critical[i] && es[i] < eps {
+			cur = i
+			break
+		}
+	}
+	if cur < 0 {
+		return nil
+	}
+
+	path := []int{cur}
+	for {
+		next := -1
+		for _, s := range succs[cur] {
+			if critical[s] && math.Abs(es[s]-ef[cur]) < eps {
+				next = s
+				break
+			}
+		}
+		if next < 0 {
+			return path
+		}
+		path = append(path, next)
+		cur = next
+	}
+}
@@ -0,0 +1,155 @@
+package schedule
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/TrueSmartcomm/backend/internal/models"
+	"github.com/google/uuid"
+)
+
+func hours(v float64) *float64 { return &v }
+
+func TestCompute(t *testing.T) {
+	start := time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+	a, b, c, d := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	due := start.Add(5 * time.Hour)
+
+	tests := []struct {
+		name     string
+		tasks    []models.Task
+		edges    []Edge
+		done     map[uuid.UUID]bool
+		finish   float64
+		critical []uuid.UUID
+		slack    map[uuid.UUID]float64
+		atRisk   []uuid.UUID
+	}{
+		{
+			name:     "no tasks",
+			critical: []uuid.UUID{},
+		},
+		{
+			name:     "independent tasks",
+			tasks:    []models.Task{{ID: a, EstimateHours: hours(2)}, {ID: b, EstimateHours: hours(5)}},
+			finish:   5,
+			critical: []uuid.UUID{b},
+			slack:    map[uuid.UUID]float64{a: 3, b: 0},
+		},
+		{
+			// a → b → d и a → c → d; ветка через c короче
+			name: "diamond",
+			tasks: []models.Task{
+				{ID: a, EstimateHours: hours(1)}, {ID: b, EstimateHours: hours(4)},
+				{ID: c, EstimateHours: hours(2)}, {ID: d, EstimateHours: hours(1)},
+			},
+			edges:    []Edge{{a, b}, {a, c}, {b, d}, {c, d}},
+			finish:   6,
+			critical: []uuid.UUID{a, b, d},
+			slack:    map[uuid.UUID]float64{a: 0, b: 0, c: 2, d: 0},
+		},
+		{
+			name: "done and unestimated tasks take no time",
+			tasks: []models.Task{
+				{ID: a, EstimateHours: hours(3)}, {ID: b}, {ID: c, EstimateHours: hours(2)},
+			},
+			edges:    []Edge{{a, b}, {b, c}},
+			done:     map[uuid.UUID]bool{a: true},
+			finish:   2,
+			critical: []uuid.UUID{a, b, c},
+		},
+		{
+			name: "edges outside the set and duplicates are ignored",
+			tasks: []models.Task{
+				{ID: a, EstimateHours: hours(1)}, {ID: b, EstimateHours: hours(1)},
+			},
+			edges:    []Edge{{a, b}, {a, b}, {d, a}, {b, c}},
+			finish:   2,
+			critical: []uuid.UUID{a, b},
+		},
+		{
+			name: "due date at risk",
+			tasks: []models.Task{
+				{ID: a, EstimateHours: hours(4)}, {ID: b, EstimateHours: hours(2), DueDate: &due},
+				{ID: c, EstimateHours: hours(1), DueDate: &due},
+			},
+			edges:    []Edge{{a, b}},
+			finish:   6,
+			critical: []uuid.UUID{a, b},
+			atRisk:   []uuid.UUID{b},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := Compute(start, tt.tasks, tt.edges, tt.done)
+			if err != nil {
+				t.Fatalf("Compute: %v", err)
+			}
+			if want := start.Add(time.Duration(tt.finish * float64(time.Hour))); !s.Finish.Equal(want) {
+				t.Errorf("finish = %v, want %v", s.Finish, want)
+			}
+			if !slices.Equal(s.CriticalPath, tt.critical) {
+				t.Errorf("critical path = %v, want %v", s.CriticalPath, tt.critical)
+			}
+			pos := map[uuid.UUID]int{}
+			for i, task := range s.Tasks {
+				pos[task.ID] = i
+			}
+			for _, task := range s.Tasks {
+				if want, ok := tt.slack[task.ID]; ok && task.SlackHours != want {
+					t.Errorf("slack of %v = %v, want %v", task.ID, task.SlackHours, want)
+				}
+				if task.DueDateAtRisk != slices.Contains(tt.atRisk, task.ID) {
+					t.Errorf("due date at risk of %v = %v", task.ID, task.DueDateAtRisk)
+				}
+				for _, p := range task.Predecessors {
+					if pos[p] > pos[task.ID] {
+						t.Errorf("predecessor %v goes after %v", p, task.ID)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestComputeCycle(t *testing.T) {
+	a, b, c := uuid.New(), uuid.New(), uuid.New()
+	tasks := []models.Task{{ID: a}, {ID: b}, {ID: c}}
+	_, err := Compute(time.Now(), tasks, []Edge{{a, b}, {b, c}, {c, b}}, nil)
+
+	var cycle *CycleError
+	if !errors.As(err, &cycle) {
+		t.Fatalf("error = %v, want *CycleError", err)
+	}
+	path := cycle.Path
+	if len(path) != 3 || path[0] != path[len(path)-1] || !slices.Contains(path, b) || !slices.Contains(path, c) {
+		t.Errorf("cycle path = %v, want b and c closed into a loop", path)
+	}
+}
+
+// Цепочка, заданная в обратном порядке: готовая задача всегда последняя в tasks.
+// Поиск готовой задачи перебором сделал бы расчёт квадратичным.
+func TestComputeLongChain(t *testing.T) {
+	const n = 100000
+	tasks := make([]models.Task, n)
+	edges := make([]Edge, 0, n-1)
+	for i := range tasks {
+		tasks[i] = models.Task{ID: uuid.New(), EstimateHours: hours(1)}
+	}
+	for i := n - 1; i > 0; i-- {
+		edges = append(edges, Edge{Before: tasks[i].ID, After: tasks[i-1].ID})
+	}
+
+	s, err := Compute(time.Time{}, tasks, edges, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Tasks[0].ID != tasks[n-1].ID || s.Tasks[n-1].ID != tasks[0].ID {
+		t.Errorf("chain is not ordered from its first task")
+	}
+	if len(s.CriticalPath) != n {
+		t.Errorf("critical path has %d tasks, want %d", len(s.CriticalPath), n)
+	}
+}
@@ -1 +1,183 @@
+// Package server собирает HTTP-маршруты сервиса.
 package server
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/TrueSmartcomm/backend/api"
+	"github.com/TrueSmartcomm/backend/internal/auth"
+	handlers "github.com/TrueSmartcomm/backend/internal/handler"
+	"github.com/TrueSmartcomm/backend/internal/middleware"
+	"github.com/TrueSmartcomm/backend/internal/openapi"
+	"github.com/gin-contrib/cors"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// APIPrefix префикс всех маршрутов API, совпадает с путями в api/openapiv1.yaml
+const APIPrefix = "/api/v1"
+
+// Deps зависимости, необходимые для построения маршрутов
+type Deps struct {
+	DB          *pgxpool.Pool
+	AuthService *auth.AuthService
+	Tasks       *handlers.TaskHandler
+	Boards      *handlers.BoardHandler
+	Members     *handlers.MemberHandler
+	Auth        *handlers.AuthHandler
+
+	// Spec документ, по которому проверяются запросы к операциям API
+	Spec *openapi.Document
+	// ValidateResponses дополнительно проверять ответы (режим разработки)
+	ValidateResponses bool
+}
+
+// Router gin-движок вместе со списком устаревших маршрутов
+type Router struct {
+	*gin.Engine
+	deprecated map[string]bool // "METHOD /path" устаревших алиасов
+}
+
+// New создаёт роутер со всеми маршрутами сервиса
+func New(d Deps) *Router {
+	r := &Router{Engine: gin.New(), deprecated: map[string]bool{}}
+
+	corsConfig := cors.DefaultConfig()
+	corsConfig.AllowOrigins = []string{"*"}
+	corsConfig.AllowMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS"}
+	corsConfig.AllowHeaders = []string{
+		"Origin",
+		"Content-Length",
+		"Content-Type",
+		"Authorization",
+		"If-Match",
+		"If-None-Match",
+	}
+	corsConfig.ExposeHeaders = []string{"Deprecation", "Link", "ETag"}
+
+	// Ошибки, переданные через c.Error, отдаются клиенту как application/problem+json
+	r.Use(gin.Recovery(), gin.Logger(), cors.New(corsConfig), middleware.Errors())
+
+	// Healthcheck endpoint (публичный)
+	r.GET("/health", func(ctx *gin.Context) {
+		ctx.JSON(http.StatusOK, gin.H{"status": "ok"})
+	})
+
+	// Проверка подключения к БД (публичный)
+	r.GET("/db-check", func(ctx *gin.Context) {
+		var now string
+		if err := d.DB.QueryRow(ctx, "SELECT NOW()").Scan(&now); err != nil {
+			_ = ctx.Error(err)
+			return
+		}
+		ctx.JSON(http.StatusOK, gin.H{"db_time": now})
+	})
+
+	opts := api.HandlerOptions{ErrorHandler: handlers.RequestError}
+
+	// Запросы к операциям из спецификации проверяются по ней до вызова обработчика
+	// Алиасы с удвоенным префиксом (см. ниже) проверяются по тем же операциям
+	validatorOpts := []openapi.ValidatorOption{openapi.WithAliasPrefix(APIPrefix)}
+	if d.ValidateResponses {
+		validatorOpts = append(validatorOpts, openapi.WithResponseValidation())
+	}
+	validate := openapi.NewValidator(d.Spec, validatorOpts...).Middleware()
+
+	// --- Публичные маршруты (не требуют аутентификации) ---
+	// refresh публичный, так как использует refresh токен из тела
+	api.RegisterAuthHandlers(r.Group("", validate), d.Auth, opts)
+
+	// --- Защищённые маршруты (требуют аутентификацию через JWT) ---
+	// Пути операций берутся из спецификации и уже содержат APIPrefix
+	authorized := r.Group("", middleware.AuthRequired(d.AuthService), validate)
+	api.RegisterTasksHandlers(authorized, d.Tasks, opts) // GET /tasks?id= поддерживается как устаревший алиас
+	api.RegisterProfileHandlers(authorized, d.Auth, opts)
+	api.RegisterBoardsHandlers(authorized, d.Boards, opts)
+	api.RegisterMembersHandlers(authorized, d.Members, opts)
+
+	// --- Устаревшие маршруты ---
+	// Старые маршруты с ?id= и id в теле запроса
+	r.registerLegacyTaskRoutes(authorized.Group(APIPrefix), d.Tasks)
+
+	// Раньше задачи регистрировались с лишним префиксом и фактически обслуживались
+	// по /api/v1/api/v1/...; эти пути оставлены, пока клиенты не перейдут на новые.
+	doubled := authorized.Group(APIPrefix, deprecatedPrefix)
+	api.RegisterTasksHandlers(doubled, d.Tasks, opts)
+	api.RegisterProfileHandlers(doubled, d.Auth, opts)
+	r.registerLegacyTaskRoutes(doubled.Group(APIPrefix), d.Tasks)
+
+	return r
+}
+
+// deprecatedPrefix помечает маршруты с удвоенным префиксом как устаревшие
+// и ссылается на тот же путь с одним APIPrefix
+func deprecatedPrefix(c *gin.Context) {
+	successor := strings.TrimPrefix(c.FullPath(), APIPrefix)
+	middleware.MarkDeprecated(c, openapi.SpecPath(successor))
+	c.Next()
+}
+
+// registerLegacyTaskRoutes регистрирует маршруты задач в старом формате (id в query или в теле)
+func (r *Router) registerLegacyTaskRoutes(g *gin.RouterGroup, th *handlers.TaskHandler) {
+	successor := func(path string) string { return APIPrefix + "/tasks" + path }
+	r.deprecate(g, http.MethodPut, "/tasks", successor("/{id}"), th.LegacyUpdateTask)
+	r.deprecate(g, http.MethodDelete, "/tasks", successor("/{id}"), th.LegacyDeleteTask)
+	r.deprecate(g, http.MethodPost, "/tasks/move", successor("/{id}/move"), th.LegacyMoveTask)
+	r.deprecate(g, http.MethodPost, "/tasks/dependency", successor("/{id}/dependencies"), th.LegacyAddTaskDependency)
+	r.deprecate(g, http.MethodDelete, "/tasks/dependency", successor("/{id}/dependencies/{dependent_id}"), th.LegacyRemoveTaskDependency)
+	r.deprecate(g, http.MethodGet, "/tasks/with-dependencies", successor("/{id}/dependencies"), th.LegacyGetTaskWithDependencies)
+}
+
+// deprecate регистрирует устаревший маршрут с заголовком Deprecation
+func (r *Router) deprecate(g *gin.RouterGroup, method, path, successor string, h gin.HandlerFunc) {
+	g.Handle(method, path, middleware.Deprecated(successor), h)
+	r.deprecated[method+" "+strings.TrimSuffix(g.BasePath(), "/")+path] = true
+}
+
+// CheckSpec сверяет зарегистрированные маршруты API с OpenAPI-документом:
+// каждый неустаревший маршрут под APIPrefix должен быть описан в спецификации,
+// а каждая операция спецификации должна иметь маршрут. Алиас с удвоенным префиксом
+// должен соответствовать описанной операции: запросы к нему проверяются по ней.
+func (r *Router) CheckSpec(doc *openapi.Document) error {
+	documented := map[string]bool{}
+	for path, item := range doc.Paths {
+		for method := range item.Operations() {
+			documented[method+" "+openapi.GinPath(path)] = true
+		}
+	}
+
+	registered := map[string]bool{}
+	var problems []string
+	for _, route := range r.Routes() {
+		key := route.Method + " " + route.Path
+		if !strings.HasPrefix(route.Path, APIPrefix+"/") || r.deprecated[key] {
+			continue
+		}
+		if successor := strings.TrimPrefix(route.Path, APIPrefix); strings.HasPrefix(successor, APIPrefix+"/") {
+			if !documented[route.Method+" "+successor] {
+				problems = append(problems, "alias of undescribed operation: "+key)
+			}
+			continue
+		}
+		registered[key] = true
+		if !documented[key] {
+			problems = append(problems, "route not described in spec: "+key)
+		}
+	}
+	for key := range documented {
+		if !registered[key] {
+			problems = append(problems, "spec operation has no route: "+key)
+		}
+	}
+
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		return fmt.Errorf("routes do not conform to OpenAPI spec:\n  %s", strings.Join(problems, "\n  "))
+	}
+	log.Printf("[INFO] %d API routes conform to OpenAPI spec", len(registered))
+	return nil
+}
@@ -0,0 +1,79 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/TrueSmartcomm/backend/api"
+	"github.com/TrueSmartcomm/backend/internal/auth"
+	"github.com/TrueSmartcomm/backend/internal/openapi"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var testSecret = []byte("test-secret")
+
+// newTestRouter собирает роутер без базы данных: до обработчиков запросы в тестах не доходят
+func newTestRouter(t *testing.T) (*Router, *openapi.Document) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	doc, err := openapi.Load(api.Spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return New(Deps{AuthService: auth.NewAuthService(nil, testSecret), Spec: doc}), doc
+}
+
+func TestCheckSpec(t *testing.T) {
+	r, doc := newTestRouter(t)
+	if err := r.CheckSpec(doc); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCheckSpecMismatch(t *testing.T) {
+	r, doc := newTestRouter(t)
+	r.GET(APIPrefix+"/undocumented", func(*gin.Context) {})
+	r.GET(APIPrefix+APIPrefix+"/undocumented", func(*gin.Context) {})
+	delete(doc.Paths, "/api/v1/profile")
+
+	want := "routes do not conform to OpenAPI spec:\n" +
+		"  alias of undescribed operation: GET /api/v1/api/v1/profile\n" +
+		"  alias of undescribed operation: GET /api/v1/api/v1/undocumented\n" +
+		"  route not described in spec: GET /api/v1/profile\n" +
+		"  route not described in spec: GET /api/v1/undocumented"
+	err := r.CheckSpec(doc)
+	if err == nil || err.Error() != want {
+		t.Errorf("CheckSpec() = %v, want %s", err, want)
+	}
+}
+
+func TestRequestValidation(t *testing.T) {
+	r, _ := newTestRouter(t)
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"user_id": 1}).SignedString(testSecret)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		path string
+	}{
+		{name: "operation", path: APIPrefix + "/tasks/not-a-uuid"},
+		{name: "doubled prefix alias", path: APIPrefix + APIPrefix + "/tasks/not-a-uuid"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			req.Header.Set("Authorization", "Bearer "+token)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			// Ошибку должен вернуть валидатор, а не разбор параметров в обработчике
+			if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), "request does not conform to API spec") {
+				t.Errorf("got %d %s, want validation error", w.Code, w.Body)
+			}
+		})
+	}
+}
@@ -0,0 +1,282 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/TrueSmartcomm/backend/internal/apperror"
+	"github.com/TrueSmartcomm/backend/internal/models"
+	"github.com/TrueSmartcomm/backend/internal/requser"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// BlockedError переход запрещён: есть незавершённые блокирующие задачи или подзадачи
+type BlockedError struct {
+	TaskID   uuid.UUID
+	Status   string      // целевой статус
+	Blocking []uuid.UUID // незавершённые задачи
+}
+
+func (e *BlockedError) Error() string {
+	return fmt.Sprintf("task cannot move to %s: %d unfinished blocking task(s)", e.Status, len(e.Blocking))
+}
+
+// AppError 409 Conflict со списком blocking_tasks
+func (e *BlockedError) AppError() *apperror.Error {
+	err := apperror.Conflict(apperror.CodeTransitionBlocked, e.Error())
+	err.Extensions = map[string]any{"blocking_tasks": e.Blocking}
+	return err
+}
+
+// TransitionError перехода между статусами нет в графе доски
+type TransitionError struct {
+	From    string
+	To      string
+	Allowed []string // статусы, в которые можно перейти из From
+}
+
+func (e *TransitionError) Error() string {
+	return fmt.Sprintf("transition from %s to %s is not allowed", e.From, e.To)
+}
+
+// AppError 409 Conflict со списком allowed_statuses
+func (e *TransitionError) AppError() *apperror.Error {
+	err := apperror.Conflict(apperror.CodeTransitionDenied, e.Error())
+	allowed := e.Allowed
+	if allowed == nil {
+		allowed = []string{}
+	}
+	err.Extensions = map[string]any{"allowed_statuses": allowed}
+	return err
+}
+
+// BoardTransitions разрешает смену статуса только по переходам графа доски и при выполнении
+// их условий: заполненные поля, назначенный исполнитель, роль пользователя.
+// Перенос задачи на другую доску граф не проверяет: статус лишь должен существовать на новой доске.
+type BoardTransitions struct{}
+
+func (BoardTransitions) Check(ctx context.Context, tx pgx.Tx, t Transition) error {
+	if t.Before.Status == t.After.Status || t.Before.BoardID != t.After.BoardID {
+		return nil
+	}
+	w, err := LoadWorkflow(ctx, tx, t.After.BoardID)
+	if err != nil || !w.Restricted() {
+		return err
+	}
+	tr := w.Transition(t.Before.Status, t.After.Status)
+	if tr == nil {
+		return &TransitionError{From: t.Before.Status, To: t.After.Status, Allowed: w.Next(t.Before.Status)}
+	}
+
+	if missing := tr.MissingFields(t.After); len(missing) > 0 {
+		fields := make([]apperror.FieldError, len(missing))
+		for i, f := range missing {
+			fields[i] = apperror.FieldError{Name: f, Reason: "required for transition to " + tr.To}
+		}
+		return apperror.Validation("task is missing fields required for the transition", fields...)
+	}
+
+	userID, _ := requser.FromContext(ctx)
+	if !tr.Permits(models.TaskRoles(t.After, userID)) {
+		return apperror.Forbidden(apperror.CodeForbidden,
+			fmt.Sprintf("transition to %s requires role %s", tr.To, strings.Join(tr.Roles, " or ")))
+	}
+	return nil
+}
+
+// LoadWorkflow статусы и переходы доски
+func LoadWorkflow(ctx context.Context, q Querier, boardID uuid.UUID) (*models.Workflow, error) {
+	rows, err := q.Query(ctx, `SELECT key, name, position, final FROM board_statuses
+                               WHERE board_id = $1 ORDER BY position`, boardID)
+	if err != nil {
+		return nil, err
+	}
+	statuses, err := pgx.CollectRows(rows, pgx.RowToStructByName[models.BoardStatus])
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err = q.Query(ctx, `SELECT from_status, to_status, required_fields, require_assignee, roles
+                              FROM board_transitions WHERE board_id = $1
+                              ORDER BY from_status, to_status`, boardID)
+	if err != nil {
+		return nil, err
+	}
+	transitions, err := pgx.CollectRows(rows, pgx.RowToStructByName[models.BoardTransition])
+	if err != nil {
+		return nil, err
+	}
+
+	w := &models.Workflow{Statuses: statuses, Transitions: transitions}
+	if w.Statuses == nil {
+		w.Statuses = []models.BoardStatus{}
+	}
+	if w.Transitions == nil {
+		w.Transitions = []models.BoardTransition{}
+	}
+	return w, nil
+}
+
+// Available переходы из текущего статуса задачи с признаком выполнения условий
+// для пользователя из контекста (см. requser.WithUser)
+func Available(ctx context.Context, q Querier, task *models.Task) ([]models.TaskTransition, error) {
+	w, err := LoadWorkflow(ctx, q, task.BoardID)
+	if err != nil {
+		return nil, err
+	}
+	userID, _ := requser.FromContext(ctx)
+	roles := models.TaskRoles(task, userID)
+	current, _ := w.Status(task.Status)
+
+	var blocking []uuid.UUID
+	blockersLoaded := false
+
+	result := []models.TaskTransition{}
+	for _, to := range w.Next(task.Status) {
+		s, _ := w.Status(to)
+		item := models.TaskTransition{To: s.Key, Name: s.Name, Final: s.Final,
+			RequiredFields: []string{}, Roles: []string{}}
+		if tr := w.Transition(task.Status, to); tr != nil {
+			item.RequiredFields, item.RequireAssignee, item.Roles = tr.RequiredFields, tr.RequireAssignee, tr.Roles
+			item.MissingFields = tr.MissingFields(task)
+			item.Forbidden = !tr.Permits(roles)
+		}
+		if s.Final && !current.Final {
+			if !blockersLoaded {
+				if blocking, err = unfinishedBlockers(ctx, q, task.ID); err != nil {
+					return nil, err
+				}
+				blockersLoaded = true
+			}
+			item.BlockingTasks = blocking
+		}
+		item.Available = len(item.MissingFields) == 0 && !item.Forbidden && len(item.BlockingTasks) == 0
+		result = append(result, item)
+	}
+	return result, nil
+}
+
+// BlockersDone запрещает переводить задачу в завершающий статус, пока не завершены задачи,
+// которые её блокируют (blocks), и её подзадачи (subtask_of)
+type BlockersDone struct{}
+
+func (BlockersDone) Check(ctx context.Context, tx pgx.Tx, t Transition) error {
+	completes, err := Completes(ctx, tx, t)
+	if err != nil || !completes {
+		return err
+	}
+	blocking, err := unfinishedBlockers(ctx, tx, t.After.ID)
+	if err != nil {
+		return err
+	}
+	if len(blocking) > 0 {
+		return &BlockedError{TaskID: t.After.ID, Status: t.After.Status, Blocking: blocking}
+	}
+	return nil
+}
+
+// unfinishedBlockers незавершённые блокирующие задачи и подзадачи задачи id; задачи в корзине не блокируют
+func unfinishedBlockers(ctx context.Context, q Querier, id uuid.UUID) ([]uuid.UUID, error) {
+	query := `SELECT t.id FROM task_dependencies d
+              JOIN tasks t ON t.id = CASE WHEN d.relation = $2 THEN d.task_id ELSE d.dependent_task_id END
+              JOIN board_statuses s ON s.board_id = t.board_id AND s.key = t.status
+              WHERE ((d.relation = $2 AND d.dependent_task_id = $1) OR (d.relation = $3 AND d.task_id = $1))
+                AND NOT s.final AND t.deleted_at IS NULL
+              ORDER BY t.created_at, t.id`
+	rows, err := q.Query(ctx, query, id, models.RelationBlocks, models.RelationSubtaskOf)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowTo[uuid.UUID])
+}
+
+// completeParents обрабатывает родителей задачи childID, у которых завершились все подзадачи.
+// При политике advance родитель переводится в первый завершающий статус своей доски
+// через Advancer (если правила это разрешают), и обработка рекурсивно продолжается
+// для его родителей.
+func (e *Engine) completeParents(ctx context.Context, tx pgx.Tx, childID uuid.UUID) ([]Event, error) {
+	rows, err := tx.Query(ctx, `SELECT d.task_id FROM task_dependencies d
+                                JOIN tasks p ON p.id = d.task_id
+                                WHERE d.dependent_task_id = $1 AND d.relation = $2 AND p.deleted_at IS NULL AND p.archived_at IS NULL`,
+		childID, models.RelationSubtaskOf)
+	if err != nil {
+		return nil, err
+	}
+	parents, err := pgx.CollectRows(rows, pgx.RowTo[uuid.UUID])
+	if err != nil {
+		return nil, err
+	}
+
+	var events []Event
+	for _, parentID := range parents {
+		// Блокировка родителя упорядочивает параллельное завершение его подзадач:
+		// последняя из них увидит остальные завершёнными
+		var boardID uuid.UUID
+		var status string
+		err := tx.QueryRow(ctx, `SELECT board_id, status FROM tasks WHERE id = $1 FOR UPDATE`, parentID).
+			Scan(&boardID, &status)
+		if err != nil {
+			return nil, err
+		}
+		final, err := isFinal(ctx, tx, boardID, status)
+		if err != nil {
+			return nil, err
+		}
+		if final {
+			continue
+		}
+
+		var pending bool
+		err = tx.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM task_dependencies d
+                                JOIN tasks t ON t.id = d.dependent_task_id
+                                JOIN board_statuses s ON s.board_id = t.board_id AND s.key = t.status
+                                WHERE d.task_id = $1 AND d.relation = $2 AND NOT s.final AND t.deleted_at IS NULL)`,
+			parentID, models.RelationSubtaskOf).Scan(&pending)
+		if err != nil {
+			return nil, err
+		}
+		if pending {
+			continue
+		}
+
+		if e.ParentPolicy != ParentAdvance || e.Advancer == nil {
+			events = append(events, Event{Kind: EventParentReady, TaskID: parentID, Cause: childID})
+			continue
+		}
+
+		advanced, err := e.Advancer.AdvanceTask(ctx, tx, parentID)
+		if refusesAdvance(err) {
+			advanced, err = false, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if !advanced {
+			// Родителя держат другие блокирующие задачи, условия перехода доски или
+			// WIP-лимит колонки: только сообщаем, что подзадачи готовы
+			events = append(events, Event{Kind: EventParentReady, TaskID: parentID, Cause: childID})
+			continue
+		}
+		events = append(events, Event{Kind: EventParentCompleted, TaskID: parentID, Cause: childID})
+
+		more, err := e.completeParents(ctx, tx, parentID)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, more...)
+	}
+	return events, nil
+}
+
+// refusesAdvance ошибки правил и WIP-лимитов, при которых родитель не завершается автоматически
+func refusesAdvance(err error) bool {
+	var blocked *BlockedError
+	var denied *TransitionError
+	var wip *models.WIPLimitError
+	var appErr *apperror.Error
+	return errors.As(err, &blocked) || errors.As(err, &denied) || errors.As(err, &wip) ||
+		(errors.As(err, &appErr) && (appErr.Kind == apperror.KindValidation || appErr.Kind == apperror.KindForbidden))
+}
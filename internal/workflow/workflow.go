@@ -0,0 +1,168 @@
+// Package workflow проверяет переходы задач между статусами. Правила вызываются
+// репозиторием внутри транзакции изменения задачи (PUT, PATCH, move), поэтому видят
+// согласованное состояние связей и могут отменить изменение.
+//
+// Статусы и граф переходов задаёт доска (models.Workflow); задача в статусе с флагом
+// final считается завершённой.
+package workflow
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/TrueSmartcomm/backend/internal/models"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// Querier общая часть пула и транзакции: правила работают в транзакции,
+// а список доступных переходов читается без неё
+type Querier interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// Transition изменение задачи: состояние до и после
+type Transition struct {
+	Before *models.Task
+	After  *models.Task
+}
+
+// Completes сообщает, что задача переходит в завершающий (final) статус своей доски
+func Completes(ctx context.Context, q Querier, t Transition) (bool, error) {
+	after, err := isFinal(ctx, q, t.After.BoardID, t.After.Status)
+	if err != nil || !after {
+		return false, err
+	}
+	before, err := isFinal(ctx, q, t.Before.BoardID, t.Before.Status)
+	return !before, err
+}
+
+// isFinal сообщает, является ли статус доски завершающим
+func isFinal(ctx context.Context, q Querier, boardID uuid.UUID, status string) (bool, error) {
+	var final bool
+	err := q.QueryRow(ctx, `SELECT final FROM board_statuses WHERE board_id = $1 AND key = $2`, boardID, status).Scan(&final)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	return final, err
+}
+
+// TrackCompletion записывает в completed_at время перехода задачи в завершающий статус
+// и очищает его при возврате в незавершённый. Пока задача переходит между завершающими
+// статусами, время сохраняется. Вызывается в транзакции после записи статуса.
+func TrackCompletion(ctx context.Context, tx pgx.Tx, task *models.Task) error {
+	err := tx.QueryRow(ctx, `UPDATE tasks t SET completed_at = CASE WHEN s.final THEN now() END
+                             FROM board_statuses s
+                             WHERE t.id = $1 AND s.board_id = t.board_id AND s.key = t.status
+                               AND (t.completed_at IS NULL) = s.final
+                             RETURNING t.completed_at`, task.ID).Scan(&task.CompletedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil
+	}
+	return err
+}
+
+// Rule правило перехода. Ошибка отменяет изменение задачи.
+type Rule interface {
+	Check(ctx context.Context, tx pgx.Tx, t Transition) error
+}
+
+// Политики обработки родительской задачи, у которой завершилась последняя подзадача
+const (
+	ParentNone    = ""        // ничего не делать
+	ParentNotify  = "notify"  // отправить событие ParentReady
+	ParentAdvance = "advance" // перевести родителя в done, если правила это разрешают
+)
+
+// Типы событий
+const (
+	EventParentReady     = "parent_ready"     // все подзадачи родителя завершены
+	EventParentCompleted = "parent_completed" // родитель автоматически переведён в done
+)
+
+// Event событие, возникшее при переходе. Отправляется после фиксации транзакции.
+type Event struct {
+	Kind   string
+	TaskID uuid.UUID // родительская задача
+	Cause  uuid.UUID // подзадача, завершение которой вызвало событие
+}
+
+// Notifier получатель событий
+type Notifier interface {
+	Notify(ctx context.Context, e Event)
+}
+
+// LogNotifier пишет события в лог
+type LogNotifier struct{}
+
+func (LogNotifier) Notify(_ context.Context, e Event) {
+	log.Printf("[INFO] workflow: %s task=%s cause=%s", e.Kind, e.TaskID, e.Cause)
+}
+
+// Advancer переводит готовую родительскую задачу в завершающий статус. Реализуется
+// репозиторием задач, чтобы автоматический перевод записывался так же, как ручной:
+// с проверкой правил Engine и WIP-лимитов колонки и записью в историю задачи.
+type Advancer interface {
+	// AdvanceTask переводит задачу id в первый завершающий статус её доски в транзакции tx.
+	// false — на доске нет подходящего места. Ошибки правил (см. Check) и *models.WIPLimitError
+	// означают, что перевод запрещён; до них в транзакции ничего не записывается.
+	AdvanceTask(ctx context.Context, tx pgx.Tx, id uuid.UUID) (bool, error)
+}
+
+// Engine набор правил и политика для родительских задач
+type Engine struct {
+	Rules        []Rule
+	ParentPolicy string
+	Notifier     Notifier
+	// Advancer выполняет политику advance; nil — родитель только получает событие parent_ready
+	Advancer Advancer
+}
+
+// New движок с правилами по умолчанию: смена статуса должна быть разрешена графом
+// переходов доски, а задачу нельзя завершить, пока не завершены её блокирующие
+// задачи и подзадачи
+func New(parentPolicy string) *Engine {
+	return &Engine{
+		Rules:        []Rule{BoardTransitions{}, BlockersDone{}},
+		ParentPolicy: parentPolicy,
+		Notifier:     LogNotifier{},
+	}
+}
+
+// Check проверяет переход по всем правилам. Nil-движок разрешает любой переход.
+func (e *Engine) Check(ctx context.Context, tx pgx.Tx, t Transition) error {
+	if e == nil {
+		return nil
+	}
+	for _, rule := range e.Rules {
+		if err := rule.Check(ctx, tx, t); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// After выполняет последствия уже записанного перехода (в той же транзакции)
+// и возвращает события для Notify
+func (e *Engine) After(ctx context.Context, tx pgx.Tx, t Transition) ([]Event, error) {
+	if e == nil || e.ParentPolicy == ParentNone {
+		return nil, nil
+	}
+	completes, err := Completes(ctx, tx, t)
+	if err != nil || !completes {
+		return nil, err
+	}
+	return e.completeParents(ctx, tx, t.After.ID)
+}
+
+// Notify отправляет события после фиксации транзакции
+func (e *Engine) Notify(ctx context.Context, events []Event) {
+	if e == nil || e.Notifier == nil {
+		return
+	}
+	for _, ev := range events {
+		e.Notifier.Notify(ctx, ev)
+	}
+}
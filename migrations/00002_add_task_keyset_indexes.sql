@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+-- составные индексы для keyset-пагинации: (ключ сортировки, id)
+CREATE INDEX idx_tasks_created_at_id ON tasks(created_at, id);
+CREATE INDEX idx_tasks_updated_at_id ON tasks(updated_at, id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_tasks_updated_at_id;
+DROP INDEX IF EXISTS idx_tasks_created_at_id;
+-- +goose StatementEnd
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+-- версия задачи для оптимистичной блокировки (ETag / If-Match),
+-- увеличивается при каждом изменении
+ALTER TABLE tasks ADD COLUMN version BIGINT NOT NULL DEFAULT 1;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE tasks DROP COLUMN IF EXISTS version;
+-- +goose StatementEnd
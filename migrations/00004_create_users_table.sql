@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE users (
+    id SERIAL PRIMARY KEY,
+    login VARCHAR(50) NOT NULL,
+    email VARCHAR(255) NOT NULL,
+    password_hash VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- имена ограничений используются в UserRepository.CreateUser для кодов login_taken / email_taken;
+-- уникальные индексы покрывают поиск в GetUserByLogin и GetUserByEmail
+ALTER TABLE users ADD CONSTRAINT users_login_key UNIQUE (login);
+ALTER TABLE users ADD CONSTRAINT users_email_key UNIQUE (email);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS users;
+-- +goose StatementEnd
@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE refresh_tokens (
+    id BIGSERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    token VARCHAR(255) NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- поиск и удаление токена по значению (GetRefreshToken, DeleteRefreshToken)
+ALTER TABLE refresh_tokens ADD CONSTRAINT refresh_tokens_token_key UNIQUE (token);
+-- каскадное удаление токенов пользователя
+CREATE INDEX idx_refresh_tokens_user_id ON refresh_tokens(user_id);
+-- очистка просроченных токенов
+CREATE INDEX idx_refresh_tokens_expires_at ON refresh_tokens(expires_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS refresh_tokens;
+-- +goose StatementEnd
@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+-- task_id — родительская задача, dependent_task_id — подзадача
+CREATE TABLE task_dependencies (
+    task_id UUID NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    dependent_task_id UUID NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    -- ключ нужен для ON CONFLICT (task_id, dependent_task_id) в AddDependency
+    -- и покрывает выборку подзадач (GetSubTasks)
+    PRIMARY KEY (task_id, dependent_task_id)
+);
+
+-- выборка родительских задач (GetParentTasks) и каскад при удалении подзадачи
+CREATE INDEX idx_task_dependencies_dependent_task_id ON task_dependencies(dependent_task_id);
+
+ALTER TABLE task_dependencies ADD CONSTRAINT chk_no_self_dependency CHECK (task_id <> dependent_task_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS task_dependencies;
+-- +goose StatementEnd
@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+-- тип связи между задачами; существующие связи были подзадачами
+ALTER TABLE task_dependencies ADD COLUMN relation VARCHAR(20) NOT NULL DEFAULT 'subtask_of';
+ALTER TABLE task_dependencies ADD CONSTRAINT chk_relation
+    CHECK (relation IN ('blocks', 'subtask_of', 'relates_to', 'duplicates'));
+
+-- между двумя задачами может быть несколько связей разных типов
+ALTER TABLE task_dependencies DROP CONSTRAINT task_dependencies_pkey;
+ALTER TABLE task_dependencies ADD PRIMARY KEY (task_id, dependent_task_id, relation);
+
+-- обход графа одного типа связей при проверке циклов
+CREATE INDEX idx_task_dependencies_relation_task_id ON task_dependencies(relation, task_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_task_dependencies_relation_task_id;
+-- при откате остаётся по одной связи на пару задач
+DELETE FROM task_dependencies d
+    USING task_dependencies o
+    WHERE d.task_id = o.task_id AND d.dependent_task_id = o.dependent_task_id AND d.relation > o.relation;
+ALTER TABLE task_dependencies DROP CONSTRAINT task_dependencies_pkey;
+ALTER TABLE task_dependencies ADD PRIMARY KEY (task_id, dependent_task_id);
+ALTER TABLE task_dependencies DROP CONSTRAINT IF EXISTS chk_relation;
+ALTER TABLE task_dependencies DROP COLUMN IF EXISTS relation;
+-- +goose StatementEnd
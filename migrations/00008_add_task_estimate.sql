@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+-- оценка трудоёмкости в часах для расчёта расписания и критического пути
+ALTER TABLE tasks ADD COLUMN estimate_hours NUMERIC(8,2);
+ALTER TABLE tasks ADD CONSTRAINT chk_estimate_hours CHECK (estimate_hours >= 0);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE tasks DROP CONSTRAINT IF EXISTS chk_estimate_hours;
+ALTER TABLE tasks DROP COLUMN IF EXISTS estimate_hours;
+-- +goose StatementEnd
@@ -0,0 +1,75 @@
+-- +goose Up
+-- +goose StatementBegin
+-- доски (проекты): у каждой свой упорядоченный набор колонок
+CREATE TABLE boards (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    is_default BOOLEAN NOT NULL DEFAULT false,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+-- доска по умолчанию одна: в неё попадают задачи, созданные без board_id
+CREATE UNIQUE INDEX idx_boards_default ON boards(is_default) WHERE is_default;
+
+-- key — неизменяемый идентификатор колонки внутри доски, он же kanban_space задач
+CREATE TABLE board_columns (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    board_id UUID NOT NULL REFERENCES boards(id) ON DELETE CASCADE,
+    key VARCHAR(50) NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    position INTEGER NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+ALTER TABLE board_columns ADD CONSTRAINT board_columns_board_id_key_key UNIQUE (board_id, key);
+-- при перестановке колонок позиции сдвигаются одним UPDATE, поэтому проверка отложенная
+ALTER TABLE board_columns ADD CONSTRAINT board_columns_board_id_position_key
+    UNIQUE (board_id, position) DEFERRABLE INITIALLY IMMEDIATE;
+-- цель составного внешнего ключа tasks (column_id, board_id)
+ALTER TABLE board_columns ADD CONSTRAINT board_columns_id_board_id_key UNIQUE (id, board_id);
+ALTER TABLE board_columns ADD CONSTRAINT chk_column_position CHECK (position >= 0);
+
+-- существующие задачи переносятся на доску по умолчанию с прежними пространствами
+INSERT INTO boards (name, is_default) VALUES ('Default', true);
+INSERT INTO board_columns (board_id, key, name, position)
+    SELECT b.id, c.key, c.name, c.position
+    FROM boards b,
+         (VALUES ('backlog', 'Backlog', 0), ('todo', 'To Do', 1), ('in_progress', 'In Progress', 2),
+                 ('review', 'Review', 3), ('done', 'Done', 4)) AS c(key, name, position)
+    WHERE b.is_default;
+
+ALTER TABLE tasks ADD COLUMN board_id UUID;
+ALTER TABLE tasks ADD COLUMN column_id UUID;
+UPDATE tasks t SET board_id = c.board_id, column_id = c.id
+    FROM board_columns c JOIN boards b ON b.id = c.board_id
+    WHERE b.is_default AND c.key = t.kanban_space;
+ALTER TABLE tasks ALTER COLUMN board_id SET NOT NULL;
+ALTER TABLE tasks ALTER COLUMN column_id SET NOT NULL;
+
+-- задачу нельзя оставить без доски или колонки: непустые доски и колонки не удаляются;
+-- составной ключ не даёт поставить задачу в колонку чужой доски
+ALTER TABLE tasks ADD CONSTRAINT tasks_board_id_fkey
+    FOREIGN KEY (board_id) REFERENCES boards(id) ON DELETE RESTRICT;
+ALTER TABLE tasks ADD CONSTRAINT tasks_column_id_fkey
+    FOREIGN KEY (column_id, board_id) REFERENCES board_columns(id, board_id) ON DELETE RESTRICT;
+CREATE INDEX idx_tasks_board_id_column_id ON tasks(board_id, column_id);
+
+-- набор пространств теперь задаётся колонками доски
+ALTER TABLE tasks DROP CONSTRAINT IF EXISTS chk_kanban_space;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+-- колонки, которых не было в фиксированном наборе, возвращаются в backlog
+UPDATE tasks SET kanban_space = 'backlog'
+    WHERE kanban_space NOT IN ('backlog', 'todo', 'in_progress', 'review', 'done');
+ALTER TABLE tasks ADD CONSTRAINT chk_kanban_space
+    CHECK (kanban_space IN ('backlog', 'todo', 'in_progress', 'review', 'done'));
+DROP INDEX IF EXISTS idx_tasks_board_id_column_id;
+ALTER TABLE tasks DROP CONSTRAINT IF EXISTS tasks_column_id_fkey;
+ALTER TABLE tasks DROP CONSTRAINT IF EXISTS tasks_board_id_fkey;
+ALTER TABLE tasks DROP COLUMN IF EXISTS column_id;
+ALTER TABLE tasks DROP COLUMN IF EXISTS board_id;
+DROP TABLE IF EXISTS board_columns;
+DROP TABLE IF EXISTS boards;
+-- +goose StatementEnd
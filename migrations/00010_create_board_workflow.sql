@@ -0,0 +1,58 @@
+-- +goose Up
+-- +goose StatementBegin
+-- статусы доски; final — задача в этом статусе считается завершённой
+CREATE TABLE board_statuses (
+    board_id UUID NOT NULL REFERENCES boards(id) ON DELETE CASCADE,
+    key VARCHAR(50) NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    position INTEGER NOT NULL,
+    final BOOLEAN NOT NULL DEFAULT false,
+    PRIMARY KEY (board_id, key)
+);
+
+-- разрешённые переходы между статусами доски и условия перехода;
+-- доска без переходов разрешает любую смену статуса
+CREATE TABLE board_transitions (
+    board_id UUID NOT NULL,
+    from_status VARCHAR(50) NOT NULL,
+    to_status VARCHAR(50) NOT NULL,
+    required_fields TEXT[] NOT NULL DEFAULT '{}',
+    require_assignee BOOLEAN NOT NULL DEFAULT false,
+    roles TEXT[] NOT NULL DEFAULT '{}',
+    PRIMARY KEY (board_id, from_status, to_status),
+    CONSTRAINT board_transitions_from_fkey FOREIGN KEY (board_id, from_status)
+        REFERENCES board_statuses(board_id, key) ON DELETE CASCADE,
+    CONSTRAINT board_transitions_to_fkey FOREIGN KEY (board_id, to_status)
+        REFERENCES board_statuses(board_id, key) ON DELETE CASCADE,
+    CONSTRAINT chk_transition_loop CHECK (from_status <> to_status)
+);
+
+-- колонка может требовать определённый статус задач в ней. NO ACTION, а не RESTRICT:
+-- при удалении доски колонки и статусы удаляются каскадно одной командой
+ALTER TABLE board_columns ADD COLUMN status VARCHAR(50);
+ALTER TABLE board_columns ADD CONSTRAINT board_columns_status_fkey
+    FOREIGN KEY (board_id, status) REFERENCES board_statuses(board_id, key);
+
+-- все существующие доски получают прежний фиксированный набор статусов без ограничений переходов
+INSERT INTO board_statuses (board_id, key, name, position, final)
+    SELECT b.id, s.key, s.name, s.position, s.final
+    FROM boards b,
+         (VALUES ('todo', 'To Do', 0, false), ('in_progress', 'In Progress', 1, false),
+                 ('review', 'Review', 2, false), ('done', 'Done', 3, true)) AS s(key, name, position, final);
+
+-- статус задачи должен быть статусом её доски
+ALTER TABLE tasks DROP CONSTRAINT IF EXISTS chk_status;
+ALTER TABLE tasks ADD CONSTRAINT tasks_status_fkey
+    FOREIGN KEY (board_id, status) REFERENCES board_statuses(board_id, key) ON DELETE RESTRICT;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE tasks DROP CONSTRAINT IF EXISTS tasks_status_fkey;
+UPDATE tasks SET status = 'todo' WHERE status NOT IN ('todo', 'in_progress', 'review', 'done');
+ALTER TABLE tasks ADD CONSTRAINT chk_status CHECK (status IN ('todo', 'in_progress', 'review', 'done'));
+ALTER TABLE board_columns DROP CONSTRAINT IF EXISTS board_columns_status_fkey;
+ALTER TABLE board_columns DROP COLUMN IF EXISTS status;
+DROP TABLE IF EXISTS board_transitions;
+DROP TABLE IF EXISTS board_statuses;
+-- +goose StatementEnd
@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+-- ручной порядок карточек в колонке: ключ fractional indexing (пакет internal/rank),
+-- сравнивается побайтно, поэтому COLLATE "C"
+ALTER TABLE tasks ADD COLUMN rank TEXT COLLATE "C";
+
+-- существующие карточки сохраняют прежний порядок списка: новые сверху
+UPDATE tasks t SET rank = r.rank
+FROM (SELECT id, lpad(row_number() OVER (PARTITION BY column_id ORDER BY created_at DESC, id)::text, 10, '0') || 'V' AS rank
+      FROM tasks) r
+WHERE t.id = r.id;
+
+ALTER TABLE tasks ALTER COLUMN rank SET NOT NULL;
+CREATE INDEX idx_tasks_column_rank ON tasks (column_id, rank);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_tasks_column_rank;
+ALTER TABLE tasks DROP COLUMN IF EXISTS rank;
+-- +goose StatementEnd
@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+-- WIP-лимиты колонки: всего задач и задач одного исполнителя; NULL — без лимита.
+-- hard — превышающее лимит перемещение отклоняется, soft — выполняется с предупреждением
+ALTER TABLE board_columns
+    ADD COLUMN wip_limit INTEGER CHECK (wip_limit > 0),
+    ADD COLUMN wip_limit_per_assignee INTEGER CHECK (wip_limit_per_assignee > 0),
+    ADD COLUMN wip_mode VARCHAR(10) NOT NULL DEFAULT 'hard' CHECK (wip_mode IN ('hard', 'soft'));
+
+-- подсчёт задач исполнителя в колонке
+CREATE INDEX idx_tasks_column_assignee ON tasks (column_id, assigned_to);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_tasks_column_assignee;
+ALTER TABLE board_columns
+    DROP COLUMN IF EXISTS wip_mode,
+    DROP COLUMN IF EXISTS wip_limit_per_assignee,
+    DROP COLUMN IF EXISTS wip_limit;
+-- +goose StatementEnd
@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+-- история изменений задачи: одно событие на изменение, changes — список полей
+-- со старым и новым значением. Без внешнего ключа на tasks: история удалённой задачи сохраняется
+CREATE TABLE task_history (
+    id BIGSERIAL PRIMARY KEY,
+    task_id UUID NOT NULL,
+    action VARCHAR(30) NOT NULL,
+    actor_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    changes JSONB NOT NULL DEFAULT '[]',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT chk_task_history_action CHECK (action IN ('created', 'updated', 'moved', 'deleted',
+                                                         'dependency_added', 'dependency_removed'))
+);
+
+-- страницы истории задачи, новые события первыми
+CREATE INDEX idx_task_history_task_id ON task_history (task_id, id DESC);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS task_history;
+-- +goose StatementEnd
@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+-- удалённая задача остаётся в корзине до deleted_at + срок хранения; связи задачи не удаляются
+-- и снова становятся видимыми при восстановлении
+ALTER TABLE tasks ADD COLUMN deleted_at TIMESTAMP;
+CREATE INDEX idx_tasks_deleted_at ON tasks (deleted_at) WHERE deleted_at IS NOT NULL;
+
+ALTER TABLE task_history DROP CONSTRAINT chk_task_history_action;
+ALTER TABLE task_history ADD CONSTRAINT chk_task_history_action
+    CHECK (action IN ('created', 'updated', 'moved', 'deleted', 'restored', 'purged',
+                      'dependency_added', 'dependency_removed'));
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM task_history WHERE action IN ('restored', 'purged');
+ALTER TABLE task_history DROP CONSTRAINT chk_task_history_action;
+ALTER TABLE task_history ADD CONSTRAINT chk_task_history_action
+    CHECK (action IN ('created', 'updated', 'moved', 'deleted', 'dependency_added', 'dependency_removed'));
+
+-- без корзины удалённые задачи удаляются окончательно
+DELETE FROM tasks WHERE deleted_at IS NOT NULL;
+DROP INDEX IF EXISTS idx_tasks_deleted_at;
+ALTER TABLE tasks DROP COLUMN IF EXISTS deleted_at;
+-- +goose StatementEnd
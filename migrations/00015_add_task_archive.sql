@@ -0,0 +1,38 @@
+-- +goose Up
+-- +goose StatementBegin
+-- completed_at — когда задача перешла в завершающий статус доски; по нему задачи архивируются
+-- автоматически. Архивные задачи не показываются на доске, но доступны по id и в поиске
+ALTER TABLE tasks ADD COLUMN completed_at TIMESTAMP;
+ALTER TABLE tasks ADD COLUMN archived_at TIMESTAMP;
+
+-- для уже завершённых задач точного времени нет, берём время последнего изменения
+UPDATE tasks t SET completed_at = t.updated_at
+FROM board_statuses s
+WHERE s.board_id = t.board_id AND s.key = t.status AND s.final;
+
+-- выборки активной доски и порядок карточек в колонках не читают архив и корзину
+CREATE INDEX idx_tasks_active_board ON tasks (board_id, column_id, rank)
+    WHERE archived_at IS NULL AND deleted_at IS NULL;
+-- кандидаты на автоматическую архивацию
+CREATE INDEX idx_tasks_completed_at ON tasks (completed_at)
+    WHERE completed_at IS NOT NULL AND archived_at IS NULL AND deleted_at IS NULL;
+
+ALTER TABLE task_history DROP CONSTRAINT chk_task_history_action;
+ALTER TABLE task_history ADD CONSTRAINT chk_task_history_action
+    CHECK (action IN ('created', 'updated', 'moved', 'deleted', 'restored', 'purged',
+                      'archived', 'unarchived', 'dependency_added', 'dependency_removed'));
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM task_history WHERE action IN ('archived', 'unarchived');
+ALTER TABLE task_history DROP CONSTRAINT chk_task_history_action;
+ALTER TABLE task_history ADD CONSTRAINT chk_task_history_action
+    CHECK (action IN ('created', 'updated', 'moved', 'deleted', 'restored', 'purged',
+                      'dependency_added', 'dependency_removed'));
+
+DROP INDEX IF EXISTS idx_tasks_completed_at;
+DROP INDEX IF EXISTS idx_tasks_active_board;
+ALTER TABLE tasks DROP COLUMN IF EXISTS archived_at;
+ALTER TABLE tasks DROP COLUMN IF EXISTS completed_at;
+-- +goose StatementEnd
@@ -0,0 +1,62 @@
+-- +goose Up
+-- +goose StatementBegin
+-- владелец и исполнитель задачи — пользователи, а не произвольные строки. Владелец может
+-- отсутствовать только у задач, чей владелец не нашёлся при переносе или удалён
+ALTER TABLE tasks ADD COLUMN owner_id INTEGER REFERENCES users(id) ON DELETE SET NULL;
+ALTER TABLE tasks ADD COLUMN assignee_id INTEGER REFERENCES users(id) ON DELETE SET NULL;
+
+-- строки переносятся по логину, затем по email
+UPDATE tasks t SET owner_id = COALESCE(
+    (SELECT u.id FROM users u WHERE u.login = t.owner),
+    (SELECT u.id FROM users u WHERE lower(u.email) = lower(t.owner)));
+UPDATE tasks t SET assignee_id = COALESCE(
+    (SELECT u.id FROM users u WHERE u.login = t.assigned_to),
+    (SELECT u.id FROM users u WHERE lower(u.email) = lower(t.assigned_to)))
+WHERE t.assigned_to IS NOT NULL;
+
+-- значения, которым не нашлось пользователя, остаются в истории задачи
+INSERT INTO task_history (task_id, action, changes)
+SELECT id, 'updated', changes FROM (
+    SELECT id,
+           CASE WHEN owner_id IS NULL
+                THEN jsonb_build_array(jsonb_build_object('field', 'owner', 'old', owner, 'new', NULL))
+                ELSE '[]'::jsonb END ||
+           CASE WHEN assigned_to IS NOT NULL AND assignee_id IS NULL
+                THEN jsonb_build_array(jsonb_build_object('field', 'assigned_to', 'old', assigned_to, 'new', NULL))
+                ELSE '[]'::jsonb END AS changes
+    FROM tasks) c
+WHERE changes <> '[]'::jsonb;
+
+DROP INDEX IF EXISTS idx_tasks_column_assignee;
+ALTER TABLE tasks DROP COLUMN owner;
+ALTER TABLE tasks DROP COLUMN assigned_to;
+
+CREATE INDEX idx_tasks_owner_id ON tasks (owner_id);
+CREATE INDEX idx_tasks_assignee_id ON tasks (assignee_id);
+-- WIP-лимит на исполнителя считает задачи исполнителя в колонке
+CREATE INDEX idx_tasks_column_assignee ON tasks (column_id, assignee_id);
+
+-- условие перехода ссылается на поле задачи по новому имени
+UPDATE board_transitions SET required_fields = array_replace(required_fields, 'assigned_to', 'assignee_id');
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+UPDATE board_transitions SET required_fields = array_replace(required_fields, 'assignee_id', 'assigned_to');
+
+ALTER TABLE tasks ADD COLUMN owner VARCHAR(255);
+ALTER TABLE tasks ADD COLUMN assigned_to VARCHAR(255);
+UPDATE tasks t SET owner = COALESCE((SELECT u.login FROM users u WHERE u.id = t.owner_id), ''),
+                   assigned_to = (SELECT u.login FROM users u WHERE u.id = t.assignee_id);
+ALTER TABLE tasks ALTER COLUMN owner SET NOT NULL;
+
+DROP INDEX IF EXISTS idx_tasks_column_assignee;
+DROP INDEX IF EXISTS idx_tasks_assignee_id;
+DROP INDEX IF EXISTS idx_tasks_owner_id;
+ALTER TABLE tasks DROP COLUMN owner_id;
+ALTER TABLE tasks DROP COLUMN assignee_id;
+
+CREATE INDEX idx_tasks_owner ON tasks (owner);
+CREATE INDEX idx_tasks_assigned_to ON tasks (assigned_to);
+CREATE INDEX idx_tasks_column_assignee ON tasks (column_id, assigned_to);
+-- +goose StatementEnd
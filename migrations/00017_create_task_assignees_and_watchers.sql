@@ -0,0 +1,49 @@
+-- +goose Up
+-- +goose StatementBegin
+-- у задачи может быть несколько исполнителей; tasks.assignee_id переносится сюда
+CREATE TABLE task_assignees (
+    task_id UUID NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    added_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (task_id, user_id)
+);
+-- фильтр по исполнителю, «мои задачи» и WIP-лимит на исполнителя
+CREATE INDEX idx_task_assignees_user_id ON task_assignees (user_id);
+
+-- наблюдатели задачи; владелец и исполнители добавляются автоматически
+CREATE TABLE task_watchers (
+    task_id UUID NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    added_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (task_id, user_id)
+);
+CREATE INDEX idx_task_watchers_user_id ON task_watchers (user_id);
+
+INSERT INTO task_assignees (task_id, user_id)
+SELECT id, assignee_id FROM tasks WHERE assignee_id IS NOT NULL;
+INSERT INTO task_watchers (task_id, user_id)
+SELECT id, owner_id FROM tasks WHERE owner_id IS NOT NULL
+UNION
+SELECT id, assignee_id FROM tasks WHERE assignee_id IS NOT NULL;
+
+DROP INDEX IF EXISTS idx_tasks_column_assignee;
+DROP INDEX IF EXISTS idx_tasks_assignee_id;
+ALTER TABLE tasks DROP COLUMN assignee_id;
+
+UPDATE board_transitions SET required_fields = array_replace(required_fields, 'assignee_id', 'assignees');
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+UPDATE board_transitions SET required_fields = array_replace(required_fields, 'assignees', 'assignee_id');
+
+-- из нескольких исполнителей остаётся назначенный первым
+ALTER TABLE tasks ADD COLUMN assignee_id INTEGER REFERENCES users(id) ON DELETE SET NULL;
+UPDATE tasks t SET assignee_id = (SELECT a.user_id FROM task_assignees a WHERE a.task_id = t.id
+                                  ORDER BY a.added_at, a.user_id LIMIT 1);
+CREATE INDEX idx_tasks_assignee_id ON tasks (assignee_id);
+CREATE INDEX idx_tasks_column_assignee ON tasks (column_id, assignee_id);
+
+DROP TABLE IF EXISTS task_watchers;
+DROP TABLE IF EXISTS task_assignees;
+-- +goose StatementEnd
@@ -0,0 +1,36 @@
+-- +goose Up
+-- +goose StatementBegin
+-- роль пользователя во всём рабочем пространстве действует на всех досках
+CREATE TABLE workspace_members (
+    user_id INTEGER PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    role TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT chk_workspace_member_role CHECK (role IN ('owner', 'admin', 'member', 'viewer'))
+);
+
+-- роль на отдельной доске; на доске действует старшая из ролей доски и рабочего пространства
+CREATE TABLE board_members (
+    board_id UUID NOT NULL REFERENCES boards(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (board_id, user_id),
+    CONSTRAINT chk_board_member_role CHECK (role IN ('owner', 'admin', 'member', 'viewer'))
+);
+-- доски, доступные пользователю (списки задач и досок)
+CREATE INDEX idx_board_members_user_id ON board_members (user_id);
+
+-- только для обновления баз с пользователями: до появления ролей все видели все доски,
+-- поэтому существующие пользователи сохраняют этот доступ, а самый ранний становится
+-- владельцем, чтобы было кому раздавать роли. На новой базе пользователей ещё нет и строк
+-- не добавляется: владельца назначает `app owner LOGIN` (cmd/app/owner.go), а регистрация
+-- выдаёт только DEFAULT_WORKSPACE_ROLE.
+INSERT INTO workspace_members (user_id, role)
+SELECT id, CASE WHEN id = (SELECT min(id) FROM users) THEN 'owner' ELSE 'member' END FROM users;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS board_members;
+DROP TABLE IF EXISTS workspace_members;
+-- +goose StatementEnd
@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+-- роль владельца задачи в условиях переходов отличается от роли owner рабочего пространства и доски
+UPDATE board_transitions SET roles = array_replace(roles, 'owner', 'task_owner');
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+UPDATE board_transitions SET roles = array_replace(roles, 'task_owner', 'owner');
+-- +goose StatementEnd
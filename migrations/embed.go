@@ -0,0 +1,9 @@
+// Package migrations содержит SQL-миграции схемы БД в формате goose.
+package migrations
+
+import "embed"
+
+// FS миграции, встроенные в бинарник
+//
+//go:embed *.sql
+var FS embed.FS
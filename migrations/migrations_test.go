@@ -0,0 +1,72 @@
+package migrations_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/TrueSmartcomm/backend/internal/migrate"
+	"github.com/TrueSmartcomm/backend/migrations"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/pressly/goose/v3"
+)
+
+// TestUpDownUp применяет все миграции, откатывает их по одной и применяет снова.
+// Нужна отдельная пустая база: TEST_DATABASE_URL=postgres://... go test ./migrations
+// Тест удаляет все объекты схемы, созданные миграциями.
+func TestUpDownUp(t *testing.T) {
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+	ctx := context.Background()
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+
+	m, err := migrate.New(pool, migrations.FS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.Close()
+
+	assertApplied := func(want bool) {
+		t.Helper()
+		statuses, err := m.Status(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(statuses) == 0 {
+			t.Fatal("no migrations found")
+		}
+		for _, st := range statuses {
+			if applied := st.AppliedAt != nil; applied != want {
+				t.Errorf("%s: applied = %v, want %v", st.Name, applied, want)
+			}
+		}
+	}
+
+	if err := m.Up(ctx); err != nil {
+		t.Fatalf("up: %v", err)
+	}
+	assertApplied(true)
+
+	for {
+		err := m.Down(ctx)
+		if errors.Is(err, goose.ErrNoNextVersion) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("down: %v", err)
+		}
+	}
+	assertApplied(false)
+
+	if err := m.Up(ctx); err != nil {
+		t.Fatalf("up after down: %v", err)
+	}
+	assertApplied(true)
+}